@@ -4,6 +4,7 @@ package call
 
 import (
 	"errors"
+	"slices"
 	"sync"
 	"time"
 )
@@ -26,6 +27,11 @@ const (
 	stateProbing
 )
 
+// String returns the state's name: "closed", "open" or "half-open".
+func (s State) String() string {
+	return stateName(s)
+}
+
 // Breaker defines the circuit breaker behavior. Consumers can provide their
 // own implementation (e.g., wrapping sony/gobreaker) via WithBreaker.
 type Breaker interface {
@@ -43,12 +49,31 @@ var breakers sync.Map
 // failures and short-circuits requests when the failure threshold is reached,
 // giving the downstream service time to recover.
 type CircuitBreaker struct {
+	name         string
 	mu           sync.Mutex
 	state        State
 	failures     int
 	threshold    int
 	resetTimeout time.Duration
 	lastFailure  time.Time
+	listeners    []*stateListener
+	forced       bool   // held open by ForceOpen
+	rejected     uint64 // requests rejected by Allow
+	opened       uint64 // transitions into StateOpen
+}
+
+// BreakerStats is a snapshot of a CircuitBreaker, returned by Stats.
+type BreakerStats struct {
+	State State
+	// Failures is the count of consecutive failures toward the threshold.
+	Failures int
+	// Rejected counts requests Allow has rejected since the breaker was
+	// created.
+	Rejected uint64
+	// Opened counts transitions into StateOpen, forced ones included.
+	Opened uint64
+	// Forced reports whether ForceOpen is holding the breaker open.
+	Forced bool
 }
 
 // GetBreaker returns an existing circuit breaker for the given name or creates
@@ -60,6 +85,7 @@ func GetBreaker(name string, threshold int, resetTimeout time.Duration) *Circuit
 	}
 
 	cb := &CircuitBreaker{
+		name:         name,
 		state:        StateClosed,
 		threshold:    threshold,
 		resetTimeout: resetTimeout,
@@ -73,8 +99,21 @@ func GetBreaker(name string, threshold int, resetTimeout time.Duration) *Circuit
 // nil when the request may proceed or ErrCircuitOpen when it must be rejected.
 func (cb *CircuitBreaker) Allow() error {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	from := cb.publicState()
+	err := cb.allowLocked()
+	if err != nil {
+		cb.rejected++
+	}
+	to, listeners := cb.transitionedLocked(from)
+	cb.mu.Unlock()
+	notify(listeners, from, to)
+	return err
+}
 
+func (cb *CircuitBreaker) allowLocked() error {
+	if cb.forced {
+		return ErrCircuitOpen
+	}
 	switch cb.state {
 	case StateClosed:
 		return nil
@@ -98,8 +137,14 @@ func (cb *CircuitBreaker) Allow() error {
 // internal state accordingly.
 func (cb *CircuitBreaker) Record(success bool) {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	from := cb.publicState()
+	cb.recordLocked(success)
+	to, listeners := cb.transitionedLocked(from)
+	cb.mu.Unlock()
+	notify(listeners, from, to)
+}
 
+func (cb *CircuitBreaker) recordLocked(success bool) {
 	switch cb.state {
 	case StateClosed:
 		if success {
@@ -128,12 +173,110 @@ func (cb *CircuitBreaker) Record(success bool) {
 func (cb *CircuitBreaker) State() State {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
+	return cb.publicState()
+}
+
+// ForceOpen opens the breaker and holds it open, rejecting every request
+// until ForceClose is called, whatever the reset timeout. Use it to shed
+// load from a downstream known to be failing.
+func (cb *CircuitBreaker) ForceOpen() {
+	cb.mu.Lock()
+	from := cb.publicState()
+	cb.state = StateOpen
+	cb.forced = true
+	cb.lastFailure = time.Now()
+	to, listeners := cb.transitionedLocked(from)
+	cb.mu.Unlock()
+	notify(listeners, from, to)
+}
+
+// ForceClose closes the breaker and clears its failure count, releasing a
+// ForceOpen. The breaker then trips again as usual once the threshold is
+// reached.
+func (cb *CircuitBreaker) ForceClose() {
+	cb.mu.Lock()
+	from := cb.publicState()
+	cb.state = StateClosed
+	cb.failures = 0
+	cb.forced = false
+	to, listeners := cb.transitionedLocked(from)
+	cb.mu.Unlock()
+	notify(listeners, from, to)
+}
+
+// Stats returns a snapshot of the breaker's state and counters.
+func (cb *CircuitBreaker) Stats() BreakerStats {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return BreakerStats{
+		State:    cb.publicState(),
+		Failures: cb.failures,
+		Rejected: cb.rejected,
+		Opened:   cb.opened,
+		Forced:   cb.forced,
+	}
+}
+
+// Name returns the name the breaker was registered under with GetBreaker.
+func (cb *CircuitBreaker) Name() string {
+	return cb.name
+}
+
+// stateListener wraps a function registered with OnStateChange, giving it
+// an identity to unregister by.
+type stateListener struct {
+	fn func(from, to State)
+}
+
+// OnStateChange registers fn to be called after every state transition, for
+// example to export metrics or log when a breaker opens. fn runs
+// synchronously on the goroutine that caused the transition, outside the
+// breaker's lock, so it must be fast. The internal probing state is
+// reported as StateHalfOpen, as with State. Call the returned function to
+// unregister fn; it is safe to call more than once.
+func (cb *CircuitBreaker) OnStateChange(fn func(from, to State)) (unregister func()) {
+	l := &stateListener{fn: fn}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	// Copy on write so that notify can run on a snapshot without the lock.
+	cb.listeners = append(cb.listeners[:len(cb.listeners):len(cb.listeners)], l)
+	return func() {
+		cb.mu.Lock()
+		defer cb.mu.Unlock()
+		cb.listeners = slices.DeleteFunc(slices.Clone(cb.listeners), func(other *stateListener) bool { return other == l })
+	}
+}
+
+// publicState returns the state as reported by State. Must be called with mu
+// held.
+func (cb *CircuitBreaker) publicState() State {
 	if cb.state == stateProbing {
 		return StateHalfOpen
 	}
 	return cb.state
 }
 
+// transitionedLocked counts an opening if the breaker has moved from from
+// into StateOpen, and returns the new state and the listeners to notify.
+// Must be called with mu held.
+func (cb *CircuitBreaker) transitionedLocked(from State) (State, []*stateListener) {
+	to := cb.publicState()
+	if to == StateOpen && from != StateOpen {
+		cb.opened++
+	}
+	return to, cb.listeners
+}
+
+// notify calls each listener if the state changed.
+func notify(listeners []*stateListener, from, to State) {
+	if from == to {
+		return
+	}
+	for _, l := range listeners {
+		l.fn(from, to)
+	}
+}
+
 // RemoveBreaker removes a named circuit breaker from the global registry,
 // allowing its memory to be reclaimed. Safe to call even if the name does
 // not exist. Use this when a downstream service is decommissioned or when
@@ -150,4 +293,7 @@ func (cb *CircuitBreaker) resetForTest() {
 	cb.state = StateClosed
 	cb.failures = 0
 	cb.lastFailure = time.Time{}
+	cb.forced = false
+	cb.rejected = 0
+	cb.opened = 0
 }
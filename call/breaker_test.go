@@ -1,6 +1,7 @@
 package call
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -78,3 +79,88 @@ func TestStateName(t *testing.T) {
 		}
 	}
 }
+
+func TestCircuitBreaker_OnStateChange(t *testing.T) {
+	name := uniqueBreakerName()
+	defer RemoveBreaker(name)
+	cb := GetBreaker(name, 1, 10*time.Millisecond)
+	if cb.Name() != name {
+		t.Fatalf("Name() = %q, want %q", cb.Name(), name)
+	}
+
+	var got []string
+	cb.OnStateChange(func(from, to State) {
+		got = append(got, from.String()+"->"+to.String())
+	})
+
+	cb.Record(true) // no transition
+	cb.Record(false)
+	time.Sleep(15 * time.Millisecond)
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("expected probe allow, got %v", err)
+	}
+	cb.Record(true)
+
+	want := []string{"closed->open", "open->half-open", "half-open->closed"}
+	if len(got) != len(want) {
+		t.Fatalf("transitions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("transitions = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCircuitBreaker_OnStateChangeUnregister(t *testing.T) {
+	name := uniqueBreakerName()
+	defer RemoveBreaker(name)
+	cb := GetBreaker(name, 1, time.Hour)
+
+	var first, second int
+	unregister := cb.OnStateChange(func(from, to State) { first++ })
+	cb.OnStateChange(func(from, to State) { second++ })
+	unregister()
+	unregister()
+
+	cb.Record(false)
+	if first != 0 || second != 1 {
+		t.Fatalf("calls = %d, %d; want the unregistered listener skipped and the other called once", first, second)
+	}
+}
+
+func TestCircuitBreaker_ForceOpenAndClose(t *testing.T) {
+	name := uniqueBreakerName()
+	defer RemoveBreaker(name)
+	cb := GetBreaker(name, 3, time.Millisecond)
+
+	var got []string
+	cb.OnStateChange(func(from, to State) {
+		got = append(got, from.String()+"->"+to.String())
+	})
+
+	cb.ForceOpen()
+	time.Sleep(5 * time.Millisecond) // past the reset timeout
+	for range 2 {
+		if err := cb.Allow(); !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Allow while forced open = %v, want ErrCircuitOpen", err)
+		}
+	}
+	if st := cb.Stats(); st.State != StateOpen || !st.Forced || st.Rejected != 2 || st.Opened != 1 {
+		t.Fatalf("Stats() = %+v, want forced open with 2 rejections and 1 opening", st)
+	}
+
+	cb.Record(false)
+	cb.ForceClose()
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("Allow after ForceClose = %v", err)
+	}
+	if st := cb.Stats(); st.State != StateClosed || st.Forced || st.Failures != 0 {
+		t.Fatalf("Stats() after ForceClose = %+v", st)
+	}
+
+	want := []string{"closed->open", "open->closed"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("transitions = %v, want %v", got, want)
+	}
+}
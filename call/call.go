@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"time"
 
 	chassis "github.com/ai8future/chassis-go/v11"
@@ -50,6 +51,10 @@ type Client struct {
 	retrier     *Retrier
 	breaker     Breaker
 	tokenSource TokenSource
+
+	maxRetryAfter time.Duration
+	retry429      bool
+	retryMethods  []string
 }
 
 // Option configures a Client.
@@ -66,6 +71,10 @@ func New(opts ...Option) *Client {
 	for _, o := range opts {
 		o(c)
 	}
+	if c.retrier != nil {
+		c.retrier.MaxRetryAfter = c.maxRetryAfter
+		c.retrier.Retry429 = c.retry429
+	}
 	return c
 }
 
@@ -89,6 +98,70 @@ func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
 			MaxAttempts: max(1, maxAttempts),
 			BaseDelay:   baseDelay,
 		}
+		c.retryMethods = nil
+	}
+}
+
+// Policy configures which failed requests are retried and for how long. See
+// WithRetryPolicy.
+type Policy struct {
+	// RetryOn decides whether an attempt is retried. It gets a nil response
+	// on a network error. Nil retries network errors and 5xx responses,
+	// and 429s as WithRetry429 and WithRetryAfter allow.
+	RetryOn func(*http.Response, error) bool
+	// Methods limits retries to these HTTP methods, e.g. the idempotent
+	// GET, HEAD, PUT and DELETE; requests with other methods are sent once.
+	// Nil retries every method.
+	Methods []string
+	// MaxElapsed bounds the time from the first attempt: no retry is made
+	// whose wait would end past it. Zero means no bound.
+	MaxElapsed time.Duration
+	// MaxAttempts is the most attempts, the first included. Zero means 3.
+	MaxAttempts int
+	// Backoff returns the wait before retry attempt+1, with attempt
+	// counting from 0. Nil backs off exponentially from 100ms with jitter.
+	// A honored Retry-After replaces it.
+	Backoff func(attempt int) time.Duration
+}
+
+// WithRetryPolicy enables retries governed by p, replacing WithRetry and its
+// fixed 5xx rule. WithRetry429 and WithRetryAfter still apply.
+//
+// The body constraint of WithRetry applies: requests with a body must
+// implement GetBody to be retried intact.
+func WithRetryPolicy(p Policy) Option {
+	return func(c *Client) {
+		attempts := p.MaxAttempts
+		if attempts == 0 {
+			attempts = 3
+		}
+		c.retrier = &Retrier{
+			MaxAttempts: max(1, attempts),
+			RetryOn:     p.RetryOn,
+			Backoff:     p.Backoff,
+			MaxElapsed:  p.MaxElapsed,
+		}
+		c.retryMethods = p.Methods
+	}
+}
+
+// WithRetryAfter makes retries honor the server's Retry-After header on 429
+// and 5xx responses, waiting the advised delay instead of the exponential
+// backoff. A 429 with Retry-After becomes retryable. If the server asks for
+// more than maxWait, the response is returned without retrying. It has no
+// effect without WithRetry.
+func WithRetryAfter(maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetryAfter = maxWait
+	}
+}
+
+// WithRetry429 makes 429 Too Many Requests retryable, with the same backoff
+// as a 5xx. Combine it with WithRetryAfter to wait as long as the server
+// asks instead, up to a cap. It has no effect without WithRetry.
+func WithRetry429() Option {
+	return func(c *Client) {
+		c.retry429 = true
 	}
 }
 
@@ -210,7 +283,7 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	var resp *http.Response
 	var err error
 
-	if c.retrier != nil {
+	if c.retrier != nil && (c.retryMethods == nil || slices.Contains(c.retryMethods, req.Method)) {
 		resp, err = c.retrier.Do(ctx, exec)
 	} else {
 		resp, err = exec()
@@ -124,6 +124,84 @@ func TestNoRetryOn4xx(t *testing.T) {
 	}
 }
 
+func TestRetryPolicyCustomRetryOn(t *testing.T) {
+	srv, hits := counterServer(http.StatusNotFound, http.StatusNotFound)
+	defer srv.Close()
+
+	c := New(WithRetryPolicy(Policy{
+		RetryOn: func(resp *http.Response, err error) bool {
+			return err != nil || resp.StatusCode == http.StatusNotFound
+		},
+		Backoff: func(int) time.Duration { return time.Millisecond },
+	}))
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || hits.Load() != 3 {
+		t.Fatalf("status = %d after %d attempts, want 200 after 3", resp.StatusCode, hits.Load())
+	}
+}
+
+func TestRetryPolicyMethods(t *testing.T) {
+	srv, hits := counterServer(500, 500)
+	defer srv.Close()
+
+	c := New(WithRetryPolicy(Policy{
+		Methods: []string{http.MethodGet},
+		Backoff: func(int) time.Duration { return time.Millisecond },
+	}))
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, nil)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 500 || hits.Load() != 1 {
+		t.Fatalf("POST: status = %d after %d attempts, want 500 after 1", resp.StatusCode, hits.Load())
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err = c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || hits.Load() != 3 {
+		t.Fatalf("GET: status = %d after %d total attempts, want 200 after 3", resp.StatusCode, hits.Load())
+	}
+}
+
+func TestRetryPolicyMaxElapsed(t *testing.T) {
+	srv, hits := counterServer(500, 500, 500, 500)
+	defer srv.Close()
+
+	c := New(WithRetryPolicy(Policy{
+		MaxAttempts: 10,
+		MaxElapsed:  150 * time.Millisecond,
+		Backoff:     func(int) time.Duration { return 100 * time.Millisecond },
+	}))
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// The second wait would end past MaxElapsed, so only one retry is made.
+	if resp.StatusCode != 500 || hits.Load() != 2 {
+		t.Fatalf("status = %d after %d attempts, want 500 after 2", resp.StatusCode, hits.Load())
+	}
+}
+
 func TestContextCancellationStopsRetries(t *testing.T) {
 	srv, hits := counterServer(500, 500, 500, 500, 500)
 	defer srv.Close()
@@ -0,0 +1,108 @@
+package call
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// RequestBuilder builds a request step by step and sends it through its
+// Client. Start one with Client.Request:
+//
+//	resp, err := client.Request(ctx).
+//		Method(http.MethodPost).
+//		URL("https://api.example.com/v1/users").
+//		Header("Authorization", "Bearer "+token).
+//		Query("dry_run", "true").
+//		Body(strings.NewReader(payload)).
+//		Do()
+//
+// A builder is not safe for concurrent use and should not be reused after Do.
+type RequestBuilder struct {
+	client *Client
+	ctx    context.Context
+	method string
+	rawURL string
+	header http.Header
+	query  url.Values
+	body   io.Reader
+}
+
+// Request starts a RequestBuilder for a GET request bound to ctx.
+func (c *Client) Request(ctx context.Context) *RequestBuilder {
+	return &RequestBuilder{
+		client: c,
+		ctx:    ctx,
+		method: http.MethodGet,
+		header: http.Header{},
+		query:  url.Values{},
+	}
+}
+
+// Method sets the HTTP method (default GET).
+func (b *RequestBuilder) Method(method string) *RequestBuilder {
+	b.method = method
+	return b
+}
+
+// URL sets the request URL. Its own query string is kept, and values added
+// with Query are appended to it.
+func (b *RequestBuilder) URL(rawURL string) *RequestBuilder {
+	b.rawURL = rawURL
+	return b
+}
+
+// Header sets a request header, replacing earlier values for key.
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	b.header.Set(key, value)
+	return b
+}
+
+// Query adds a query parameter; repeated keys keep every value.
+func (b *RequestBuilder) Query(key, value string) *RequestBuilder {
+	b.query.Add(key, value)
+	return b
+}
+
+// Body sets the request body. A *bytes.Buffer, *bytes.Reader or
+// *strings.Reader can be rewound, so the request stays safe to retry; any
+// other reader is sent only once.
+func (b *RequestBuilder) Body(body io.Reader) *RequestBuilder {
+	b.body = body
+	return b
+}
+
+// Build returns the *http.Request without sending it.
+func (b *RequestBuilder) Build() (*http.Request, error) {
+	u, err := url.Parse(b.rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("call: request URL: %w", err)
+	}
+	if len(b.query) > 0 {
+		q := u.Query()
+		for key, values := range b.query {
+			q[key] = append(q[key], values...)
+		}
+		u.RawQuery = q.Encode()
+	}
+	req, err := http.NewRequestWithContext(b.ctx, b.method, u.String(), b.body)
+	if err != nil {
+		return nil, fmt.Errorf("call: build request: %w", err)
+	}
+	for key, values := range b.header {
+		req.Header[key] = values
+	}
+	return req, nil
+}
+
+// Do builds the request and sends it with Client.Do, applying the client's
+// timeout, retries, circuit breaker and token source.
+func (b *RequestBuilder) Do() (*http.Response, error) {
+	req, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return b.client.Do(req)
+}
@@ -0,0 +1,64 @@
+package call
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestBuilderSendsMethodHeadersQueryAndBody(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		b, _ := io.ReadAll(r.Body)
+		got, gotBody = r, string(b)
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(WithRetry(2, time.Millisecond))
+	resp, err := c.Request(context.Background()).
+		Method(http.MethodPost).
+		URL(srv.URL+"/v1/users?page=2").
+		Header("X-Api-Key", "secret").
+		Query("tag", "a").
+		Query("tag", "b").
+		Body(strings.NewReader(`{"name":"ada"}`)).
+		Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || attempts != 2 {
+		t.Fatalf("status = %d after %d attempts, want 200 after 2", resp.StatusCode, attempts)
+	}
+	if got.Method != http.MethodPost || got.URL.Path != "/v1/users" {
+		t.Errorf("request = %s %s", got.Method, got.URL.Path)
+	}
+	if q := got.URL.Query(); q.Get("page") != "2" || strings.Join(q["tag"], ",") != "a,b" {
+		t.Errorf("query = %v", q)
+	}
+	if got.Header.Get("X-Api-Key") != "secret" {
+		t.Errorf("X-Api-Key = %q", got.Header.Get("X-Api-Key"))
+	}
+	// The retried attempt gets the body again.
+	if gotBody != `{"name":"ada"}` {
+		t.Errorf("body = %q", gotBody)
+	}
+}
+
+func TestRequestBuilderInvalidURL(t *testing.T) {
+	c := New()
+	if _, err := c.Request(context.Background()).URL("://bad").Do(); err == nil {
+		t.Fatal("expected error for an invalid URL")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"math/rand/v2"
 	"net/http"
+	"strconv"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -12,16 +13,35 @@ import (
 )
 
 // Retrier provides retry logic with exponential backoff and jitter for
-// transient server errors (5xx). It never retries client errors (4xx).
+// transient server errors (5xx). It never retries client errors (4xx), except
+// a 429 when Retry429 is set or its Retry-After is honored. RetryOn, Backoff
+// and MaxElapsed replace those rules; WithRetryPolicy sets them.
 type Retrier struct {
 	MaxAttempts int
 	BaseDelay   time.Duration
+	// Retry429 retries 429 Too Many Requests like a 5xx, after the backoff
+	// or, when MaxRetryAfter is set, the server's Retry-After delay.
+	Retry429 bool
+	// MaxRetryAfter enables honoring the server's Retry-After header on 429
+	// and 5xx responses: the retrier waits the advised delay instead of its
+	// own backoff. If the server asks for longer than MaxRetryAfter the
+	// response is returned without retrying. Zero ignores Retry-After.
+	MaxRetryAfter time.Duration
+	// RetryOn, if set, decides whether an attempt is retried instead of the
+	// 5xx and 429 rules. It gets a nil response on a network error.
+	RetryOn func(*http.Response, error) bool
+	// Backoff, if set, returns the wait before retry attempt+1 (attempt
+	// counts from 0) instead of the exponential backoff from BaseDelay.
+	Backoff func(attempt int) time.Duration
+	// MaxElapsed bounds the time from the first attempt: a retry whose wait
+	// would end past it is not made. Zero means no bound.
+	MaxElapsed time.Duration
 }
 
-// Do executes fn up to MaxAttempts times, retrying only when a 5xx status code
-// is returned. Between attempts it sleeps with exponential backoff plus random
-// jitter of up to 50% of the calculated delay. It respects context
-// cancellation and deadline, stopping immediately when the context is done.
+// Do executes fn up to MaxAttempts times, retrying the attempts the Retrier's
+// rules deem transient and waiting between them as its fields describe; see
+// Retrier. It respects context cancellation and deadline, stopping
+// immediately when the context is done.
 //
 // If the request has a GetBody function, it is called before each retry to
 // rewind the request body. Without GetBody, retries of requests with a body
@@ -31,6 +51,7 @@ func (r *Retrier) Do(ctx context.Context, fn func() (*http.Response, error)) (*h
 		resp *http.Response
 		err  error
 	)
+	start := time.Now()
 
 	for attempt := range r.MaxAttempts {
 		// Check context before each attempt.
@@ -39,33 +60,40 @@ func (r *Retrier) Do(ctx context.Context, fn func() (*http.Response, error)) (*h
 		}
 
 		resp, err = fn()
-		if err != nil {
+		if err != nil && resp != nil {
 			// Drain and close any partial response body so the connection can be reused.
-			if resp != nil && resp.Body != nil {
+			if resp.Body != nil {
 				io.Copy(io.Discard, resp.Body)
 				resp.Body.Close()
 			}
-			// Network-level error — worth retrying.
-			if attempt < r.MaxAttempts-1 {
-				trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
-					attribute.Int("attempt", attempt+1),
-					attribute.String("reason", "network_error"),
-				))
-				if waitErr := r.backoff(ctx, attempt); waitErr != nil {
-					return nil, waitErr
-				}
-				continue
-			}
-			return nil, err
+			resp = nil
 		}
 
-		// 2xx/3xx — success, return immediately.
-		if resp.StatusCode < 500 {
-			return resp, nil
+		var wait time.Duration
+		var hasWait bool
+		if resp != nil {
+			wait, hasWait = r.retryAfter(resp)
+		}
+		if !r.retryable(resp, err, hasWait) || attempt == r.MaxAttempts-1 {
+			return resp, err
+		}
+		if hasWait && wait > r.MaxRetryAfter {
+			// The server wants us to back off longer than we will wait.
+			return resp, err
+		}
+		if !hasWait {
+			wait = r.delay(attempt)
+		}
+		if r.MaxElapsed > 0 && time.Since(start)+wait > r.MaxElapsed {
+			return resp, err
 		}
 
-		// 5xx — retry if we have attempts remaining.
-		if attempt < r.MaxAttempts-1 {
+		if resp == nil {
+			trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+				attribute.Int("attempt", attempt+1),
+				attribute.String("reason", "network_error"),
+			))
+		} else {
 			trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
 				attribute.Int("attempt", attempt+1),
 				attribute.Int("http.status_code", resp.StatusCode),
@@ -73,20 +101,34 @@ func (r *Retrier) Do(ctx context.Context, fn func() (*http.Response, error)) (*h
 			// Drain and close the body so the underlying connection can be reused.
 			io.Copy(io.Discard, resp.Body)
 			resp.Body.Close()
-			if waitErr := r.backoff(ctx, attempt); waitErr != nil {
-				return nil, waitErr
-			}
-			continue
+		}
+		if waitErr := sleep(ctx, wait); waitErr != nil {
+			return nil, waitErr
 		}
 	}
 
-	// All attempts exhausted — return the last response and error.
+	// No attempts were made.
 	return resp, err
 }
 
-// backoff sleeps for an exponentially increasing duration with jitter. It
-// returns an error if the context is cancelled during the wait.
-func (r *Retrier) backoff(ctx context.Context, attempt int) error {
+// retryable reports whether an attempt that ended with resp and err should be
+// retried. hasWait reports whether resp carries an honored Retry-After.
+func (r *Retrier) retryable(resp *http.Response, err error, hasWait bool) bool {
+	if r.RetryOn != nil {
+		return r.RetryOn(resp, err)
+	}
+	if err != nil || resp.StatusCode >= 500 {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests && (hasWait || r.Retry429)
+}
+
+// delay returns the wait before retry attempt+1: Backoff's if set, else an
+// exponentially increasing duration with jitter.
+func (r *Retrier) delay(attempt int) time.Duration {
+	if r.Backoff != nil {
+		return max(0, r.Backoff(attempt))
+	}
 	delay := r.BaseDelay
 	if delay <= 0 {
 		delay = 100 * time.Millisecond
@@ -99,8 +141,32 @@ func (r *Retrier) backoff(ctx context.Context, attempt int) error {
 	if half := int64(delay / 2); half > 0 {
 		delay += time.Duration(rand.Int64N(half))
 	}
+	return delay
+}
+
+// retryAfter parses the Retry-After header of resp, given in seconds or as
+// an HTTP date. It reports false when MaxRetryAfter is zero or the header is
+// absent or malformed.
+func (r *Retrier) retryAfter(resp *http.Response) (time.Duration, bool) {
+	if r.MaxRetryAfter <= 0 {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(0, time.Until(at)), true
+	}
+	return 0, false
+}
 
-	t := time.NewTimer(delay)
+// sleep waits for d, returning an error if ctx is cancelled first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
 	defer t.Stop()
 
 	select {
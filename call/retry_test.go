@@ -73,7 +73,7 @@ func TestRetrier_BackoffHonorsContextCancel(t *testing.T) {
 	cancel()
 
 	start := time.Now()
-	err := r.backoff(ctx, 0)
+	err := sleep(ctx, r.delay(0))
 	if !errors.Is(err, context.Canceled) {
 		t.Fatalf("expected context.Canceled, got %v", err)
 	}
@@ -81,3 +81,96 @@ func TestRetrier_BackoffHonorsContextCancel(t *testing.T) {
 		t.Fatalf("backoff returned too slowly after cancel")
 	}
 }
+
+func TestRetrier_HonorsRetryAfterOn429(t *testing.T) {
+	r := &Retrier{MaxAttempts: 2, BaseDelay: time.Hour, MaxRetryAfter: 5 * time.Second}
+	var attempts int
+	start := time.Now()
+	resp, err := r.Do(context.Background(), func() (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			h := http.Header{"Retry-After": []string{"0"}}
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Header: h, Body: http.NoBody}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || attempts != 2 {
+		t.Fatalf("status = %d, attempts = %d; want 200 after 2 attempts", resp.StatusCode, attempts)
+	}
+	// Retry-After replaces the (hour-long) backoff.
+	if time.Since(start) > time.Second {
+		t.Errorf("retry waited %s, want Retry-After delay", time.Since(start))
+	}
+}
+
+func TestRetrier_RetryAfterBeyondMaxReturnsResponse(t *testing.T) {
+	r := &Retrier{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxRetryAfter: time.Second}
+	var attempts int
+	resp, err := r.Do(context.Background(), func() (*http.Response, error) {
+		attempts++
+		h := http.Header{"Retry-After": []string{"120"}}
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Header: h, Body: http.NoBody}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || attempts != 1 {
+		t.Fatalf("status = %d, attempts = %d; want 503 without retrying", resp.StatusCode, attempts)
+	}
+}
+
+func TestRetrier_429NotRetriedWithoutRetryAfter(t *testing.T) {
+	r := &Retrier{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	var attempts int
+	r.Do(context.Background(), func() (*http.Response, error) {
+		attempts++
+		h := http.Header{"Retry-After": []string{"0"}}
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: h, Body: http.NoBody}, nil
+	})
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1 when MaxRetryAfter is unset", attempts)
+	}
+}
+
+func TestRetrier_Retry429WithoutRetryAfterUsesBackoff(t *testing.T) {
+	r := &Retrier{MaxAttempts: 3, BaseDelay: time.Millisecond, Retry429: true}
+	var attempts int
+	resp, err := r.Do(context.Background(), func() (*http.Response, error) {
+		attempts++
+		if attempts < 3 {
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Body: http.NoBody}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || attempts != 3 {
+		t.Fatalf("status = %d, attempts = %d; want 200 after 3 attempts", resp.StatusCode, attempts)
+	}
+}
+
+func TestRetrier_Retry429HonorsRetryAfterCap(t *testing.T) {
+	r := &Retrier{MaxAttempts: 3, BaseDelay: time.Millisecond, Retry429: true, MaxRetryAfter: time.Second}
+	var attempts int
+	resp, _ := r.Do(context.Background(), func() (*http.Response, error) {
+		attempts++
+		h := http.Header{"Retry-After": []string{"120"}}
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: h, Body: http.NoBody}, nil
+	})
+	if resp.StatusCode != http.StatusTooManyRequests || attempts != 1 {
+		t.Fatalf("status = %d, attempts = %d; want 429 without retrying past the cap", resp.StatusCode, attempts)
+	}
+}
+
+func TestRetrier_ParsesHTTPDateRetryAfter(t *testing.T) {
+	r := &Retrier{MaxRetryAfter: time.Minute}
+	h := http.Header{"Retry-After": []string{time.Now().Add(-time.Second).UTC().Format(http.TimeFormat)}}
+	d, ok := r.retryAfter(&http.Response{Header: h})
+	if !ok || d != 0 {
+		t.Fatalf("retryAfter = %v, %v; want 0, true for a past date", d, ok)
+	}
+}
@@ -3,8 +3,11 @@
 package config
 
 import (
+	"context"
+	"encoding"
+	"errors"
 	"fmt"
-	"os"
+	"maps"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -19,78 +22,278 @@ import (
 //
 // Supported struct tags:
 //
-//	env:"VAR_NAME"       — the environment variable to read
+//	env:"VAR_NAME"       — the environment variable (or Source key) to read
 //	default:"value"      — fallback value when the env var is empty
 //	required:"true"      — panic if missing and no default (this is the default behavior)
 //	required:"false"     — leave the zero value if missing and no default
+//	required_if:"K=v"    — required only when key K resolves to v (e.g. "TLS_ENABLED=true")
+//	secret:"name"        — resolve from the configured SecretProvider, falling back to env
 //
-// Supported field types: string, int, int64, float64, bool, time.Duration, []string.
-func MustLoad[T any]() T {
+// Supported field types: string, int, int64, float64, bool, time.Duration,
+// comma-separated slices of those types ([]string, []int, []time.Duration, ...),
+// string-keyed maps from "k1=v1,k2=v2" (map[string]string, map[string]int, ...),
+// any type implementing encoding.TextUnmarshaler, and any type with a parser
+// registered via RegisterParser.
+//
+// MustLoad reports every problem found in a single panic message; use Load to
+// receive them as an error instead.
+func MustLoad[T any](opts ...Option) T {
+	cfg, err := Load[T](opts...)
+	if err != nil {
+		panic(err.Error())
+	}
+	return cfg
+}
+
+// Load is the non-panicking variant of MustLoad. Instead of stopping at the
+// first problem it checks every field and returns a *LoadError listing each
+// missing, unparseable, or invalid value, so a service can report its whole
+// misconfiguration at once and decide how to fail.
+func Load[T any](opts ...Option) (T, error) {
 	chassis.AssertVersionChecked()
+	l := &loader{opts: options{ctx: context.Background(), sources: []Source{Env()}}}
+	for _, o := range opts {
+		o(&l.opts)
+	}
+
 	var cfg T
 	v := reflect.ValueOf(&cfg).Elem()
 	t := v.Type()
 
-	loadFields(v, t)
+	var docs []FieldDoc
+	describeFields(t, "", &docs)
+	l.defaults = make(map[string]string, len(docs))
+	for _, d := range docs {
+		if d.Env != "" && d.Default != "" {
+			l.defaults[d.Env] = d.Default
+		}
+	}
 
-	return cfg
+	l.prov = make(map[string]string)
+	l.loadFields(v, t, "")
+	if len(l.errs) > 0 {
+		return cfg, &LoadError{Errors: l.errs}
+	}
+	if l.opts.provenance != nil {
+		*l.opts.provenance = l.prov
+	}
+
+	return cfg, nil
+}
+
+// Option configures Load and MustLoad.
+type Option func(*options)
+
+type options struct {
+	ctx        context.Context
+	secrets    SecretProvider
+	sources    []Source
+	provenance *map[string]string
+}
+
+// WithContext sets the context passed to the SecretProvider. Defaults to
+// context.Background().
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithSecretProvider resolves fields tagged secret:"name" through p. When p
+// reports ErrSecretNotFound the field falls back to its env var and default.
+func WithSecretProvider(p SecretProvider) Option {
+	return func(o *options) { o.secrets = p }
 }
 
-// loadFields populates struct fields from environment variables, recursing
+// loader carries the options and accumulated errors for a single Load call.
+type loader struct {
+	opts     options
+	defaults map[string]string // env key → default tag, for required_if
+	found    int               // fields whose value came from a source
+	prov     map[string]string // field path → origin, for WithProvenance
+	errs     []*FieldError
+}
+
+func (l *loader) fail(field reflect.StructField, err error) {
+	l.errs = append(l.errs, &FieldError{
+		Field:  field.Name,
+		Env:    field.Tag.Get("env"),
+		Secret: field.Tag.Get("secret"),
+		Err:    err,
+	})
+}
+
+// loadFields populates struct fields from the configured sources, recursing
 // into nested structs so that embedded config types (e.g. kafkakit.Config) are
-// populated correctly.
-func loadFields(v reflect.Value, t reflect.Type) {
+// populated correctly. Problems are recorded on the loader rather than aborting.
+func (l *loader) loadFields(v reflect.Value, t reflect.Type, prefix string) {
 	for i := range t.NumField() {
 		field := t.Field(i)
 		fieldVal := v.Field(i)
 
-		// Skip unexported fields — they can't be set via reflection.
-		if !field.IsExported() {
+		// Skip unexported fields — they can't be set via reflection. Embedded
+		// unexported structs are the exception: their exported fields are
+		// promoted and remain settable.
+		if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+
+		// Recurse into nested and embedded structs (e.g. kafkakit.Config,
+		// meilikit.Config). Structs with a registered parser or a
+		// TextUnmarshaler (url.URL, netip.Addr, ...) are leaf values and are
+		// set directly instead.
+		if field.Type.Kind() == reflect.Struct && !isScalar(field.Type) {
+			sub := prefix
+			if !field.Anonymous {
+				sub += field.Name + "."
+			}
+			l.loadFields(fieldVal, field.Type, sub)
 			continue
 		}
 
-		// Recurse into nested structs (e.g. kafkakit.Config, meilikit.Config).
-		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Duration(0)) {
-			loadFields(fieldVal, field.Type)
+		// Optional *struct groups are only allocated when at least one of
+		// their fields is supplied by a source.
+		if isOptionalGroup(field.Type) {
+			l.loadOptional(fieldVal, field.Type.Elem(), prefix+field.Name+".")
 			continue
 		}
 
 		envKey := field.Tag.Get("env")
-		if envKey == "" {
+		secretName := field.Tag.Get("secret")
+		if envKey == "" && secretName == "" {
 			continue
 		}
 
-		raw := os.Getenv(envKey)
+		raw, origin, err := l.lookup(envKey, secretName)
+		if err != nil {
+			l.fail(field, err)
+			continue
+		}
+		if raw != "" {
+			l.found++
+		}
 
 		// Apply default if env var is empty.
 		if raw == "" {
 			if def, ok := field.Tag.Lookup("default"); ok {
 				raw = def
+				origin = OriginDefault
 			}
 		}
+		if raw != "" {
+			l.prov[prefix+field.Name] = origin
+		}
 
 		// Handle missing value.
 		if raw == "" {
+			if cond := field.Tag.Get("required_if"); cond != "" {
+				met, err := l.conditionMet(cond)
+				if err != nil {
+					l.fail(field, err)
+				} else if met {
+					l.fail(field, &requiredIfError{cond: cond})
+				}
+				continue
+			}
 			req := field.Tag.Get("required")
 			if req == "false" {
 				continue
 			}
 			// Default behaviour: required.
-			panic(fmt.Sprintf("config: required environment variable %q is not set (field %s)", envKey, field.Name))
+			l.fail(field, ErrMissing)
+			continue
 		}
 
 		if err := setField(fieldVal, raw); err != nil {
-			panic(fmt.Sprintf("config: cannot set field %s from env %q: %v", field.Name, envKey, err))
+			l.fail(field, err)
+			continue
 		}
 
 		if vTag := field.Tag.Get("validate"); vTag != "" {
-			validateField(field.Name, fieldVal, vTag)
+			if err := validateField(fieldVal, vTag); err != nil {
+				l.fail(field, err)
+			}
 		}
 	}
 }
 
+// loadOptional populates a *struct field. The struct is loaded into a fresh
+// allocation with its own error list; if none of its fields were supplied by
+// a source the pointer stays nil and any errors (e.g. missing required
+// fields inside the group) are discarded.
+func (l *loader) loadOptional(fieldVal reflect.Value, t reflect.Type, prefix string) {
+	ptr := reflect.New(t)
+	sub := &loader{opts: l.opts, defaults: l.defaults, prov: make(map[string]string)}
+	sub.loadFields(ptr.Elem(), t, prefix)
+	if sub.found == 0 {
+		return
+	}
+	fieldVal.Set(ptr)
+	l.found += sub.found
+	l.errs = append(l.errs, sub.errs...)
+	maps.Copy(l.prov, sub.prov)
+}
+
+// isOptionalGroup reports whether t is a pointer to a nested config struct.
+func isOptionalGroup(t reflect.Type) bool {
+	return t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct && !isScalar(t.Elem())
+}
+
+// conditionMet evaluates a required_if condition of the form KEY=value. KEY is
+// resolved through the same sources as any field, falling back to the default
+// of the field bound to KEY. Boolean values compare semantically, so
+// "TLS_ENABLED=true" is met by TLS_ENABLED=1.
+func (l *loader) conditionMet(cond string) (bool, error) {
+	key, want, ok := strings.Cut(cond, "=")
+	key, want = strings.TrimSpace(key), strings.TrimSpace(want)
+	if !ok || key == "" {
+		return false, fmt.Errorf("invalid required_if tag %q: expected KEY=value", cond)
+	}
+	actual, _, _ := l.lookup(key, "")
+	if actual == "" {
+		actual = l.defaults[key]
+	}
+	if a, err := strconv.ParseBool(actual); err == nil {
+		if w, err := strconv.ParseBool(want); err == nil {
+			return a == w, nil
+		}
+	}
+	return actual == want, nil
+}
+
+// lookup resolves the raw value for a field and reports which source supplied
+// it. A configured SecretProvider is consulted first for secret-tagged fields,
+// then each Source in order.
+func (l *loader) lookup(envKey, secretName string) (val, origin string, err error) {
+	if secretName != "" && l.opts.secrets != nil {
+		val, err := l.opts.secrets.Secret(l.opts.ctx, secretName)
+		switch {
+		case err == nil && val != "":
+			return val, OriginSecret, nil
+		case err != nil && !errors.Is(err, ErrSecretNotFound):
+			return "", "", fmt.Errorf("secret %q: %w", secretName, err)
+		}
+	}
+	if envKey == "" {
+		return "", "", nil
+	}
+	if val, origin, ok := lookupSource(Multi(l.opts.sources...), envKey); ok {
+		return val, origin, nil
+	}
+	return "", "", nil
+}
+
 // setField converts a raw string value and sets it on the reflected field.
+// Registered parsers take precedence, followed by the built-in types and
+// finally encoding.TextUnmarshaler.
 func setField(fieldVal reflect.Value, raw string) error {
+	if parse, ok := lookupParser(fieldVal.Type()); ok {
+		v, err := parse(raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", fieldVal.Type(), err)
+		}
+		fieldVal.Set(v)
+		return nil
+	}
+
 	// Handle time.Duration specially before the kind switch.
 	if fieldVal.Type() == reflect.TypeOf(time.Duration(0)) {
 		d, err := time.ParseDuration(raw)
@@ -112,6 +315,14 @@ func setField(fieldVal reflect.Value, raw string) error {
 		return nil
 	}
 
+	if fieldVal.CanAddr() && reflect.PointerTo(fieldVal.Type()).Implements(textUnmarshalerType) {
+		u := fieldVal.Addr().Interface().(encoding.TextUnmarshaler)
+		if err := u.UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("invalid %s: %w", fieldVal.Type(), err)
+		}
+		return nil
+	}
+
 	switch fieldVal.Kind() {
 	case reflect.String:
 		fieldVal.SetString(raw)
@@ -141,6 +352,41 @@ func setField(fieldVal reflect.Value, raw string) error {
 		}
 		fieldVal.SetBool(b)
 
+	case reflect.Slice:
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(fieldVal.Type(), len(parts), len(parts))
+		for i, p := range parts {
+			if err := setField(slice.Index(i), strings.TrimSpace(p)); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		fieldVal.Set(slice)
+
+	case reflect.Map:
+		if fieldVal.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", fieldVal.Type())
+		}
+		m := reflect.MakeMap(fieldVal.Type())
+		for _, entry := range strings.Split(raw, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(entry, "=")
+			k = strings.TrimSpace(k)
+			if !ok || k == "" {
+				return fmt.Errorf("invalid map entry %q: expected key=value", entry)
+			}
+			key := reflect.New(fieldVal.Type().Key()).Elem()
+			key.SetString(k)
+			elem := reflect.New(fieldVal.Type().Elem()).Elem()
+			if err := setField(elem, strings.TrimSpace(v)); err != nil {
+				return fmt.Errorf("key %q: %w", k, err)
+			}
+			m.SetMapIndex(key, elem)
+		}
+		fieldVal.Set(m)
+
 	default:
 		return fmt.Errorf("unsupported field type %s", fieldVal.Type())
 	}
@@ -151,7 +397,7 @@ func setField(fieldVal reflect.Value, raw string) error {
 // validateField checks a populated field against constraints in the validate
 // struct tag. Supported keys: min, max, oneof, pattern. Multiple constraints
 // are comma-separated (e.g. validate:"min=1,max=65535").
-func validateField(name string, val reflect.Value, tag string) {
+func validateField(val reflect.Value, tag string) error {
 	parts := strings.Split(tag, ",")
 	for _, part := range parts {
 		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
@@ -159,20 +405,20 @@ func validateField(name string, val reflect.Value, tag string) {
 		case "min":
 			minVal, err := strconv.ParseFloat(value, 64)
 			if err != nil {
-				panic(fmt.Sprintf("config: field %s has invalid min value %q in validate tag", name, value))
+				return fmt.Errorf("invalid min value %q in validate tag", value)
 			}
 			actual := fieldAsFloat(val)
 			if actual < minVal {
-				panic(fmt.Sprintf("config: field %s value %v is below minimum %s", name, val.Interface(), value))
+				return fmt.Errorf("value %v is below minimum %s", val.Interface(), value)
 			}
 		case "max":
 			maxVal, err := strconv.ParseFloat(value, 64)
 			if err != nil {
-				panic(fmt.Sprintf("config: field %s has invalid max value %q in validate tag", name, value))
+				return fmt.Errorf("invalid max value %q in validate tag", value)
 			}
 			actual := fieldAsFloat(val)
 			if actual > maxVal {
-				panic(fmt.Sprintf("config: field %s value %v exceeds maximum %s", name, val.Interface(), value))
+				return fmt.Errorf("value %v exceeds maximum %s", val.Interface(), value)
 			}
 		case "oneof":
 			allowed := strings.Fields(value)
@@ -185,19 +431,20 @@ func validateField(name string, val reflect.Value, tag string) {
 				}
 			}
 			if !found {
-				panic(fmt.Sprintf("config: field %s value %q not in allowed set [%s]", name, actual, value))
+				return fmt.Errorf("value %q not in allowed set [%s]", actual, value)
 			}
 		case "pattern":
 			re, err := regexp.Compile(value)
 			if err != nil {
-				panic(fmt.Sprintf("config: field %s has invalid pattern %q in validate tag: %v", name, value, err))
+				return fmt.Errorf("invalid pattern %q in validate tag: %v", value, err)
 			}
 			actual := fmt.Sprintf("%v", val.Interface())
 			if !re.MatchString(actual) {
-				panic(fmt.Sprintf("config: field %s value %q does not match pattern %s", name, actual, value))
+				return fmt.Errorf("value %q does not match pattern %s", actual, value)
 			}
 		}
 	}
+	return nil
 }
 
 // fieldAsFloat converts numeric reflect values to float64 for comparison.
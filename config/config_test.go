@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -321,3 +322,247 @@ func TestValidateMinMax(t *testing.T) {
 	}
 }
 
+// ---------- Load tests ----------
+
+func TestLoad_ReturnsConfig(t *testing.T) {
+	t.Setenv("TEST_HOST", "example.com")
+
+	cfg, err := Load[withDefaults]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Host != "example.com" || cfg.Port != 8080 {
+		t.Errorf("cfg = %+v, want Host=example.com Port=8080", cfg)
+	}
+}
+
+func TestLoad_AggregatesAllErrors(t *testing.T) {
+	type Cfg struct {
+		Secret string `env:"TEST_SECRET"`
+		Port   int    `env:"TEST_PORT"`
+		Level  string `env:"TEST_LEVEL" validate:"oneof=debug info"`
+		Token  string `env:"TEST_TOKEN"`
+	}
+	t.Setenv("TEST_PORT", "not-a-number")
+	t.Setenv("TEST_LEVEL", "verbose")
+
+	_, err := Load[Cfg]()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var le *LoadError
+	if !errors.As(err, &le) {
+		t.Fatalf("error %T is not a *LoadError", err)
+	}
+	if len(le.Errors) != 4 {
+		t.Fatalf("got %d field errors, want 4: %v", len(le.Errors), err)
+	}
+	for i, want := range []string{"Secret", "Port", "Level", "Token"} {
+		if le.Errors[i].Field != want {
+			t.Errorf("Errors[%d].Field = %q, want %q", i, le.Errors[i].Field, want)
+		}
+	}
+	if !errors.Is(err, ErrMissing) {
+		t.Error("expected errors.Is(err, ErrMissing) to be true")
+	}
+	for _, key := range []string{"TEST_SECRET", "TEST_PORT", "TEST_LEVEL", "TEST_TOKEN"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error message %q does not mention %s", err.Error(), key)
+		}
+	}
+}
+
+func TestMustLoad_PanicListsAllErrors(t *testing.T) {
+	type Cfg struct {
+		A string `env:"TEST_A"`
+		B string `env:"TEST_B"`
+	}
+	defer func() {
+		r := recover()
+		msg, ok := r.(string)
+		if !ok {
+			t.Fatalf("panic value is not a string: %v", r)
+		}
+		if !strings.Contains(msg, "TEST_A") || !strings.Contains(msg, "TEST_B") {
+			t.Errorf("panic message %q should mention both TEST_A and TEST_B", msg)
+		}
+	}()
+	MustLoad[Cfg]()
+}
+
+// ---------- collection type tests ----------
+
+func TestMustLoad_NumericSlices(t *testing.T) {
+	type Cfg struct {
+		Ports   []int           `env:"TEST_PORTS"`
+		Backoff []time.Duration `env:"TEST_BACKOFF"`
+	}
+	t.Setenv("TEST_PORTS", "80, 443,8080")
+	t.Setenv("TEST_BACKOFF", "100ms,1s, 5s")
+
+	cfg := MustLoad[Cfg]()
+	if len(cfg.Ports) != 3 || cfg.Ports[0] != 80 || cfg.Ports[1] != 443 || cfg.Ports[2] != 8080 {
+		t.Errorf("Ports = %v, want [80 443 8080]", cfg.Ports)
+	}
+	want := []time.Duration{100 * time.Millisecond, time.Second, 5 * time.Second}
+	if len(cfg.Backoff) != len(want) {
+		t.Fatalf("Backoff = %v, want %v", cfg.Backoff, want)
+	}
+	for i := range want {
+		if cfg.Backoff[i] != want[i] {
+			t.Errorf("Backoff[%d] = %v, want %v", i, cfg.Backoff[i], want[i])
+		}
+	}
+}
+
+func TestMustLoad_Maps(t *testing.T) {
+	type Cfg struct {
+		Labels map[string]string `env:"TEST_LABELS"`
+		Limits map[string]int    `env:"TEST_LIMITS"`
+	}
+	t.Setenv("TEST_LABELS", "team=platform, tier = gold,")
+	t.Setenv("TEST_LIMITS", "free=10,pro=1000")
+
+	cfg := MustLoad[Cfg]()
+	if len(cfg.Labels) != 2 || cfg.Labels["team"] != "platform" || cfg.Labels["tier"] != "gold" {
+		t.Errorf("Labels = %v, want map[team:platform tier:gold]", cfg.Labels)
+	}
+	if len(cfg.Limits) != 2 || cfg.Limits["free"] != 10 || cfg.Limits["pro"] != 1000 {
+		t.Errorf("Limits = %v, want map[free:10 pro:1000]", cfg.Limits)
+	}
+}
+
+func TestLoad_InvalidCollections(t *testing.T) {
+	type Cfg struct {
+		Ports  []int             `env:"TEST_PORTS"`
+		Limits map[string]int    `env:"TEST_LIMITS"`
+		Labels map[string]string `env:"TEST_LABELS"`
+	}
+	t.Setenv("TEST_PORTS", "80,http")
+	t.Setenv("TEST_LIMITS", "free=lots")
+	t.Setenv("TEST_LABELS", "novalue")
+
+	_, err := Load[Cfg]()
+	var le *LoadError
+	if !errors.As(err, &le) || len(le.Errors) != 3 {
+		t.Fatalf("expected 3 field errors, got %v", err)
+	}
+}
+
+// ---------- required_if tests ----------
+
+type tlsConfig struct {
+	TLSEnabled bool   `env:"TEST_TLS_ENABLED" default:"false"`
+	CertFile   string `env:"TEST_TLS_CERT" required_if:"TEST_TLS_ENABLED=true"`
+	KeyFile    string `env:"TEST_TLS_KEY" required_if:"TEST_TLS_ENABLED=true"`
+}
+
+func TestRequiredIf_ConditionNotMet(t *testing.T) {
+	cfg, err := Load[tlsConfig]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.CertFile != "" {
+		t.Errorf("CertFile = %q, want empty", cfg.CertFile)
+	}
+}
+
+func TestRequiredIf_ConditionMet(t *testing.T) {
+	t.Setenv("TEST_TLS_ENABLED", "1")
+	t.Setenv("TEST_TLS_KEY", "/etc/tls/key.pem")
+
+	_, err := Load[tlsConfig]()
+	var le *LoadError
+	if !errors.As(err, &le) || len(le.Errors) != 1 {
+		t.Fatalf("expected exactly one field error, got %v", err)
+	}
+	if !errors.Is(err, ErrMissing) {
+		t.Error("expected errors.Is(err, ErrMissing)")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "TEST_TLS_CERT") || !strings.Contains(msg, "required when TEST_TLS_ENABLED=true") {
+		t.Errorf("error %q should name the field and the triggering condition", msg)
+	}
+}
+
+func TestRequiredIf_ConditionFromDefault(t *testing.T) {
+	type Cfg struct {
+		Mode   string `env:"TEST_MODE" default:"prod"`
+		APIKey string `env:"TEST_API_KEY" required_if:"TEST_MODE=prod"`
+	}
+	if _, err := Load[Cfg](); !errors.Is(err, ErrMissing) {
+		t.Fatalf("expected ErrMissing when default satisfies condition, got %v", err)
+	}
+	t.Setenv("TEST_MODE", "dev")
+	if _, err := Load[Cfg](); err != nil {
+		t.Fatalf("unexpected error with TEST_MODE=dev: %v", err)
+	}
+}
+
+func TestRequiredIf_MalformedTag(t *testing.T) {
+	type Cfg struct {
+		Cert string `env:"TEST_TLS_CERT" required_if:"TEST_TLS_ENABLED"`
+	}
+	_, err := Load[Cfg]()
+	if err == nil || !strings.Contains(err.Error(), "invalid required_if") {
+		t.Fatalf("expected malformed tag error, got %v", err)
+	}
+}
+
+// ---------- embedded and pointer struct tests ----------
+
+type sharedHTTP struct {
+	Port int `env:"TEST_HTTP_PORT" default:"8080"`
+}
+
+type sharedDB struct {
+	URL      string `env:"TEST_DB_URL"`
+	MaxConns int    `env:"TEST_DB_MAX_CONNS" default:"10"`
+}
+
+type embeddingConfig struct {
+	sharedHTTP
+	Name string    `env:"TEST_NAME" default:"svc"`
+	DB   *sharedDB // optional group
+}
+
+func TestMustLoad_EmbeddedUnexportedStruct(t *testing.T) {
+	t.Setenv("TEST_HTTP_PORT", "9090")
+
+	cfg := MustLoad[embeddingConfig]()
+	if cfg.Port != 9090 {
+		t.Errorf("Port = %d, want 9090", cfg.Port)
+	}
+}
+
+func TestMustLoad_PointerStructNilWhenUnset(t *testing.T) {
+	cfg, err := Load[embeddingConfig]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DB != nil {
+		t.Errorf("DB = %+v, want nil when no TEST_DB_* vars are set", cfg.DB)
+	}
+}
+
+func TestMustLoad_PointerStructAllocatedWhenSet(t *testing.T) {
+	t.Setenv("TEST_DB_URL", "postgres://db")
+
+	cfg := MustLoad[embeddingConfig]()
+	if cfg.DB == nil {
+		t.Fatal("DB = nil, want allocated group")
+	}
+	if cfg.DB.URL != "postgres://db" || cfg.DB.MaxConns != 10 {
+		t.Errorf("DB = %+v, want URL=postgres://db MaxConns=10", *cfg.DB)
+	}
+}
+
+func TestLoad_PointerStructPartiallySetReportsMissing(t *testing.T) {
+	t.Setenv("TEST_DB_MAX_CONNS", "20")
+
+	_, err := Load[embeddingConfig]()
+	if !errors.Is(err, ErrMissing) || !strings.Contains(err.Error(), "TEST_DB_URL") {
+		t.Fatalf("expected missing TEST_DB_URL once the group is in use, got %v", err)
+	}
+}
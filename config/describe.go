@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+)
+
+// FieldDoc describes one configurable field of a config struct.
+type FieldDoc struct {
+	Field      string // dotted Go field path, e.g. "HTTP.Port"
+	Env        string // environment variable name
+	Secret     string // secret name from the secret tag, if any
+	Type       string // Go type, e.g. "time.Duration"
+	Default    string // value of the default tag
+	Required   bool   // true when Load fails if the value is missing
+	RequiredIf string // required_if condition, e.g. "TLS_ENABLED=true"
+	Doc        string // human-readable description from the doc tag
+}
+
+// Describe returns a FieldDoc for every env- or secret-tagged field of T, in
+// declaration order, walking nested structs the same way Load does. Go doc
+// comments are not available at runtime, so descriptions come from a doc tag:
+//
+//	Port int `env:"PORT" default:"8080" doc:"HTTP listen port"`
+//
+// Use WriteMarkdown or WriteText to render the result for --help output or
+// deployment docs.
+func Describe[T any]() []FieldDoc {
+	chassis.AssertVersionChecked()
+	var docs []FieldDoc
+	describeFields(reflect.TypeFor[T](), "", &docs)
+	return docs
+}
+
+func describeFields(t reflect.Type, prefix string, docs *[]FieldDoc) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct && !isScalar(field.Type) {
+			// Embedded fields are promoted, so they keep the parent's prefix.
+			sub := prefix
+			if !field.Anonymous {
+				sub += field.Name + "."
+			}
+			describeFields(field.Type, sub, docs)
+			continue
+		}
+		if isOptionalGroup(field.Type) {
+			describeFields(field.Type.Elem(), prefix+field.Name+".", docs)
+			continue
+		}
+
+		envKey := field.Tag.Get("env")
+		secretName := field.Tag.Get("secret")
+		if envKey == "" && secretName == "" {
+			continue
+		}
+
+		def := field.Tag.Get("default")
+		*docs = append(*docs, FieldDoc{
+			Field:      prefix + field.Name,
+			Env:        envKey,
+			Secret:     secretName,
+			Type:       field.Type.String(),
+			Default:    def,
+			Required:   field.Tag.Get("required") != "false" && field.Tag.Get("required_if") == "" && def == "",
+			RequiredIf: field.Tag.Get("required_if"),
+			Doc:        field.Tag.Get("doc"),
+		})
+	}
+}
+
+// WriteMarkdown renders docs as a Markdown table.
+func WriteMarkdown(w io.Writer, docs []FieldDoc) error {
+	var b strings.Builder
+	b.WriteString("| Variable | Type | Default | Required | Description |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, d := range docs {
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %s | %s |\n",
+			mdCell(docName(d)), d.Type, mdCode(d.Default), mdCell(requiredText(d)), mdCell(d.Doc))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteText renders docs as an aligned plain-text table suitable for --help.
+func WriteText(w io.Writer, docs []FieldDoc) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VARIABLE\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION")
+	for _, d := range docs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", docName(d), d.Type, d.Default, requiredText(d), d.Doc)
+	}
+	return tw.Flush()
+}
+
+// docName is the display name for a field: its env var, annotated with the
+// secret name when one is set.
+func docName(d FieldDoc) string {
+	switch {
+	case d.Env == "":
+		return "secret:" + d.Secret
+	case d.Secret != "":
+		return d.Env + " (secret:" + d.Secret + ")"
+	default:
+		return d.Env
+	}
+}
+
+func mdCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func mdCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + mdCell(s) + "`"
+}
+
+func requiredText(d FieldDoc) string {
+	switch {
+	case d.Required:
+		return "yes"
+	case d.RequiredIf != "" && d.Default == "":
+		return "if " + d.RequiredIf
+	default:
+		return "no"
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type describeHTTP struct {
+	Port    int           `env:"HTTP_PORT" default:"8080" doc:"listen port"`
+	Timeout time.Duration `env:"HTTP_TIMEOUT" required:"false" doc:"request timeout"`
+}
+
+type describeConfig struct {
+	HTTP     describeHTTP
+	Database string `env:"DATABASE_URL" doc:"Postgres DSN"`
+	Password string `env:"DB_PASSWORD" secret:"db-password"`
+	Internal string
+}
+
+func TestDescribe(t *testing.T) {
+	docs := Describe[describeConfig]()
+	if len(docs) != 4 {
+		t.Fatalf("got %d docs, want 4: %+v", len(docs), docs)
+	}
+
+	want := []FieldDoc{
+		{Field: "HTTP.Port", Env: "HTTP_PORT", Type: "int", Default: "8080", Required: false, Doc: "listen port"},
+		{Field: "HTTP.Timeout", Env: "HTTP_TIMEOUT", Type: "time.Duration", Required: false, Doc: "request timeout"},
+		{Field: "Database", Env: "DATABASE_URL", Type: "string", Required: true, Doc: "Postgres DSN"},
+		{Field: "Password", Env: "DB_PASSWORD", Secret: "db-password", Type: "string", Required: true},
+	}
+	for i := range want {
+		if docs[i] != want[i] {
+			t.Errorf("docs[%d] = %+v, want %+v", i, docs[i], want[i])
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := WriteMarkdown(&b, Describe[describeConfig]()); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"| Variable | Type | Default | Required | Description |",
+		"| HTTP_PORT | `int` | `8080` | no | listen port |",
+		"| DATABASE_URL | `string` |  | yes | Postgres DSN |",
+		"DB_PASSWORD (secret:db-password)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var b strings.Builder
+	if err := WriteText(&b, Describe[describeConfig]()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want 5:\n%s", len(lines), b.String())
+	}
+	if !strings.HasPrefix(lines[0], "VARIABLE") || !strings.Contains(lines[3], "DATABASE_URL") {
+		t.Errorf("unexpected text output:\n%s", b.String())
+	}
+}
+
+func TestDescribe_RequiredIf(t *testing.T) {
+	docs := Describe[tlsConfig]()
+	if docs[1].Required || docs[1].RequiredIf != "TEST_TLS_ENABLED=true" {
+		t.Errorf("docs[1] = %+v, want conditional requirement", docs[1])
+	}
+	var b strings.Builder
+	if err := WriteText(&b, docs); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "if TEST_TLS_ENABLED=true") {
+		t.Errorf("text output missing condition:\n%s", b.String())
+	}
+}
+
+func TestDescribe_EmbeddedAndPointer(t *testing.T) {
+	docs := Describe[embeddingConfig]()
+	var fields []string
+	for _, d := range docs {
+		fields = append(fields, d.Field)
+	}
+	got := strings.Join(fields, ",")
+	if want := "Port,Name,DB.URL,DB.MaxConns"; got != want {
+		t.Errorf("fields = %s, want %s", got, want)
+	}
+}
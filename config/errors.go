@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMissing is wrapped by a FieldError when a required variable is not set
+// and the field has no default.
+var ErrMissing = errors.New("required value is not set")
+
+// FieldError describes a single field that could not be loaded.
+type FieldError struct {
+	Field  string // Go struct field name
+	Env    string // environment variable the field is bound to, if any
+	Secret string // secret name from the secret tag, if any
+	Err    error  // underlying cause; ErrMissing for unset required fields
+}
+
+func (e *FieldError) Error() string {
+	if errors.Is(e.Err, ErrMissing) {
+		detail := "field " + e.Field
+		var ri *requiredIfError
+		if errors.As(e.Err, &ri) {
+			detail += "; required when " + ri.cond
+		}
+		if e.Env == "" {
+			return fmt.Sprintf("config: required secret %q is not set (%s)", e.Secret, detail)
+		}
+		return fmt.Sprintf("config: required environment variable %q is not set (%s)", e.Env, detail)
+	}
+	if e.Env == "" {
+		return fmt.Sprintf("config: field %s from secret %q: %v", e.Field, e.Secret, e.Err)
+	}
+	return fmt.Sprintf("config: field %s from env %q: %v", e.Field, e.Env, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// LoadError aggregates every FieldError found during a single Load call.
+// It supports errors.Is / errors.As against the individual field errors.
+type LoadError struct {
+	Errors []*FieldError
+}
+
+func (e *LoadError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("config: %d errors:\n%s", len(e.Errors), strings.Join(msgs, "\n"))
+}
+
+// Unwrap exposes the individual field errors to errors.Is and errors.As.
+func (e *LoadError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, fe := range e.Errors {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// requiredIfError marks a missing field whose required_if condition was met.
+type requiredIfError struct {
+	cond string
+}
+
+func (e *requiredIfError) Error() string {
+	return ErrMissing.Error() + " (required when " + e.cond + ")"
+}
+
+func (e *requiredIfError) Unwrap() error { return ErrMissing }
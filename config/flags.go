@@ -0,0 +1,113 @@
+package config
+
+import (
+	"flag"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BindFlags registers a command-line flag on fs for every env-tagged field of
+// T and returns a Source that reports the flags explicitly set on the command
+// line. Flag names are derived from the env key — HTTP_PORT becomes
+// --http-port — and the usage text comes from the doc tag. Fields of bool
+// kind are boolean flags, so a bare --verbose sets VERBOSE to true.
+//
+// Place the returned Source ahead of Env() to get flags > env > default
+// precedence:
+//
+//	flags := config.BindFlags[AppConfig](flag.CommandLine)
+//	flag.Parse()
+//	cfg := config.MustLoad[AppConfig](config.WithSources(flags, config.Env()))
+//
+// If fs already defines a flag with the derived name, that flag is reused
+// rather than redefined, so callers may declare typed flags themselves.
+func BindFlags[T any](fs *flag.FlagSet) Source {
+	t := reflect.TypeFor[T]()
+	var docs []FieldDoc
+	describeFields(t, "", &docs)
+
+	names := make(map[string]string, len(docs))
+	for _, d := range docs {
+		if d.Env == "" {
+			continue
+		}
+		name := FlagName(d.Env)
+		names[d.Env] = name
+		if fs.Lookup(name) != nil {
+			continue
+		}
+		usage := d.Doc
+		if usage != "" {
+			usage += " "
+		}
+		usage += "(env " + d.Env + ")"
+		if fieldKind(t, d.Field) == reflect.Bool {
+			fs.Var(&boolFlag{val: d.Default}, name, usage)
+			continue
+		}
+		fs.String(name, d.Default, usage)
+	}
+
+	return Named(OriginFlags, SourceFunc(func(key string) (string, bool) {
+		name, ok := names[key]
+		if !ok {
+			return "", false
+		}
+		var val string
+		var set bool
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == name {
+				val, set = f.Value.String(), true
+			}
+		})
+		return val, set
+	}))
+}
+
+// fieldKind returns the kind of the field at the dotted Go path within t,
+// looking through pointers.
+func fieldKind(t reflect.Type, path string) reflect.Kind {
+	for name := range strings.SplitSeq(path, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		f, ok := t.FieldByName(name)
+		if !ok {
+			return reflect.Invalid
+		}
+		t = f.Type
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind()
+}
+
+// boolFlag is a boolean flag that keeps its default as written in the
+// default tag, so Load parses it exactly as it would from the environment.
+type boolFlag struct{ val string }
+
+func (b *boolFlag) String() string {
+	if b == nil {
+		return ""
+	}
+	return b.val
+}
+
+func (b *boolFlag) Set(s string) error {
+	if _, err := strconv.ParseBool(s); err != nil {
+		return err
+	}
+	b.val = s
+	return nil
+}
+
+// IsBoolFlag lets the flag be given without a value, as --verbose.
+func (b *boolFlag) IsBoolFlag() bool { return true }
+
+// FlagName converts an env key to its command-line flag name: lowercased,
+// with underscores replaced by dashes (HTTP_PORT → http-port).
+func FlagName(envKey string) string {
+	return strings.ReplaceAll(strings.ToLower(envKey), "_", "-")
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+type flagConfig struct {
+	HTTPPort int    `env:"TEST_HTTP_PORT" default:"8080" doc:"listen port"`
+	LogLevel string `env:"TEST_LOG_LEVEL" default:"info"`
+	Region   string `env:"TEST_REGION" required:"false"`
+	Verbose  bool   `env:"TEST_VERBOSE" default:"false"`
+}
+
+func newFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func TestBindFlags_Precedence(t *testing.T) {
+	t.Setenv("TEST_HTTP_PORT", "9000")
+	t.Setenv("TEST_LOG_LEVEL", "warn")
+
+	fs := newFlagSet()
+	flags := BindFlags[flagConfig](fs)
+	if err := fs.Parse([]string{"--test-http-port=9999"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load[flagConfig](WithSources(flags, Env()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.HTTPPort != 9999 {
+		t.Errorf("HTTPPort = %d, want 9999 (flag beats env)", cfg.HTTPPort)
+	}
+	if cfg.LogLevel != "warn" {
+		t.Errorf("LogLevel = %q, want warn (env beats default)", cfg.LogLevel)
+	}
+}
+
+func TestBindFlags_DefaultsWhenUnset(t *testing.T) {
+	fs := newFlagSet()
+	flags := BindFlags[flagConfig](fs)
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load[flagConfig](WithSources(flags, Env()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.HTTPPort != 8080 || cfg.LogLevel != "info" {
+		t.Errorf("cfg = %+v, want defaults", cfg)
+	}
+}
+
+func TestBindFlags_Usage(t *testing.T) {
+	fs := newFlagSet()
+	BindFlags[flagConfig](fs)
+
+	f := fs.Lookup("test-http-port")
+	if f == nil {
+		t.Fatal("flag test-http-port not registered")
+	}
+	if f.DefValue != "8080" {
+		t.Errorf("DefValue = %q, want 8080", f.DefValue)
+	}
+	if !strings.Contains(f.Usage, "listen port") || !strings.Contains(f.Usage, "TEST_HTTP_PORT") {
+		t.Errorf("Usage = %q, want doc and env name", f.Usage)
+	}
+}
+
+func TestBindFlags_BareBoolFlag(t *testing.T) {
+	t.Setenv("TEST_VERBOSE", "false")
+
+	fs := newFlagSet()
+	flags := BindFlags[flagConfig](fs)
+	if err := fs.Parse([]string{"--test-verbose", "--test-http-port", "9999"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := MustLoad[flagConfig](WithSources(flags, Env()))
+	if !cfg.Verbose || cfg.HTTPPort != 9999 {
+		t.Errorf("cfg = %+v, want Verbose and HTTPPort 9999 (bare flag takes no value)", cfg)
+	}
+
+	fs = newFlagSet()
+	flags = BindFlags[flagConfig](fs)
+	if err := fs.Parse([]string{"--test-verbose=false"}); err != nil {
+		t.Fatal(err)
+	}
+	if cfg := MustLoad[flagConfig](WithSources(flags)); cfg.Verbose {
+		t.Error("Verbose = true, want false from --test-verbose=false")
+	}
+	if err := fs.Set("test-verbose", "maybe"); err == nil {
+		t.Error("expected an error for a non-boolean value")
+	}
+}
+
+func TestBindFlags_ReusesExistingFlag(t *testing.T) {
+	fs := newFlagSet()
+	fs.Int("test-http-port", 1, "predefined")
+	flags := BindFlags[flagConfig](fs)
+	if err := fs.Parse([]string{"-test-http-port", "7000"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := MustLoad[flagConfig](WithSources(flags))
+	if cfg.HTTPPort != 7000 {
+		t.Errorf("HTTPPort = %d, want 7000", cfg.HTTPPort)
+	}
+}
+
+func TestWithSources_ReplacesEnv(t *testing.T) {
+	t.Setenv("TEST_LOG_LEVEL", "warn")
+	src := SourceFunc(func(string) (string, bool) { return "", false })
+
+	cfg := MustLoad[flagConfig](WithSources(src))
+	if cfg.LogLevel != "info" {
+		t.Errorf("LogLevel = %q, want info (env not consulted)", cfg.LogLevel)
+	}
+}
+
+func TestFlagName(t *testing.T) {
+	if got := FlagName("HTTP_PORT"); got != "http-port" {
+		t.Errorf("FlagName = %q, want http-port", got)
+	}
+}
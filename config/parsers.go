@@ -0,0 +1,64 @@
+package config
+
+import (
+	"encoding"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[reflect.Type]func(string) (reflect.Value, error){}
+)
+
+// RegisterParser teaches Load how to populate fields of type T from a raw
+// string. Registered parsers take precedence over the built-in conversions and
+// over encoding.TextUnmarshaler, so they can also override how an existing
+// type is parsed. Registering the same type twice replaces the earlier parser.
+//
+// Call RegisterParser from init or early in main, before the first Load:
+//
+//	config.RegisterParser(func(s string) (url.URL, error) {
+//		u, err := url.Parse(s)
+//		if err != nil {
+//			return url.URL{}, err
+//		}
+//		return *u, nil
+//	})
+func RegisterParser[T any](parse func(string) (T, error)) {
+	if parse == nil {
+		panic("config: RegisterParser called with nil parser")
+	}
+	t := reflect.TypeFor[T]()
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[t] = func(raw string) (reflect.Value, error) {
+		v, err := parse(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(&v).Elem(), nil
+	}
+}
+
+func lookupParser(t reflect.Type) (func(string) (reflect.Value, error), bool) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	p, ok := parsers[t]
+	return p, ok
+}
+
+// isScalar reports whether t is populated from a single value rather than
+// recursed into as a nested config struct.
+func isScalar(t reflect.Type) bool {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return true
+	}
+	if _, ok := lookupParser(t); ok {
+		return true
+	}
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
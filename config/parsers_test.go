@@ -0,0 +1,113 @@
+package config
+
+import (
+	"errors"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type logLevel int
+
+func (l *logLevel) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+func TestLoad_TextUnmarshaler(t *testing.T) {
+	type Cfg struct {
+		Addr  netip.Addr `env:"TEST_ADDR"`
+		Level logLevel   `env:"TEST_LEVEL"`
+	}
+	t.Setenv("TEST_ADDR", "10.0.0.1")
+	t.Setenv("TEST_LEVEL", "info")
+
+	cfg, err := Load[Cfg]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Addr != netip.MustParseAddr("10.0.0.1") {
+		t.Errorf("Addr = %v, want 10.0.0.1", cfg.Addr)
+	}
+	if cfg.Level != 1 {
+		t.Errorf("Level = %d, want 1", cfg.Level)
+	}
+}
+
+func TestLoad_TextUnmarshalerError(t *testing.T) {
+	type Cfg struct {
+		Level logLevel `env:"TEST_LEVEL"`
+	}
+	t.Setenv("TEST_LEVEL", "verbose")
+
+	_, err := Load[Cfg]()
+	if err == nil || !strings.Contains(err.Error(), "unknown level") {
+		t.Fatalf("expected unmarshal error, got %v", err)
+	}
+}
+
+// unregisterParser removes a test-registered parser so it does not leak into
+// other tests.
+func unregisterParser[T any](t *testing.T) {
+	t.Cleanup(func() {
+		parsersMu.Lock()
+		defer parsersMu.Unlock()
+		delete(parsers, reflect.TypeFor[T]())
+	})
+}
+
+func TestRegisterParser(t *testing.T) {
+	unregisterParser[url.URL](t)
+	unregisterParser[[]time.Duration](t)
+	RegisterParser(func(s string) (url.URL, error) {
+		u, err := url.Parse(s)
+		if err != nil {
+			return url.URL{}, err
+		}
+		return *u, nil
+	})
+	RegisterParser(func(s string) ([]time.Duration, error) {
+		var out []time.Duration
+		for _, p := range strings.Split(s, ",") {
+			d, err := time.ParseDuration(strings.TrimSpace(p))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, d)
+		}
+		return out, nil
+	})
+
+	type Cfg struct {
+		Upstream url.URL         `env:"TEST_UPSTREAM"`
+		Backoff  []time.Duration `env:"TEST_BACKOFF"`
+	}
+	t.Setenv("TEST_UPSTREAM", "https://api.example.com/v1")
+	t.Setenv("TEST_BACKOFF", "100ms, 1s")
+
+	cfg, err := Load[Cfg]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Upstream.Host != "api.example.com" || cfg.Upstream.Path != "/v1" {
+		t.Errorf("Upstream = %v, want https://api.example.com/v1", cfg.Upstream.String())
+	}
+	if len(cfg.Backoff) != 2 || cfg.Backoff[0] != 100*time.Millisecond || cfg.Backoff[1] != time.Second {
+		t.Errorf("Backoff = %v, want [100ms 1s]", cfg.Backoff)
+	}
+
+	t.Setenv("TEST_BACKOFF", "soon")
+	if _, err := Load[Cfg](); err == nil {
+		t.Fatal("expected parse error for invalid duration list")
+	}
+}
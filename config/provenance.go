@@ -0,0 +1,23 @@
+package config
+
+// Origins reported by WithProvenance for the built-in sources. FromDir sources
+// report "dir:<path>"; sources wrapped with Named report their given name.
+const (
+	OriginDefault = "default" // the field's default tag
+	OriginEnv     = "env"     // Env()
+	OriginFlags   = "flags"   // BindFlags
+	OriginSecret  = "secret"  // the configured SecretProvider
+	OriginCustom  = "custom"  // an unnamed custom Source
+)
+
+// WithProvenance makes a successful Load or MustLoad store in *dst where each
+// field got its value, keyed by dotted field path (e.g. "HTTP.Port") with
+// values such as "env", "flags", "dir:/etc/myapp", "secret", or "default".
+// Fields left at their zero value are omitted. *dst is left untouched when
+// the load fails.
+//
+//	var prov map[string]string
+//	cfg := config.MustLoad[AppConfig](config.WithProvenance(&prov))
+func WithProvenance(dst *map[string]string) Option {
+	return func(o *options) { o.provenance = dst }
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+type provConfig struct {
+	HTTP struct {
+		Port int `env:"TEST_HTTP_PORT" default:"8080"`
+	}
+	Host     string `env:"TEST_HOST"`
+	Region   string `env:"TEST_REGION"`
+	Level    string `env:"TEST_LEVEL"`
+	Password string `env:"TEST_PASSWORD" secret:"password"`
+	Optional string `env:"TEST_OPTIONAL" required:"false"`
+}
+
+func TestProvenance(t *testing.T) {
+	t.Setenv("TEST_HOST", "env-host")
+	t.Setenv("TEST_REGION", "env-region")
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "TEST_REGION"), "dir-region")
+	writeFile(t, filepath.Join(dir, "TEST_LEVEL"), "debug")
+	dirSrc, err := FromDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	override := Named("override", SourceFunc(func(key string) (string, bool) {
+		if key == "TEST_HOST" {
+			return "override-host", true
+		}
+		return "", false
+	}))
+	secrets := SecretProviderFunc(func(context.Context, string) (string, error) {
+		return "s3cret", nil
+	})
+
+	var got map[string]string
+	_, err = Load[provConfig](
+		WithSources(override, Multi(Env(), dirSrc)),
+		WithSecretProvider(secrets),
+		WithProvenance(&got),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"HTTP.Port": OriginDefault,
+		"Host":      "override",
+		"Region":    OriginEnv,
+		"Level":     "dir:" + dir,
+		"Password":  OriginSecret,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Provenance = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Provenance[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestProvenance_PerLoad(t *testing.T) {
+	type Cfg struct {
+		Name string `env:"TEST_NAME"`
+	}
+	src := SourceFunc(func(string) (string, bool) { return "x", true })
+	var custom, env map[string]string
+	MustLoad[Cfg](WithSources(src), WithProvenance(&custom))
+	t.Setenv("TEST_NAME", "y")
+	MustLoad[Cfg](WithProvenance(&env))
+
+	// A second load of the same type must not change the first's result.
+	if custom["Name"] != OriginCustom || env["Name"] != OriginEnv {
+		t.Errorf("Provenance[Name] = %q and %q, want %q and %q", custom["Name"], env["Name"], OriginCustom, OriginEnv)
+	}
+}
+
+func TestProvenance_NotSetOnFailure(t *testing.T) {
+	type Cfg struct {
+		Name     string `env:"TEST_NAME"`
+		Required string `env:"TEST_PROV_REQUIRED"`
+	}
+	t.Setenv("TEST_NAME", "x")
+	var prov map[string]string
+	if _, err := Load[Cfg](WithProvenance(&prov)); err == nil {
+		t.Fatal("expected a LoadError for the missing required field")
+	}
+	if prov != nil {
+		t.Errorf("Provenance after a failed load = %v, want nil", prov)
+	}
+}
+
+func TestMulti_Lookup(t *testing.T) {
+	a := SourceFunc(func(key string) (string, bool) { return "", key == "K" })
+	b := SourceFunc(func(key string) (string, bool) { return "from-b", key == "K" })
+	if v, ok := Multi(a, b).Lookup("K"); !ok || v != "from-b" {
+		t.Errorf("Lookup = %q, %v; want from-b, true (empty values fall through)", v, ok)
+	}
+	if _, ok := Multi(a, b).Lookup("OTHER"); ok {
+		t.Error("Lookup(OTHER) should not be found")
+	}
+}
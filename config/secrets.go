@@ -0,0 +1,54 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrSecretNotFound is returned by a SecretProvider when it has no value for
+// the requested name. Load treats it as "fall back to the env var" rather than
+// as a failure.
+var ErrSecretNotFound = errors.New("config: secret not found")
+
+// SecretProvider resolves named secrets at load time. Implementations can
+// front Vault, AWS Secrets Manager, GCP Secret Manager, or any other store;
+// wrap an SDK client with SecretProviderFunc to plug it in.
+//
+// Secret must return ErrSecretNotFound (possibly wrapped) when the name is
+// unknown so that Load can fall back to the field's env var. Any other error
+// is reported as a FieldError.
+type SecretProvider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// SecretProviderFunc adapts an ordinary function to the SecretProvider interface.
+type SecretProviderFunc func(ctx context.Context, name string) (string, error)
+
+// Secret calls f(ctx, name).
+func (f SecretProviderFunc) Secret(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+// FileSecrets returns a SecretProvider that reads each secret from a file
+// named after it inside dir — the layout Kubernetes uses for Secret volume
+// mounts (e.g. /var/run/secrets/app/db-password). Trailing newlines are
+// trimmed. Names containing path separators are rejected.
+func FileSecrets(dir string) SecretProvider {
+	return SecretProviderFunc(func(_ context.Context, name string) (string, error) {
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return "", fmt.Errorf("config: invalid secret name %q", name)
+		}
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			return "", ErrSecretNotFound
+		}
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	})
+}
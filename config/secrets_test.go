@@ -0,0 +1,115 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type secretConfig struct {
+	DBPassword string `env:"TEST_DB_PASSWORD" secret:"db-password"`
+	APIKey     string `secret:"api-key" required:"false"`
+}
+
+func TestLoad_SecretFromProvider(t *testing.T) {
+	t.Setenv("TEST_DB_PASSWORD", "from-env")
+	p := SecretProviderFunc(func(_ context.Context, name string) (string, error) {
+		switch name {
+		case "db-password":
+			return "from-vault", nil
+		case "api-key":
+			return "key-123", nil
+		}
+		return "", ErrSecretNotFound
+	})
+
+	cfg, err := Load[secretConfig](WithSecretProvider(p))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DBPassword != "from-vault" {
+		t.Errorf("DBPassword = %q, want %q", cfg.DBPassword, "from-vault")
+	}
+	if cfg.APIKey != "key-123" {
+		t.Errorf("APIKey = %q, want %q", cfg.APIKey, "key-123")
+	}
+}
+
+func TestLoad_SecretFallsBackToEnv(t *testing.T) {
+	t.Setenv("TEST_DB_PASSWORD", "from-env")
+	p := SecretProviderFunc(func(context.Context, string) (string, error) {
+		return "", ErrSecretNotFound
+	})
+
+	cfg, err := Load[secretConfig](WithSecretProvider(p))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DBPassword != "from-env" {
+		t.Errorf("DBPassword = %q, want %q", cfg.DBPassword, "from-env")
+	}
+}
+
+func TestLoad_SecretWithoutProviderUsesEnv(t *testing.T) {
+	t.Setenv("TEST_DB_PASSWORD", "from-env")
+
+	cfg := MustLoad[secretConfig]()
+	if cfg.DBPassword != "from-env" {
+		t.Errorf("DBPassword = %q, want %q", cfg.DBPassword, "from-env")
+	}
+}
+
+func TestLoad_SecretProviderError(t *testing.T) {
+	t.Setenv("TEST_DB_PASSWORD", "from-env")
+	boom := errors.New("vault sealed")
+	p := SecretProviderFunc(func(context.Context, string) (string, error) {
+		return "", boom
+	})
+
+	_, err := Load[secretConfig](WithSecretProvider(p))
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected provider error to be wrapped, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "db-password") {
+		t.Errorf("error %q should mention the secret name", err)
+	}
+}
+
+func TestLoad_MissingSecretOnlyField(t *testing.T) {
+	type Cfg struct {
+		Token string `secret:"token"`
+	}
+	_, err := Load[Cfg](WithSecretProvider(FileSecrets(t.TempDir())))
+	if !errors.Is(err, ErrMissing) {
+		t.Fatalf("expected ErrMissing, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `secret "token"`) {
+		t.Errorf("error %q should mention the secret name", err)
+	}
+}
+
+func TestFileSecrets(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "db-password"), []byte("hunter2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p := FileSecrets(dir)
+
+	val, err := p.Secret(context.Background(), "db-password")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if val != "hunter2" {
+		t.Errorf("val = %q, want %q", val, "hunter2")
+	}
+
+	if _, err := p.Secret(context.Background(), "missing"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("missing file: err = %v, want ErrSecretNotFound", err)
+	}
+	if _, err := p.Secret(context.Background(), "../etc/passwd"); err == nil || errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("traversal name: err = %v, want invalid name error", err)
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Source supplies raw configuration values keyed by env tag name. Load
+// consults its sources in order and uses the first non-empty value; the
+// default tag applies when no source has one.
+type Source interface {
+	Lookup(key string) (value string, ok bool)
+}
+
+// SourceFunc adapts an ordinary function to the Source interface.
+type SourceFunc func(key string) (string, bool)
+
+// Lookup calls f(key).
+func (f SourceFunc) Lookup(key string) (string, bool) { return f(key) }
+
+// Env returns a Source backed by the process environment. It is the only
+// source Load uses unless WithSources is given.
+func Env() Source {
+	return Named(OriginEnv, SourceFunc(os.LookupEnv))
+}
+
+// Named attaches a name to src. The name is what WithProvenance reports for
+// fields the source supplied; unnamed custom sources report "custom".
+func Named(name string, src Source) Source {
+	return namedSource{name: name, Source: src}
+}
+
+type namedSource struct {
+	name string
+	Source
+}
+
+func (n namedSource) Name() string { return n.name }
+
+// Multi layers srcs into a single Source: the first source with a non-empty
+// value wins. WithProvenance still attributes each value to the individual
+// source inside the stack that supplied it.
+func Multi(srcs ...Source) Source {
+	return multiSource(srcs)
+}
+
+type multiSource []Source
+
+func (m multiSource) Lookup(key string) (string, bool) {
+	val, _, ok := lookupSource(m, key)
+	return val, ok
+}
+
+// lookupSource resolves key through src, descending into Multi stacks, and
+// returns the name of the source that supplied the value.
+func lookupSource(src Source, key string) (val, origin string, ok bool) {
+	if m, isMulti := src.(multiSource); isMulti {
+		for _, s := range m {
+			if val, origin, ok := lookupSource(s, key); ok {
+				return val, origin, true
+			}
+		}
+		return "", "", false
+	}
+	val, ok = src.Lookup(key)
+	if !ok || val == "" {
+		return "", "", false
+	}
+	origin = OriginCustom
+	if n, isNamed := src.(interface{ Name() string }); isNamed {
+		origin = n.Name()
+	}
+	return val, origin, true
+}
+
+// WithSources replaces the default environment source with srcs, consulted in
+// order. Include Env() explicitly to keep reading environment variables:
+//
+//	config.Load[AppConfig](config.WithSources(flagSrc, config.Env()))
+func WithSources(srcs ...Source) Option {
+	return func(o *options) { o.sources = srcs }
+}
+
+// FromDir returns a Source that treats each regular file in dir as one key,
+// named after the file, with the file contents (minus trailing newlines) as
+// the value. This is the layout Kubernetes uses for ConfigMap and Secret
+// volume mounts. Hidden entries such as the "..data" symlink Kubernetes
+// maintains are skipped, as are subdirectories.
+//
+// The directory is read once, when FromDir is called. Layer it with the
+// environment so per-pod env vars override the mounted files:
+//
+//	dir, err := config.FromDir("/etc/myapp")
+//	cfg := config.MustLoad[AppConfig](config.WithSources(config.Env(), dir))
+func FromDir(dir string) (Source, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("config: read dir %s: %w", dir, err)
+	}
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		// Stat follows the symlinks Kubernetes uses for projected keys.
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: stat %s: %w", path, err)
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: read %s: %w", path, err)
+		}
+		values[name] = strings.TrimRight(string(b), "\r\n")
+	}
+	return Named("dir:"+dir, SourceFunc(func(key string) (string, bool) {
+		v, ok := values[key]
+		return v, ok
+	})), nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFromDir(t *testing.T) {
+	// Mimic a Kubernetes projected volume: keys are symlinks into ..data.
+	dir := t.TempDir()
+	data := filepath.Join(dir, "..2026_10_16")
+	if err := os.Mkdir(data, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(data, "TEST_HOST"), "db.internal\n")
+	writeFile(t, filepath.Join(data, "TEST_PORT"), "5432")
+	if err := os.Symlink(filepath.Base(data), filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"TEST_HOST", "TEST_PORT"} {
+		if err := os.Symlink(filepath.Join("..data", key), filepath.Join(dir, key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := FromDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := src.Lookup("TEST_HOST"); !ok || v != "db.internal" {
+		t.Errorf("TEST_HOST = %q, %v; want db.internal, true", v, ok)
+	}
+	for _, key := range []string{"..data", "nested", "MISSING"} {
+		if _, ok := src.Lookup(key); ok {
+			t.Errorf("Lookup(%q) should not be found", key)
+		}
+	}
+
+	type Cfg struct {
+		Host string `env:"TEST_HOST"`
+		Port int    `env:"TEST_PORT"`
+	}
+	t.Setenv("TEST_PORT", "6543")
+	cfg := MustLoad[Cfg](WithSources(Env(), src))
+	if cfg.Host != "db.internal" {
+		t.Errorf("Host = %q, want db.internal (from dir)", cfg.Host)
+	}
+	if cfg.Port != 6543 {
+		t.Errorf("Port = %d, want 6543 (env overrides dir)", cfg.Port)
+	}
+}
+
+func TestFromDir_MissingDir(t *testing.T) {
+	if _, err := FromDir(filepath.Join(t.TempDir(), "nope")); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+}
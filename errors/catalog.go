@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// catalogEntry is a registered error type.
+type catalogEntry struct {
+	httpCode int
+	grpcCode codes.Code
+	title    string
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = map[string]catalogEntry{}
+)
+
+// Register adds a service-specific error type to the catalog and returns a
+// constructor for it, usable directly or with Errorf:
+//
+//	var QuotaExceeded = errors.Register("quota-exceeded", 429, codes.ResourceExhausted, "Quota Exceeded")
+//	return QuotaExceeded("monthly quota of 1000 requests used")
+//
+// name is either a slug, which is placed under the chassis type base URI, or
+// an absolute type URI. Errors of the type, including those given it with
+// WithType, report title in their Problem Details. Register is meant to be
+// called at package initialization and panics if the type is already
+// registered or collides with a built-in type.
+func Register(name string, httpCode int, grpcCode codes.Code, title string) func(msg string) *ServiceError {
+	typeURI := name
+	if !strings.Contains(name, ":") {
+		typeURI = typeBaseURI + name
+	}
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if _, dup := catalog[typeURI]; dup || isBuiltinType(typeURI) {
+		panic(fmt.Sprintf("errors: type %q already registered", typeURI))
+	}
+	catalog[typeURI] = catalogEntry{httpCode: httpCode, grpcCode: grpcCode, title: title}
+
+	return func(msg string) *ServiceError {
+		return &ServiceError{Message: msg, GRPCCode: grpcCode, HTTPCode: httpCode, typeURI: typeURI}
+	}
+}
+
+// registeredTitle returns the title of a registered type URI.
+func registeredTitle(typeURI string) (string, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	e, ok := catalog[typeURI]
+	return e.title, ok
+}
+
+// isBuiltinType reports whether typeURI is one of the default type URIs.
+func isBuiltinType(typeURI string) bool {
+	for _, uri := range typeURIs {
+		if uri == typeURI {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+const testCardDeclinedURI = "https://billing.example.com/errors/test-card-declined"
+
+// Types are registered once per process, as services would at init time.
+var (
+	quotaExceeded = Register("test-quota-exceeded", http.StatusTooManyRequests, codes.ResourceExhausted, "Quota Exceeded")
+	_             = Register(testCardDeclinedURI, http.StatusPaymentRequired, codes.FailedPrecondition, "Card Declined")
+)
+
+func TestRegisterConstructor(t *testing.T) {
+	err := quotaExceeded("monthly quota used")
+	if err.HTTPCode != http.StatusTooManyRequests || err.GRPCCode != codes.ResourceExhausted {
+		t.Errorf("codes = %d / %v", err.HTTPCode, err.GRPCCode)
+	}
+	pd := err.ProblemDetail(nil)
+	if pd.Type != typeBaseURI+"test-quota-exceeded" {
+		t.Errorf("Type = %q", pd.Type)
+	}
+	if pd.Title != "Quota Exceeded" {
+		t.Errorf("Title = %q, want registered title", pd.Title)
+	}
+	info := err.GRPCStatus().Details()[0].(*errdetails.ErrorInfo)
+	if info.Reason != "TEST_QUOTA_EXCEEDED" {
+		t.Errorf("Reason = %q", info.Reason)
+	}
+
+	formatted := Errorf(quotaExceeded, "used %d of %d", 1000, 1000)
+	if formatted.Message != "used 1000 of 1000" || formatted.ProblemDetail(nil).Title != "Quota Exceeded" {
+		t.Errorf("Errorf with registered factory = %+v", formatted)
+	}
+}
+
+func TestRegisterAbsoluteURIAndWithType(t *testing.T) {
+	uri := testCardDeclinedURI
+	// WithType on any error picks up the registered title.
+	pd := ValidationError("declined").WithType(uri).ProblemDetail(nil)
+	if pd.Type != uri || pd.Title != "Card Declined" {
+		t.Errorf("ProblemDetail = %+v", pd)
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	for _, name := range []string{"test-quota-exceeded", "validation"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) should panic", name)
+				}
+			}()
+			Register(name, http.StatusConflict, codes.Aborted, "Again")
+		}()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	stderrors "errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -18,6 +19,10 @@ type ServiceError struct {
 	Details  map[string]any
 	cause    error
 	typeURI  string // custom RFC 9457 type URI (optional)
+
+	retryable  *bool         // nil means not set
+	retryAfter time.Duration // zero means not set
+	header     http.Header   // response headers for WriteProblem (optional)
 }
 
 // Error implements the error interface.
@@ -30,9 +35,17 @@ func (e *ServiceError) Unwrap() error {
 	return e.cause
 }
 
-// GRPCStatus returns a gRPC status for this error.
+// GRPCStatus returns a gRPC status for this error. The status carries
+// google.rpc ErrorInfo, BadRequest and RetryInfo details equivalent to the
+// Problem Details HTTP response, so clients get structured errors on both
+// transports. Under SetExposure(Production), internal and dependency errors
+// carry a generic message and no Details.
 func (e *ServiceError) GRPCStatus() *status.Status {
-	return status.New(e.GRPCCode, e.Message)
+	msg := e.Message
+	if e.redacted() {
+		msg = redactedMessage
+	}
+	return withDetails(status.New(e.GRPCCode, msg), e.grpcDetails())
 }
 
 // WithDetail returns a copy of the error with the given detail key-value pair added.
@@ -66,6 +79,23 @@ func (e *ServiceError) WithType(uri string) *ServiceError {
 	return out
 }
 
+// WithHeader returns a copy of the error with a response header value added,
+// e.g. WithHeader("WWW-Authenticate", `Bearer realm="api"`) on a 401.
+// WriteProblem emits the headers; values for the same key accumulate.
+func (e *ServiceError) WithHeader(key, value string) *ServiceError {
+	out := e.clone()
+	if out.header == nil {
+		out.header = make(http.Header)
+	}
+	out.header.Add(key, value)
+	return out
+}
+
+// Header returns a copy of the response headers set with WithHeader.
+func (e *ServiceError) Header() http.Header {
+	return e.header.Clone()
+}
+
 // WithCause returns a copy of the error with the underlying error cause set for Unwrap chaining.
 func (e *ServiceError) WithCause(err error) *ServiceError {
 	out := e.clone()
@@ -73,9 +103,11 @@ func (e *ServiceError) WithCause(err error) *ServiceError {
 	return out
 }
 
-// clone returns a shallow copy of the ServiceError with a deep-copied Details map.
+// clone returns a shallow copy of the ServiceError with deep-copied Details
+// and headers.
 func (e *ServiceError) clone() *ServiceError {
 	out := *e
+	out.header = e.header.Clone()
 	if e.Details != nil {
 		out.Details = make(map[string]any, len(e.Details))
 		for k, v := range e.Details {
@@ -132,6 +164,36 @@ func InternalError(msg string) *ServiceError {
 	return &ServiceError{Message: msg, GRPCCode: codes.Internal, HTTPCode: http.StatusInternalServerError}
 }
 
+// ConflictError creates an error for conflicting state such as duplicates (409 / ALREADY_EXISTS).
+func ConflictError(msg string) *ServiceError {
+	return &ServiceError{Message: msg, GRPCCode: codes.AlreadyExists, HTTPCode: http.StatusConflict}
+}
+
+// PreconditionFailedError creates an error for failed preconditions such as a stale ETag (412 / FAILED_PRECONDITION).
+func PreconditionFailedError(msg string) *ServiceError {
+	return &ServiceError{Message: msg, GRPCCode: codes.FailedPrecondition, HTTPCode: http.StatusPreconditionFailed}
+}
+
+// NotImplementedError creates an error for unsupported operations (501 / UNIMPLEMENTED).
+func NotImplementedError(msg string) *ServiceError {
+	return &ServiceError{Message: msg, GRPCCode: codes.Unimplemented, HTTPCode: http.StatusNotImplemented}
+}
+
+// UnavailableForLegalReasonsError creates an error for legally blocked resources (451 / PERMISSION_DENIED).
+func UnavailableForLegalReasonsError(msg string) *ServiceError {
+	return &ServiceError{Message: msg, GRPCCode: codes.PermissionDenied, HTTPCode: http.StatusUnavailableForLegalReasons}
+}
+
+// TooEarlyError creates an error for requests that must not be processed yet, such as replayed early data (425 / UNAVAILABLE).
+func TooEarlyError(msg string) *ServiceError {
+	return &ServiceError{Message: msg, GRPCCode: codes.Unavailable, HTTPCode: http.StatusTooEarly}
+}
+
+// UnsupportedMediaTypeError creates an error for request bodies in a format the endpoint does not accept (415 / INVALID_ARGUMENT).
+func UnsupportedMediaTypeError(msg string) *ServiceError {
+	return &ServiceError{Message: msg, GRPCCode: codes.InvalidArgument, HTTPCode: http.StatusUnsupportedMediaType}
+}
+
 // --- Helpers ---
 
 // FromError converts any error to a ServiceError. If the error is already
@@ -147,6 +209,27 @@ func FromError(err error) *ServiceError {
 	return InternalError("an internal error occurred").WithCause(err)
 }
 
+// Wrap adds context to err without losing its classification. If err has a
+// ServiceError in its chain, Wrap returns a copy of it with the formatted
+// context prefixed to its message ("loading order 7: not found") and err as
+// its cause, so the HTTP and gRPC codes, type, details and retry metadata
+// survive. Otherwise it behaves like fmt.Errorf("...: %w", err). Wrap
+// returns nil if err is nil.
+func Wrap(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	prefix := fmt.Sprintf(format, args...)
+	var se *ServiceError
+	if !stderrors.As(err, &se) {
+		return fmt.Errorf("%s: %w", prefix, err)
+	}
+	out := se.clone()
+	out.Message = prefix + ": " + se.Message
+	out.cause = err
+	return out
+}
+
 // Errorf creates a formatted ServiceError using the given factory.
 func Errorf(factory func(string) *ServiceError, format string, args ...any) *ServiceError {
 	return factory(fmt.Sprintf(format, args...))
@@ -105,6 +105,109 @@ func TestInternalError(t *testing.T) {
 	}
 }
 
+func TestAdditionalConstructors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *ServiceError
+		httpCode int
+		grpcCode codes.Code
+		typeSlug string
+		title    string
+	}{
+		{"conflict", ConflictError("exists"), http.StatusConflict, codes.AlreadyExists, "conflict", "Conflict"},
+		{"precondition", PreconditionFailedError("stale etag"), http.StatusPreconditionFailed, codes.FailedPrecondition, "precondition-failed", "Precondition Failed"},
+		{"not implemented", NotImplementedError("soon"), http.StatusNotImplemented, codes.Unimplemented, "not-implemented", "Not Implemented"},
+		{"legal", UnavailableForLegalReasonsError("blocked"), http.StatusUnavailableForLegalReasons, codes.PermissionDenied, "unavailable-for-legal-reasons", "Unavailable For Legal Reasons"},
+		{"too early", TooEarlyError("replay"), http.StatusTooEarly, codes.Unavailable, "too-early", "Too Early"},
+		{"media type", UnsupportedMediaTypeError("want JSON"), http.StatusUnsupportedMediaType, codes.InvalidArgument, "unsupported-media-type", "Unsupported Media Type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.HTTPCode != tt.httpCode {
+				t.Errorf("HTTPCode = %d, want %d", tt.err.HTTPCode, tt.httpCode)
+			}
+			if tt.err.GRPCCode != tt.grpcCode {
+				t.Errorf("GRPCCode = %v, want %v", tt.err.GRPCCode, tt.grpcCode)
+			}
+			pd := tt.err.ProblemDetail(nil)
+			if pd.Type != "https://chassis.ai8future.com/errors/"+tt.typeSlug {
+				t.Errorf("Type = %q", pd.Type)
+			}
+			if pd.Title != tt.title {
+				t.Errorf("Title = %q, want %q", pd.Title, tt.title)
+			}
+		})
+	}
+}
+
+func TestWithHeaderWrittenByWriteProblem(t *testing.T) {
+	err := UnauthorizedError("token expired").
+		WithHeader("WWW-Authenticate", `Bearer realm="api"`).
+		WithHeader("WWW-Authenticate", `Basic realm="api"`).
+		WithHeader("Content-Type", "text/plain")
+
+	rec := httptest.NewRecorder()
+	WriteProblem(rec, httptest.NewRequest("GET", "/", nil), err, "")
+	if got := rec.Header().Values("WWW-Authenticate"); len(got) != 2 || got[0] != `Bearer realm="api"` {
+		t.Errorf("WWW-Authenticate = %v", got)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, must not be overridden", ct)
+	}
+}
+
+func TestWithHeaderDoesNotMutateOriginal(t *testing.T) {
+	orig := RateLimitError("slow down").WithHeader("X-RateLimit-Limit", "100")
+	_ = orig.WithHeader("X-RateLimit-Limit", "200")
+	if got := orig.Header().Values("X-RateLimit-Limit"); len(got) != 1 {
+		t.Errorf("original headers = %v, want unchanged", got)
+	}
+	orig.Header().Set("X-RateLimit-Limit", "0")
+	if orig.Header().Get("X-RateLimit-Limit") != "100" {
+		t.Error("Header() should return a copy")
+	}
+}
+
+func TestWrapPreservesClassification(t *testing.T) {
+	base := NotFoundError("not found").WithDetail("id", 7).WithRetryable(false)
+	err := Wrap(fmt.Errorf("repo: %w", base), "loading order %d", 7)
+
+	var se *ServiceError
+	if !errors.As(err, &se) {
+		t.Fatalf("Wrap returned %T, want *ServiceError", err)
+	}
+	if se.Message != "loading order 7: not found" {
+		t.Errorf("Message = %q", se.Message)
+	}
+	if se.HTTPCode != http.StatusNotFound || se.GRPCCode != codes.NotFound {
+		t.Errorf("codes = %d / %v, want 404 / NotFound", se.HTTPCode, se.GRPCCode)
+	}
+	if se.Details["id"] != 7 || se.Retryable() {
+		t.Errorf("details or retry metadata lost: %+v", se)
+	}
+	if !errors.Is(err, base) {
+		t.Error("wrapped error should remain in the chain")
+	}
+	if base.Message != "not found" {
+		t.Error("original should not be modified")
+	}
+}
+
+func TestWrapPlainError(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := Wrap(cause, "loading order %d", 7)
+	if err.Error() != "loading order 7: connection reset" || !errors.Is(err, cause) {
+		t.Errorf("Wrap = %v", err)
+	}
+	var se *ServiceError
+	if errors.As(err, &se) {
+		t.Error("plain errors should not become ServiceErrors")
+	}
+	if Wrap(nil, "noop") != nil {
+		t.Error("Wrap(nil) should return nil")
+	}
+}
+
 func TestErrorInterface(t *testing.T) {
 	var err error = ValidationError("test")
 	if err.Error() != "test" {
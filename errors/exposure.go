@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync/atomic"
+)
+
+// Exposure controls how much of a server-side error's message reaches
+// clients.
+type Exposure int32
+
+const (
+	// Development sends every error's message and details to clients. It is
+	// the default.
+	Development Exposure = iota
+	// Production replaces the message and details of internal (500) and
+	// dependency (503) errors in client responses with a generic message
+	// and a correlation ID. The full message is still returned by Error()
+	// and logged by WriteProblem under the same correlation ID.
+	Production
+)
+
+// redactedMessage is the client-facing message of redacted errors.
+const redactedMessage = "an internal error occurred"
+
+var exposure atomic.Int32
+
+// SetExposure sets the process-wide exposure mode. Call it once at startup,
+// e.g. SetExposure(Production) outside of development environments.
+func SetExposure(e Exposure) {
+	exposure.Store(int32(e))
+}
+
+// redacted reports whether the error's message and details must be hidden
+// from clients under the current exposure mode.
+func (e *ServiceError) redacted() bool {
+	if Exposure(exposure.Load()) != Production {
+		return false
+	}
+	return e.HTTPCode == http.StatusInternalServerError || e.HTTPCode == http.StatusServiceUnavailable
+}
+
+// redact replaces the detail and extensions of pd with the generic message
+// and correlationID, keeping only the retryable flag.
+func redact(pd *ProblemDetail, correlationID string) {
+	pd.Detail = redactedMessage
+	ext := map[string]any{"correlation_id": correlationID}
+	if v, ok := pd.Extensions["retryable"]; ok {
+		ext["retryable"] = v
+	}
+	pd.Extensions = ext
+}
+
+// newCorrelationID returns a random 16-character hex ID.
+func newCorrelationID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
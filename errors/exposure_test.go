@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func setExposure(t *testing.T, e Exposure) {
+	t.Helper()
+	SetExposure(e)
+	t.Cleanup(func() { SetExposure(Development) })
+}
+
+func writeProblemBody(t *testing.T, err error, requestID string) map[string]any {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	WriteProblem(rec, httptest.NewRequest(http.MethodGet, "/orders", nil), err, requestID)
+	var body map[string]any
+	if decErr := json.Unmarshal(rec.Body.Bytes(), &body); decErr != nil {
+		t.Fatal(decErr)
+	}
+	return body
+}
+
+func TestProductionRedactsInternalErrors(t *testing.T) {
+	setExposure(t, Production)
+	err := InternalError("pq: relation \"orders\" does not exist").WithDetail("query", "SELECT *")
+
+	body := writeProblemBody(t, err, "")
+	if body["detail"] != redactedMessage {
+		t.Errorf("detail = %v, want generic message", body["detail"])
+	}
+	if _, ok := body["query"]; ok {
+		t.Error("details should be removed")
+	}
+	if id, _ := body["correlation_id"].(string); len(id) != 16 {
+		t.Errorf("correlation_id = %v, want generated ID", body["correlation_id"])
+	}
+	if err.Error() != "pq: relation \"orders\" does not exist" {
+		t.Errorf("Error() = %q, full message must be preserved", err.Error())
+	}
+}
+
+func TestProductionCorrelationIDUsesRequestID(t *testing.T) {
+	setExposure(t, Production)
+	body := writeProblemBody(t, DependencyError("redis: connection refused").WithRetryable(true), "req-123")
+	if body["correlation_id"] != "req-123" || body["request_id"] != "req-123" {
+		t.Errorf("body = %v", body)
+	}
+	if body["retryable"] != true {
+		t.Error("retryable flag should survive redaction")
+	}
+}
+
+func TestProductionKeepsClientErrors(t *testing.T) {
+	setExposure(t, Production)
+	body := writeProblemBody(t, ValidationError("email is required"), "")
+	if body["detail"] != "email is required" {
+		t.Errorf("detail = %v, 4xx messages are not redacted", body["detail"])
+	}
+	if _, ok := body["correlation_id"]; ok {
+		t.Error("unexpected correlation_id")
+	}
+}
+
+func TestProductionRedactsGRPCStatus(t *testing.T) {
+	setExposure(t, Production)
+	st := InternalError("secret dsn").WithDetail("host", "db-1").GRPCStatus()
+	if st.Message() != redactedMessage {
+		t.Errorf("message = %q", st.Message())
+	}
+	info := st.Details()[0].(*errdetails.ErrorInfo)
+	if _, ok := info.Metadata["host"]; ok {
+		t.Error("details should not reach gRPC metadata")
+	}
+}
+
+func TestDevelopmentExposesMessages(t *testing.T) {
+	body := writeProblemBody(t, InternalError("boom"), "")
+	if body["detail"] != "boom" {
+		t.Errorf("detail = %v", body["detail"])
+	}
+}
@@ -0,0 +1,212 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// grpcDetails builds the google.rpc detail messages that mirror the Problem
+// Details HTTP response:
+//   - ErrorInfo carries the problem type as reason and domain, the type URI
+//     and HTTP status, and every scalar entry of Details as metadata.
+//   - BadRequest carries the field violations from Validation, or one
+//     when Details has a "field" entry, described by the "reason" entry or
+//     the message, with the "rule" entry as its reason.
+//   - RetryInfo carries the WithRetryAfter delay.
+func (e *ServiceError) grpcDetails() []protoadapt.MessageV1 {
+	typeURI := e.resolvedType()
+	info := &errdetails.ErrorInfo{
+		Reason: typeReason(typeURI),
+		Domain: typeDomain(typeURI),
+		Metadata: map[string]string{
+			"type":        typeURI,
+			"http_status": strconv.Itoa(e.HTTPCode),
+		},
+	}
+	redacted := e.redacted()
+	details := e.Details
+	if redacted {
+		details = nil
+	}
+	for k, v := range details {
+		switch v.(type) {
+		case string, bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+			if _, reserved := info.Metadata[k]; !reserved {
+				info.Metadata[k] = fmt.Sprint(v)
+			}
+		}
+	}
+	if e.hasRetryInfo() {
+		info.Metadata["retryable"] = strconv.FormatBool(e.Retryable())
+	}
+	msgs := []protoadapt.MessageV1{info}
+
+	if violations := e.fieldViolations(); len(violations) > 0 && !redacted {
+		msgs = append(msgs, &errdetails.BadRequest{FieldViolations: violations})
+	}
+	if e.retryAfter > 0 {
+		msgs = append(msgs, &errdetails.RetryInfo{RetryDelay: durationpb.New(e.retryAfter)})
+	}
+	return msgs
+}
+
+// fieldViolations returns the BadRequest field violations described by
+// Details: the "errors" []FieldError set by Validation, or a single "field"
+// entry with its "reason" and "rule".
+func (e *ServiceError) fieldViolations() []*errdetails.BadRequest_FieldViolation {
+	if fields, ok := e.Details["errors"].([]FieldError); ok {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(fields))
+		for i, f := range fields {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Detail}
+		}
+		return violations
+	}
+	field, ok := e.Details["field"].(string)
+	if !ok || field == "" {
+		return nil
+	}
+	desc, _ := e.Details["reason"].(string)
+	if desc == "" {
+		desc = e.Message
+	}
+	rule, _ := e.Details["rule"].(string)
+	return []*errdetails.BadRequest_FieldViolation{{Field: field, Description: desc, Reason: rule}}
+}
+
+// typeReason derives an UPPER_SNAKE_CASE ErrorInfo reason from the last path
+// segment of a problem type URI, e.g. ".../rate-limit" becomes "RATE_LIMIT".
+func typeReason(typeURI string) string {
+	slug := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(typeSlug(typeURI)))
+	if slug == "" {
+		return "UNKNOWN"
+	}
+	return slug
+}
+
+// typeSlug returns the last path segment of a problem type URI, e.g.
+// "rate-limit".
+func typeSlug(typeURI string) string {
+	if i := strings.LastIndexAny(typeURI, "/:#"); i >= 0 {
+		return typeURI[i+1:]
+	}
+	return typeURI
+}
+
+// typeDomain returns the host of a problem type URI, falling back to the
+// chassis domain for relative or opaque URIs.
+func typeDomain(typeURI string) string {
+	if u, err := url.Parse(typeURI); err == nil && u.Host != "" {
+		return u.Host
+	}
+	u, _ := url.Parse(typeBaseURI)
+	return u.Host
+}
+
+// withDetails attaches details to st, returning st unchanged if they cannot
+// be marshalled.
+func withDetails(st *status.Status, details []protoadapt.MessageV1) *status.Status {
+	if rich, err := st.WithDetails(details...); err == nil {
+		return rich
+	}
+	return st
+}
+
+// httpCodes maps gRPC codes to HTTP status codes, following the mapping used
+// by grpc-gateway.
+var httpCodes = map[codes.Code]int{
+	codes.Canceled:           499, // client closed request
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// FromGRPCError converts an error returned by a gRPC call into a
+// ServiceError, so HTTP frontends proxying gRPC backends can write correct
+// Problem Details. The HTTP code is mapped from the status code, unless the
+// status carries chassis ErrorInfo naming the original HTTP status. Decoded
+// details are restored: the ErrorInfo type URI and metadata, BadRequest field
+// violations as the "errors" detail, and the RetryInfo delay. The original
+// error is kept as the cause. A ServiceError in err's chain is returned
+// as-is; nil and OK statuses return nil.
+func FromGRPCError(err error) *ServiceError {
+	if err == nil {
+		return nil
+	}
+	var se *ServiceError
+	if stderrors.As(err, &se) {
+		return se
+	}
+	st, _ := status.FromError(err)
+	if st.Code() == codes.OK {
+		return nil
+	}
+
+	httpCode, ok := httpCodes[st.Code()]
+	if !ok {
+		httpCode = http.StatusInternalServerError
+	}
+	out := &ServiceError{Message: st.Message(), GRPCCode: st.Code(), HTTPCode: httpCode, cause: err}
+	for _, d := range st.Details() {
+		switch v := d.(type) {
+		case *errdetails.ErrorInfo:
+			out.applyErrorInfo(v)
+		case *errdetails.BadRequest:
+			fields := make([]FieldError, len(v.GetFieldViolations()))
+			for i, fv := range v.GetFieldViolations() {
+				fields[i] = FieldError{Field: fv.GetField(), Detail: fv.GetDescription()}
+			}
+			if len(fields) > 0 {
+				out = out.WithDetail("errors", fields)
+			}
+		case *errdetails.RetryInfo:
+			out.retryAfter = v.GetRetryDelay().AsDuration()
+		}
+	}
+	return out
+}
+
+// applyErrorInfo restores the type, HTTP status, retryable flag and details
+// that grpcDetails encoded into info.
+func (e *ServiceError) applyErrorInfo(info *errdetails.ErrorInfo) {
+	for k, v := range info.GetMetadata() {
+		switch k {
+		case "type":
+			e.typeURI = v
+		case "http_status":
+			if code, err := strconv.Atoi(v); err == nil && code >= 400 && code <= 599 {
+				e.HTTPCode = code
+			}
+		case "retryable":
+			if b, err := strconv.ParseBool(v); err == nil {
+				e.retryable = &b
+			}
+		default:
+			if e.Details == nil {
+				e.Details = make(map[string]any)
+			}
+			e.Details[k] = v
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCStatusErrorInfo(t *testing.T) {
+	st := RateLimitError("slow down").WithDetail("limit", 100).GRPCStatus()
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("code = %v", st.Code())
+	}
+	var info *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		if v, ok := d.(*errdetails.ErrorInfo); ok {
+			info = v
+		}
+	}
+	if info == nil {
+		t.Fatal("missing ErrorInfo detail")
+	}
+	if info.Reason != "RATE_LIMIT" {
+		t.Errorf("Reason = %q, want RATE_LIMIT", info.Reason)
+	}
+	if info.Domain != "chassis.ai8future.com" {
+		t.Errorf("Domain = %q", info.Domain)
+	}
+	if info.Metadata["type"] != typeBaseURI+"rate-limit" || info.Metadata["http_status"] != "429" {
+		t.Errorf("Metadata = %v", info.Metadata)
+	}
+	if info.Metadata["limit"] != "100" {
+		t.Errorf("Metadata[limit] = %q, want detail copied", info.Metadata["limit"])
+	}
+}
+
+func TestGRPCStatusCustomTypeDomain(t *testing.T) {
+	st := ValidationError("bad").WithType("https://billing.example.com/errors/card-declined").GRPCStatus()
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	if !ok {
+		t.Fatalf("first detail = %T", st.Details()[0])
+	}
+	if info.Reason != "CARD_DECLINED" || info.Domain != "billing.example.com" {
+		t.Errorf("ErrorInfo = %v", info)
+	}
+}
+
+func TestGRPCStatusBadRequest(t *testing.T) {
+	st := ValidationError("invalid input").
+		WithDetail("field", "email").
+		WithDetail("reason", "invalid format").
+		GRPCStatus()
+	var br *errdetails.BadRequest
+	for _, d := range st.Details() {
+		if v, ok := d.(*errdetails.BadRequest); ok {
+			br = v
+		}
+	}
+	if br == nil || len(br.FieldViolations) != 1 {
+		t.Fatalf("BadRequest = %v", br)
+	}
+	if fv := br.FieldViolations[0]; fv.Field != "email" || fv.Description != "invalid format" {
+		t.Errorf("violation = %v", fv)
+	}
+}
+
+func TestGRPCStatusRetryInfo(t *testing.T) {
+	st := DependencyError("down").WithRetryAfter(3 * time.Second).GRPCStatus()
+	var ri *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if v, ok := d.(*errdetails.RetryInfo); ok {
+			ri = v
+		}
+	}
+	if ri == nil || ri.RetryDelay.AsDuration() != 3*time.Second {
+		t.Fatalf("RetryInfo = %v", ri)
+	}
+}
+
+func TestGRPCStatusNoOptionalDetails(t *testing.T) {
+	st := InternalError("boom").GRPCStatus()
+	if n := len(st.Details()); n != 1 {
+		t.Errorf("details = %d, want only ErrorInfo", n)
+	}
+}
+
+func TestTypeReason(t *testing.T) {
+	tests := map[string]string{
+		typeBaseURI + "payload-too-large": "PAYLOAD_TOO_LARGE",
+		"urn:acme:quota.exceeded":         "QUOTA_EXCEEDED",
+		"":                                "UNKNOWN",
+	}
+	for in, want := range tests {
+		if got := typeReason(in); got != want {
+			t.Errorf("typeReason(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFromGRPCErrorRoundTrip(t *testing.T) {
+	orig := PreconditionFailedError("stale etag").
+		WithDetail("resource", "order-1").
+		WithRetryAfter(2 * time.Second)
+	// Simulate the wire: only the status survives.
+	wire := status.ErrorProto(orig.GRPCStatus().Proto())
+
+	got := FromGRPCError(wire)
+	if got.HTTPCode != http.StatusPreconditionFailed || got.GRPCCode != codes.FailedPrecondition {
+		t.Errorf("codes = %d / %v", got.HTTPCode, got.GRPCCode)
+	}
+	if got.Message != "stale etag" {
+		t.Errorf("Message = %q", got.Message)
+	}
+	if pd := got.ProblemDetail(nil); pd.Type != typeBaseURI+"precondition-failed" {
+		t.Errorf("Type = %q", pd.Type)
+	}
+	if got.Details["resource"] != "order-1" {
+		t.Errorf("Details = %v", got.Details)
+	}
+	if got.RetryAfter() != 2*time.Second || !got.Retryable() {
+		t.Errorf("retry = %v / %v", got.RetryAfter(), got.Retryable())
+	}
+	if !errors.Is(got, wire) {
+		t.Error("original error should be the cause")
+	}
+}
+
+func TestFromGRPCErrorFieldViolations(t *testing.T) {
+	wire := status.ErrorProto(FromError(Validation().Field("email", "required").Err()).GRPCStatus().Proto())
+	got := FromGRPCError(wire)
+	fields, ok := got.Details["errors"].([]FieldError)
+	if !ok || len(fields) != 1 || fields[0] != (FieldError{"email", "required"}) {
+		t.Errorf("errors detail = %v", got.Details["errors"])
+	}
+}
+
+func TestFromGRPCErrorPlainStatus(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want int
+	}{
+		{codes.NotFound, http.StatusNotFound},
+		{codes.Unavailable, http.StatusServiceUnavailable},
+		{codes.Aborted, http.StatusConflict},
+		{codes.Canceled, 499},
+	}
+	for _, tt := range tests {
+		got := FromGRPCError(status.Error(tt.code, "x"))
+		if got.HTTPCode != tt.want || got.GRPCCode != tt.code {
+			t.Errorf("%v: HTTPCode = %d, want %d", tt.code, got.HTTPCode, tt.want)
+		}
+	}
+}
+
+func TestFromGRPCErrorEdgeCases(t *testing.T) {
+	if FromGRPCError(nil) != nil {
+		t.Error("nil should map to nil")
+	}
+	if FromGRPCError(status.Error(codes.OK, "")) != nil {
+		t.Error("OK status should map to nil")
+	}
+	se := NotFoundError("gone")
+	if FromGRPCError(fmt.Errorf("wrapped: %w", se)) != se {
+		t.Error("ServiceError in chain should be returned as-is")
+	}
+	if got := FromGRPCError(errors.New("dial failed")); got.HTTPCode != http.StatusInternalServerError {
+		t.Errorf("plain error HTTPCode = %d", got.HTTPCode)
+	}
+}
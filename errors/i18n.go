@@ -0,0 +1,131 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Translation is the localized text of one error type in one language.
+type Translation struct {
+	// Title replaces the problem title.
+	Title string
+	// Detail, if set, replaces the problem detail. "{key}" placeholders are
+	// filled from the error's Details; unknown placeholders are left as is.
+	Detail string
+}
+
+var (
+	translationsMu sync.RWMutex
+	translations   = map[string]map[string]Translation{} // lang -> type URI -> text
+)
+
+// RegisterTranslation registers the text of an error type for lang, a
+// BCP 47 tag such as "de" or "pt-BR". errType is a slug under the chassis
+// type base URI (e.g. "not-found" or a name passed to Register) or an
+// absolute type URI. Type URIs are never translated, so clients can keep
+// matching on them. Call it at startup, before serving requests.
+func RegisterTranslation(lang, errType string, t Translation) {
+	typeURI := errType
+	if !strings.Contains(errType, ":") {
+		typeURI = typeBaseURI + errType
+	}
+	lang = strings.ToLower(lang)
+
+	translationsMu.Lock()
+	defer translationsMu.Unlock()
+	if translations[lang] == nil {
+		translations[lang] = make(map[string]Translation)
+	}
+	translations[lang][typeURI] = t
+}
+
+// WriteLocalizedProblem is WriteProblem with the title, and detail where a
+// template is registered, translated into the best language in the request's
+// Accept-Language header that has a translation for the error's type. The
+// chosen language is reported in the Content-Language header. Without a
+// match the response is identical to WriteProblem's.
+func WriteLocalizedProblem(w http.ResponseWriter, r *http.Request, err error, requestID string) {
+	writeProblem(w, r, err, requestID, true)
+}
+
+// localize applies the best translation for acceptLanguage to pd, returning
+// the chosen language.
+func (e *ServiceError) localize(pd *ProblemDetail, acceptLanguage string) (string, bool) {
+	translationsMu.RLock()
+	defer translationsMu.RUnlock()
+	for _, lang := range parseAcceptLanguage(acceptLanguage) {
+		t, ok := translations[lang][pd.Type]
+		if !ok {
+			continue
+		}
+		if t.Title != "" {
+			pd.Title = t.Title
+		}
+		if t.Detail != "" {
+			pd.Detail = e.renderDetail(t.Detail)
+		}
+		return lang, true
+	}
+	return "", false
+}
+
+// renderDetail fills "{key}" placeholders in tmpl from e.Details.
+func (e *ServiceError) renderDetail(tmpl string) string {
+	if len(e.Details) == 0 {
+		return tmpl
+	}
+	pairs := make([]string, 0, 2*len(e.Details))
+	for k, v := range e.Details {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
+
+// parseAcceptLanguage returns the lower-cased language tags of an
+// Accept-Language header in preference order. Each regional tag is followed
+// by its base language, e.g. "de-CH" then "de". Tags with q=0 and "*" are
+// skipped.
+func parseAcceptLanguage(header string) []string {
+	type pref struct {
+		tag string
+		q   float64
+	}
+	var prefs []pref
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			prefs = append(prefs, pref{tag, q})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	for _, p := range prefs {
+		add(p.tag)
+		if base, _, ok := strings.Cut(p.tag, "-"); ok {
+			add(base)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func init() {
+	RegisterTranslation("de", "not-found", Translation{Title: "Nicht gefunden", Detail: "{resource} {id} wurde nicht gefunden"})
+	RegisterTranslation("fr", "not-found", Translation{Title: "Introuvable"})
+	RegisterTranslation("de", "test-quota-exceeded", Translation{Title: "Kontingent überschritten"})
+}
+
+func writeLocalized(t *testing.T, err error, acceptLanguage string) (*httptest.ResponseRecorder, ProblemDetail) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/orders/7", nil)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	rec := httptest.NewRecorder()
+	WriteLocalizedProblem(rec, req, err, "")
+	var pd ProblemDetail
+	if decErr := json.Unmarshal(rec.Body.Bytes(), &pd); decErr != nil {
+		t.Fatal(decErr)
+	}
+	return rec, pd
+}
+
+func TestWriteLocalizedProblemTranslates(t *testing.T) {
+	err := NotFoundError("order 7 not found").WithDetail("resource", "Bestellung").WithDetail("id", 7)
+	rec, pd := writeLocalized(t, err, "de-CH, en;q=0.8")
+
+	if pd.Title != "Nicht gefunden" {
+		t.Errorf("Title = %q", pd.Title)
+	}
+	if pd.Detail != "Bestellung 7 wurde nicht gefunden" {
+		t.Errorf("Detail = %q", pd.Detail)
+	}
+	if pd.Type != typeBaseURI+"not-found" {
+		t.Errorf("Type = %q, type URIs must not be translated", pd.Type)
+	}
+	if got := rec.Header().Get("Content-Language"); got != "de" {
+		t.Errorf("Content-Language = %q", got)
+	}
+}
+
+func TestWriteLocalizedProblemTitleOnlyKeepsDetail(t *testing.T) {
+	_, pd := writeLocalized(t, NotFoundError("order 7 not found"), "fr;q=0.9, de;q=0.5")
+	if pd.Title != "Introuvable" || pd.Detail != "order 7 not found" {
+		t.Errorf("pd = %+v", pd)
+	}
+}
+
+func TestWriteLocalizedProblemRegisteredType(t *testing.T) {
+	_, pd := writeLocalized(t, quotaExceeded("over"), "de")
+	if pd.Title != "Kontingent überschritten" {
+		t.Errorf("Title = %q", pd.Title)
+	}
+}
+
+func TestWriteLocalizedProblemNoMatch(t *testing.T) {
+	rec, pd := writeLocalized(t, NotFoundError("gone"), "ja, de;q=0")
+	if pd.Title != "Not Found" || rec.Header().Get("Content-Language") != "" {
+		t.Errorf("pd = %+v, Content-Language = %q", pd, rec.Header().Get("Content-Language"))
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := parseAcceptLanguage("en;q=0.5, pt-BR, *, fr;q=0, de-AT;q=0.9")
+	want := []string{"pt-br", "pt", "de-at", "de", "en"}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseAcceptLanguage = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/ai8future/chassis-go/v11/internal/otelutil"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/ai8future/chassis-go/v11/errors"
+
+var getCreatedCounter = otelutil.LazyCounter(
+	meterName,
+	"errors.created",
+	metric.WithUnit("{error}"),
+	metric.WithDescription("Number of service errors sent, by problem type and HTTP status"),
+)
+
+// Count records err in the errors.created counter if it is or wraps a
+// ServiceError. Errors are counted when they are sent, not when they are
+// built, so package-level sentinels count on every response. WriteProblem
+// counts every error it writes, and the grpckit metrics interceptors call
+// Count for every RPC error; call it on other paths that send errors.
+func Count(err error) {
+	var se *ServiceError
+	if stderrors.As(err, &se) {
+		record(se)
+	}
+}
+
+// record adds e to the errors.created counter under its type at this point.
+func record(e *ServiceError) {
+	getCreatedCounter().Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("type", typeSlug(e.resolvedType())),
+		attribute.Int("code", e.HTTPCode),
+	))
+}
@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collectCreated returns errors.created counts keyed by "type/code".
+func collectCreated(t *testing.T, reader sdkmetric.Reader) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	counts := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "errors.created" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				typ, _ := dp.Attributes.Value(attribute.Key("type"))
+				code, _ := dp.Attributes.Value(attribute.Key("code"))
+				counts[typ.AsString()+"/"+code.Emit()] += dp.Value
+			}
+		}
+	}
+	return counts
+}
+
+var (
+	metricsOnce   sync.Once
+	metricsReader *sdkmetric.ManualReader
+)
+
+// installMeterProvider installs a global MeterProvider once per test binary.
+// The lazily created counter binds to the first provider, so it is never
+// replaced; tests compare counts before and after instead.
+func installMeterProvider() *sdkmetric.ManualReader {
+	metricsOnce.Do(func() {
+		metricsReader = sdkmetric.NewManualReader()
+		otelapi.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(metricsReader)))
+	})
+	return metricsReader
+}
+
+// errSentinel is built at package initialisation, before any test installs
+// a MeterProvider, like a service's package-level sentinel errors.
+var errSentinel = NotFoundError("no such order")
+
+func TestCreatedCounter(t *testing.T) {
+	reader := installMeterProvider()
+	before := collectCreated(t, reader)
+
+	NotFoundError("a") // built but never sent: not counted
+	notFound := NotFoundError("b").WithDetail("id", 1)
+	quotaExceeded("over")
+
+	// Errors are counted each time they are written, hand-built ones too.
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	WriteProblem(httptest.NewRecorder(), req, notFound, "")
+	WriteProblem(httptest.NewRecorder(), req, quotaExceeded("over"), "")
+	WriteProblem(httptest.NewRecorder(), req, &ServiceError{Message: "teapot", HTTPCode: http.StatusTeapot}, "")
+
+	after := collectCreated(t, reader)
+	want := map[string]int64{
+		"not-found/404":           1,
+		"test-quota-exceeded/429": 1,
+		"unknown/418":             1,
+	}
+	for k, v := range want {
+		if got := after[k] - before[k]; got != v {
+			t.Errorf("count[%s] = %d, want %d (all: %v)", k, got, v, after)
+		}
+	}
+}
+
+func TestCreatedCounterSentinelsAndCopies(t *testing.T) {
+	reader := installMeterProvider()
+	before := collectCreated(t, reader)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	WriteProblem(httptest.NewRecorder(), req, errSentinel, "")
+	WriteProblem(httptest.NewRecorder(), req, errSentinel, "")
+	WriteProblem(httptest.NewRecorder(), req, errSentinel.WithCause(context.Canceled), "")
+	WriteProblem(httptest.NewRecorder(), req, errSentinel.WithType("https://example.com/problems/order-missing"), "")
+	Count(fmt.Errorf("wrapped: %w", errSentinel))
+	Count(context.Canceled) // not a ServiceError
+
+	after := collectCreated(t, reader)
+	want := map[string]int64{
+		"not-found/404":     4,
+		"order-missing/404": 1,
+	}
+	for k, v := range want {
+		if got := after[k] - before[k]; got != v {
+			t.Errorf("count[%s] = %d, want %d (all: %v)", k, got, v, after)
+		}
+	}
+}
@@ -9,27 +9,39 @@ import (
 const typeBaseURI = "https://chassis.ai8future.com/errors/"
 
 var typeURIs = map[int]string{
-	http.StatusBadRequest:            typeBaseURI + "validation",
-	http.StatusNotFound:              typeBaseURI + "not-found",
-	http.StatusUnauthorized:          typeBaseURI + "unauthorized",
-	http.StatusForbidden:             typeBaseURI + "forbidden",
-	http.StatusGatewayTimeout:        typeBaseURI + "timeout",
-	http.StatusRequestEntityTooLarge: typeBaseURI + "payload-too-large",
-	http.StatusTooManyRequests:       typeBaseURI + "rate-limit",
-	http.StatusServiceUnavailable:    typeBaseURI + "dependency",
-	http.StatusInternalServerError:   typeBaseURI + "internal",
+	http.StatusBadRequest:                 typeBaseURI + "validation",
+	http.StatusNotFound:                   typeBaseURI + "not-found",
+	http.StatusUnauthorized:               typeBaseURI + "unauthorized",
+	http.StatusForbidden:                  typeBaseURI + "forbidden",
+	http.StatusGatewayTimeout:             typeBaseURI + "timeout",
+	http.StatusRequestEntityTooLarge:      typeBaseURI + "payload-too-large",
+	http.StatusTooManyRequests:            typeBaseURI + "rate-limit",
+	http.StatusServiceUnavailable:         typeBaseURI + "dependency",
+	http.StatusInternalServerError:        typeBaseURI + "internal",
+	http.StatusConflict:                   typeBaseURI + "conflict",
+	http.StatusPreconditionFailed:         typeBaseURI + "precondition-failed",
+	http.StatusNotImplemented:             typeBaseURI + "not-implemented",
+	http.StatusUnavailableForLegalReasons: typeBaseURI + "unavailable-for-legal-reasons",
+	http.StatusTooEarly:                   typeBaseURI + "too-early",
+	http.StatusUnsupportedMediaType:       typeBaseURI + "unsupported-media-type",
 }
 
 var titleMap = map[int]string{
-	http.StatusBadRequest:            "Validation Error",
-	http.StatusNotFound:              "Not Found",
-	http.StatusUnauthorized:          "Unauthorized",
-	http.StatusForbidden:             "Forbidden",
-	http.StatusGatewayTimeout:        "Timeout",
-	http.StatusRequestEntityTooLarge: "Payload Too Large",
-	http.StatusTooManyRequests:       "Rate Limit Exceeded",
-	http.StatusServiceUnavailable:    "Dependency Error",
-	http.StatusInternalServerError:   "Internal Error",
+	http.StatusBadRequest:                 "Validation Error",
+	http.StatusNotFound:                   "Not Found",
+	http.StatusUnauthorized:               "Unauthorized",
+	http.StatusForbidden:                  "Forbidden",
+	http.StatusGatewayTimeout:             "Timeout",
+	http.StatusRequestEntityTooLarge:      "Payload Too Large",
+	http.StatusTooManyRequests:            "Rate Limit Exceeded",
+	http.StatusServiceUnavailable:         "Dependency Error",
+	http.StatusInternalServerError:        "Internal Error",
+	http.StatusConflict:                   "Conflict",
+	http.StatusPreconditionFailed:         "Precondition Failed",
+	http.StatusNotImplemented:             "Not Implemented",
+	http.StatusUnavailableForLegalReasons: "Unavailable For Legal Reasons",
+	http.StatusTooEarly:                   "Too Early",
+	http.StatusUnsupportedMediaType:       "Unsupported Media Type",
 }
 
 // ProblemDetail represents an RFC 9457 Problem Details object.
@@ -68,14 +80,11 @@ func (pd ProblemDetail) MarshalJSON() ([]byte, error) {
 // ProblemDetail converts this ServiceError into an RFC 9457 ProblemDetail,
 // using the request to populate the Instance field.
 func (e *ServiceError) ProblemDetail(r *http.Request) ProblemDetail {
-	typeURI, ok := typeURIs[e.HTTPCode]
+	typeURI := e.resolvedType()
+	title, ok := registeredTitle(typeURI)
 	if !ok {
-		typeURI = typeBaseURI + "unknown"
+		title, ok = titleMap[e.HTTPCode]
 	}
-	if e.typeURI != "" {
-		typeURI = e.typeURI
-	}
-	title, ok := titleMap[e.HTTPCode]
 	if !ok {
 		title = http.StatusText(e.HTTPCode)
 	}
@@ -91,25 +100,68 @@ func (e *ServiceError) ProblemDetail(r *http.Request) ProblemDetail {
 		Instance: instance,
 	}
 	if len(e.Details) > 0 {
-		pd.Extensions = make(map[string]any, len(e.Details))
+		pd.Extensions = make(map[string]any, len(e.Details)+1)
 		for k, v := range e.Details {
 			pd.Extensions[k] = v
 		}
 	}
+	if e.hasRetryInfo() {
+		if pd.Extensions == nil {
+			pd.Extensions = make(map[string]any, 1)
+		}
+		pd.Extensions["retryable"] = e.Retryable()
+	}
 	return pd
 }
 
+// resolvedType returns the error's RFC 9457 type URI: the WithType override,
+// or the default for its HTTP status.
+func (e *ServiceError) resolvedType() string {
+	if e.typeURI != "" {
+		return e.typeURI
+	}
+	if typeURI, ok := typeURIs[e.HTTPCode]; ok {
+		return typeURI
+	}
+	return typeBaseURI + "unknown"
+}
+
 // WriteProblem writes an RFC 9457 Problem Details JSON response for the given
 // error. It converts the error to a ServiceError via FromError, builds a
 // ProblemDetail, and injects the requestID as an extension member if non-empty.
-// This is the canonical write path used by httpkit and guard.
+// Headers set with WithHeader are emitted, and errors carrying WithRetryAfter
+// also get a Retry-After header. Under SetExposure(Production), internal and
+// dependency errors are sent with a generic message and a correlation ID, and
+// their full message is logged. This is the canonical write path used by
+// httpkit and guard.
 func WriteProblem(w http.ResponseWriter, r *http.Request, err error, requestID string) {
+	writeProblem(w, r, err, requestID, false)
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, err error, requestID string, localize bool) {
 	if err == nil {
 		return
 	}
 	svcErr := FromError(err)
+	record(svcErr)
 	pd := svcErr.ProblemDetail(r)
 
+	if localize {
+		if lang, ok := svcErr.localize(&pd, r.Header.Get("Accept-Language")); ok {
+			w.Header().Set("Content-Language", lang)
+		}
+	}
+
+	if svcErr.redacted() {
+		correlationID := requestID
+		if correlationID == "" {
+			correlationID = newCorrelationID()
+		}
+		slog.ErrorContext(r.Context(), "errors: redacted error response",
+			"correlation_id", correlationID, "status", svcErr.HTTPCode, "error", svcErr.Message)
+		redact(&pd, correlationID)
+	}
+
 	if requestID != "" {
 		if pd.Extensions == nil {
 			pd.Extensions = make(map[string]any)
@@ -117,7 +169,13 @@ func WriteProblem(w http.ResponseWriter, r *http.Request, err error, requestID s
 		pd.Extensions["request_id"] = requestID
 	}
 
+	for k, vs := range svcErr.header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
 	w.Header().Set("Content-Type", "application/problem+json")
+	setRetryAfterHeader(w.Header(), svcErr.retryAfter)
 	w.WriteHeader(svcErr.HTTPCode)
 
 	if encErr := json.NewEncoder(w).Encode(pd); encErr != nil {
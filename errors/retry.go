@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WithRetryable returns a copy of the error marked as safe (true) or unsafe
+// (false) for the client to retry. WriteProblem reports it as the
+// "retryable" extension member.
+func (e *ServiceError) WithRetryable(retryable bool) *ServiceError {
+	out := e.clone()
+	out.retryable = &retryable
+	return out
+}
+
+// WithRetryAfter returns a copy of the error advising clients to wait d
+// before retrying. WriteProblem emits it as a Retry-After header. Unless
+// WithRetryable(false) was applied, the error is also marked retryable.
+func (e *ServiceError) WithRetryAfter(d time.Duration) *ServiceError {
+	out := e.clone()
+	out.retryAfter = d
+	return out
+}
+
+// Retryable reports whether the error was marked retryable, either
+// explicitly with WithRetryable or implicitly with WithRetryAfter.
+func (e *ServiceError) Retryable() bool {
+	if e.retryable != nil {
+		return *e.retryable
+	}
+	return e.retryAfter > 0
+}
+
+// RetryAfter returns the delay set with WithRetryAfter, or zero.
+func (e *ServiceError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// hasRetryInfo reports whether retry metadata was set on the error.
+func (e *ServiceError) hasRetryInfo() bool {
+	return e.retryable != nil || e.retryAfter > 0
+}
+
+// setRetryAfterHeader sets the Retry-After header in whole seconds, rounding
+// up so clients never retry early.
+func setRetryAfterHeader(h http.Header, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	secs := int64((d + time.Second - 1) / time.Second)
+	h.Set("Retry-After", strconv.FormatInt(secs, 10))
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithRetryAfterSetsHeaderAndExtension(t *testing.T) {
+	err := DependencyError("db overloaded").WithRetryAfter(1500 * time.Millisecond)
+	if !err.Retryable() {
+		t.Error("WithRetryAfter should imply Retryable")
+	}
+	if err.RetryAfter() != 1500*time.Millisecond {
+		t.Errorf("RetryAfter = %v", err.RetryAfter())
+	}
+
+	rec := httptest.NewRecorder()
+	WriteProblem(rec, httptest.NewRequest(http.MethodGet, "/", nil), err, "")
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want %q (rounded up)", got, "2")
+	}
+	var body map[string]any
+	if decErr := json.Unmarshal(rec.Body.Bytes(), &body); decErr != nil {
+		t.Fatal(decErr)
+	}
+	if body["retryable"] != true {
+		t.Errorf("retryable = %v, want true", body["retryable"])
+	}
+}
+
+func TestWithRetryableFalse(t *testing.T) {
+	err := RateLimitError("quota exhausted").WithRetryAfter(time.Minute).WithRetryable(false)
+	if err.Retryable() {
+		t.Error("explicit WithRetryable(false) should win")
+	}
+	pd := err.ProblemDetail(nil)
+	if pd.Extensions["retryable"] != false {
+		t.Errorf("retryable extension = %v, want false", pd.Extensions["retryable"])
+	}
+}
+
+func TestNoRetryInfoOmitsExtensionAndHeader(t *testing.T) {
+	err := InternalError("boom")
+	if err.Retryable() {
+		t.Error("errors are not retryable by default")
+	}
+	rec := httptest.NewRecorder()
+	WriteProblem(rec, httptest.NewRequest(http.MethodGet, "/", nil), err, "")
+	if rec.Header().Get("Retry-After") != "" {
+		t.Error("unexpected Retry-After header")
+	}
+	var body map[string]any
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if _, ok := body["retryable"]; ok {
+		t.Error("unexpected retryable extension")
+	}
+}
+
+func TestWithRetryableDoesNotMutateOriginal(t *testing.T) {
+	orig := DependencyError("down")
+	_ = orig.WithRetryable(true).WithRetryAfter(time.Second)
+	if orig.Retryable() || orig.RetryAfter() != 0 {
+		t.Error("original should not be modified")
+	}
+}
@@ -0,0 +1,50 @@
+package errors
+
+import "strings"
+
+// FieldError is one invalid input field. A multi-field validation error
+// carries them as the "errors" extension member of its Problem Details
+// response and as BadRequest field violations in its gRPC status.
+type FieldError struct {
+	Field  string `json:"field"`
+	Detail string `json:"detail"`
+}
+
+// ValidationBuilder collects field errors into a single ValidationError.
+// Create one with Validation.
+type ValidationBuilder struct {
+	fields []FieldError
+}
+
+// Validation returns an empty ValidationBuilder:
+//
+//	err := errors.Validation().
+//		Field("email", "invalid format").
+//		Field("age", "must be positive").
+//		Err()
+func Validation() *ValidationBuilder {
+	return &ValidationBuilder{}
+}
+
+// Field records that field failed validation with detail. A field may be
+// recorded more than once.
+func (b *ValidationBuilder) Field(field, detail string) *ValidationBuilder {
+	b.fields = append(b.fields, FieldError{Field: field, Detail: detail})
+	return b
+}
+
+// Err returns nil if no fields were recorded. Otherwise it returns a
+// *ServiceError (400 / INVALID_ARGUMENT) whose message lists every field and
+// whose "errors" detail holds the []FieldError.
+func (b *ValidationBuilder) Err() error {
+	if len(b.fields) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(b.fields))
+	for i, f := range b.fields {
+		msgs[i] = f.Field + ": " + f.Detail
+	}
+	fields := make([]FieldError, len(b.fields))
+	copy(fields, b.fields)
+	return ValidationError(strings.Join(msgs, "; ")).WithDetail("errors", fields)
+}
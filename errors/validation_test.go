@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func TestValidationNoFieldsIsNil(t *testing.T) {
+	if err := Validation().Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+}
+
+func TestValidationAggregatesFields(t *testing.T) {
+	err := Validation().
+		Field("email", "invalid format").
+		Field("age", "must be positive").
+		Err()
+
+	var se *ServiceError
+	if !stderrors.As(err, &se) {
+		t.Fatalf("Err() = %T, want *ServiceError", err)
+	}
+	if se.HTTPCode != http.StatusBadRequest {
+		t.Errorf("HTTPCode = %d", se.HTTPCode)
+	}
+	if se.Message != "email: invalid format; age: must be positive" {
+		t.Errorf("Message = %q", se.Message)
+	}
+
+	rec := httptest.NewRecorder()
+	WriteProblem(rec, httptest.NewRequest(http.MethodPost, "/users", nil), err, "")
+	var body struct {
+		Errors []FieldError `json:"errors"`
+	}
+	if decErr := json.Unmarshal(rec.Body.Bytes(), &body); decErr != nil {
+		t.Fatal(decErr)
+	}
+	want := []FieldError{{"email", "invalid format"}, {"age", "must be positive"}}
+	if len(body.Errors) != 2 || body.Errors[0] != want[0] || body.Errors[1] != want[1] {
+		t.Errorf("errors = %+v, want %+v", body.Errors, want)
+	}
+}
+
+func TestValidationBadRequestViolations(t *testing.T) {
+	err := Validation().Field("email", "required").Field("email", "too long").Err()
+	st := FromError(err).GRPCStatus()
+	var br *errdetails.BadRequest
+	for _, d := range st.Details() {
+		if v, ok := d.(*errdetails.BadRequest); ok {
+			br = v
+		}
+	}
+	if br == nil || len(br.FieldViolations) != 2 {
+		t.Fatalf("BadRequest = %v", br)
+	}
+	if br.FieldViolations[1].Field != "email" || br.FieldViolations[1].Description != "too long" {
+		t.Errorf("violation = %v", br.FieldViolations[1])
+	}
+}
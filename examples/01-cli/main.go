@@ -7,24 +7,32 @@
 // Override via environment:
 //
 //	APP_NAME=my-app LOG_LEVEL=debug GREETING="Hey there!" go run ./examples/01-cli
+//
+// Or via command-line flags, which take precedence over the environment:
+//
+//	go run ./examples/01-cli --app-name=my-app --log-level=debug
 package main
 
 import (
+	"flag"
+
 	chassis "github.com/ai8future/chassis-go/v11"
 	"github.com/ai8future/chassis-go/v11/config"
 	"github.com/ai8future/chassis-go/v11/logz"
 )
 
 type AppConfig struct {
-	AppName  string `env:"APP_NAME" default:"demo-cli"`
-	LogLevel string `env:"LOG_LEVEL" default:"info"`
-	Greeting string `env:"GREETING" default:"Hello from chassis-go!"`
+	AppName  string `env:"APP_NAME" default:"demo-cli" doc:"application name"`
+	LogLevel string `env:"LOG_LEVEL" default:"info" doc:"log level"`
+	Greeting string `env:"GREETING" default:"Hello from chassis-go!" doc:"greeting to log"`
 }
 
 func main() {
 	chassis.SetAppVersion(chassis.Version)
 	chassis.RequireMajor(11)
-	cfg := config.MustLoad[AppConfig]()
+	flags := config.BindFlags[AppConfig](flag.CommandLine)
+	flag.Parse()
+	cfg := config.MustLoad[AppConfig](config.WithSources(flags, config.Env()))
 	logger := logz.New(cfg.LogLevel)
 
 	logger.Info("application started",
@@ -74,29 +74,19 @@ func main() {
 	addr := fmt.Sprintf(":%d", cfg.Port)
 	logger.Info("starting gRPC server", "addr", addr)
 
-	// Run the gRPC server as a lifecycle component.
-	err := lifecycle.Run(context.Background(), func(ctx context.Context) error {
-		ln, err := net.Listen("tcp", addr)
-		if err != nil {
-			return err
-		}
-		logger.Info("listening", "addr", ln.Addr().String())
-
-		// Serve in background; wait for context cancellation.
-		errCh := make(chan error, 1)
-		go func() {
-			errCh <- srv.Serve(ln)
-		}()
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.Error("listen failed", "error", err)
+		return
+	}
+	logger.Info("listening", "addr", ln.Addr().String())
 
-		select {
-		case <-ctx.Done():
-			logger.Info("shutting down gracefully")
-			srv.GracefulStop()
-			return nil
-		case err := <-errCh:
-			return err
-		}
-	})
+	// Run the gRPC server as a lifecycle component; it stops gracefully on
+	// SIGTERM/SIGINT.
+	err = lifecycle.Run(context.Background(),
+		lifecycle.WithLogger(logger),
+		lifecycle.Named("grpc-server", lifecycle.GRPCServer(srv, ln)),
+	)
 
 	if err != nil {
 		logger.Error("server exited with error", "error", err)
@@ -19,7 +19,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"time"
 
@@ -108,64 +107,26 @@ func main() {
 		rec.RecordRequest(r.Context(), r.Method, "200", float64(time.Since(start).Milliseconds()), float64(len(body)))
 	})
 
-	// Wrap with httpkit middleware: Recovery → Tracing → RequestID → Timeout → Logging → handler
-	handler := httpkit.Recovery(logger)(
-		httpkit.Tracing()(
-			httpkit.RequestID(
-				guard.Timeout(10 * time.Second)(
-					httpkit.Logging(logger)(mux),
-				),
-			),
-		),
-	)
+	// Wrap with httpkit middleware, outermost first.
+	handler := httpkit.Chain(
+		httpkit.Recovery(logger),
+		httpkit.Tracing(),
+		httpkit.RequestID,
+		guard.Timeout(10*time.Second),
+		httpkit.Logging(logger),
+	).Then(mux)
 
 	// --- Lifecycle orchestration ---
+	httpSrv := &http.Server{Addr: fmt.Sprintf(":%d", cfg.HTTPPort), Handler: handler}
+
+	// Admin server (metrics flow via OTLP, so no /metrics)
+	adminMux := httpkit.AdminMux(httpkit.AdminConfig{Health: health.Handler(checks)})
+	adminSrv := &http.Server{Addr: fmt.Sprintf(":%d", cfg.AdminPort), Handler: adminMux}
+
 	err := lifecycle.Run(context.Background(),
-		// HTTP server component
-		func(ctx context.Context) error {
-			addr := fmt.Sprintf(":%d", cfg.HTTPPort)
-			srv := &http.Server{Addr: addr, Handler: handler}
-			ln, err := net.Listen("tcp", addr)
-			if err != nil {
-				return err
-			}
-			logger.Info("HTTP server listening", "addr", ln.Addr().String())
-
-			errCh := make(chan error, 1)
-			go func() { errCh <- srv.Serve(ln) }()
-
-			select {
-			case <-ctx.Done():
-				logger.Info("shutting down HTTP server")
-				return srv.Shutdown(context.Background())
-			case err := <-errCh:
-				return err
-			}
-		},
-		// Admin server (health only — metrics flow via OTLP)
-		func(ctx context.Context) error {
-			adminMux := http.NewServeMux()
-			adminMux.Handle("GET /health", health.Handler(checks))
-
-			addr := fmt.Sprintf(":%d", cfg.AdminPort)
-			srv := &http.Server{Addr: addr, Handler: adminMux}
-			ln, err := net.Listen("tcp", addr)
-			if err != nil {
-				return err
-			}
-			logger.Info("admin server listening", "addr", ln.Addr().String())
-
-			errCh := make(chan error, 1)
-			go func() { errCh <- srv.Serve(ln) }()
-
-			select {
-			case <-ctx.Done():
-				logger.Info("shutting down admin server")
-				return srv.Shutdown(context.Background())
-			case err := <-errCh:
-				return err
-			}
-		},
+		lifecycle.WithLogger(logger),
+		lifecycle.Named("http-server", lifecycle.HTTPServer(httpSrv, nil)),
+		lifecycle.Named("admin-server", lifecycle.HTTPServer(adminSrv, nil)),
 	)
 
 	if err != nil {
@@ -0,0 +1,126 @@
+package grpckit
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"net/http"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+	"github.com/ai8future/chassis-go/v11/errors"
+	"github.com/ai8future/chassis-go/v11/httpkit"
+	"github.com/ai8future/chassis-go/v11/requestid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	otelapi "go.opentelemetry.io/otel"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GatewayConfig configures Gateway.
+type GatewayConfig struct {
+	// Logger is used by the default middleware. Nil uses slog.Default().
+	Logger *slog.Logger
+	// Middleware wraps the gateway mux, outermost first. Nil uses
+	// httpkit.Recovery, Tracing, RequestID and Logging, in that order.
+	Middleware []httpkit.Middleware
+	// MuxOptions are passed to runtime.NewServeMux after the chassis error
+	// handlers and metadata annotator, so a WithErrorHandler or
+	// WithRoutingErrorHandler here replaces GatewayErrorHandler or
+	// GatewayRoutingErrorHandler.
+	MuxOptions []runtime.ServeMuxOption
+}
+
+// Gateway returns a grpc-gateway mux for the generated Register*Handler
+// functions and an http.Handler serving it behind httpkit middleware, so one
+// service can expose the same API over gRPC and REST:
+//
+//	gw, handler := grpckit.Gateway(grpckit.GatewayConfig{Logger: logger})
+//	pb.RegisterOrderServiceHandlerFromEndpoint(ctx, gw, grpcAddr, dialOpts)
+//	mux.Handle("/v1/", handler)
+//
+// Errors from the backend, and routing errors such as unknown paths, are
+// written as RFC 9457 Problem Details by GatewayErrorHandler and
+// GatewayRoutingErrorHandler. Each call to the backend carries the request
+// ID as x-request-id metadata and the trace context of the HTTP request,
+// via GatewayMetadata.
+func Gateway(cfg GatewayConfig) (*runtime.ServeMux, http.Handler) {
+	chassis.AssertVersionChecked()
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	mws := cfg.Middleware
+	if mws == nil {
+		mws = []httpkit.Middleware{
+			httpkit.Recovery(logger),
+			httpkit.Tracing(),
+			httpkit.RequestID,
+			httpkit.Logging(logger),
+		}
+	}
+	opts := append([]runtime.ServeMuxOption{
+		runtime.WithErrorHandler(GatewayErrorHandler),
+		runtime.WithRoutingErrorHandler(GatewayRoutingErrorHandler),
+		runtime.WithMetadata(GatewayMetadata),
+	}, cfg.MuxOptions...)
+	gw := runtime.NewServeMux(opts...)
+	return gw, httpkit.Chain(mws...).Then(gw)
+}
+
+// GatewayErrorHandler is a runtime.ErrorHandlerFunc that converts a gateway
+// error into a ServiceError with errors.FromGRPCError and writes it with
+// httpkit.JSONProblem, keeping the status code mapping and the ErrorInfo,
+// BadRequest and RetryInfo details the backend sent. When the gateway wraps
+// the error in a runtime.HTTPStatusError, its HTTPStatus is the response
+// status.
+func GatewayErrorHandler(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	var hs *runtime.HTTPStatusError
+	if stderrors.As(err, &hs) {
+		err = hs.Err
+	}
+	se := errors.FromGRPCError(err)
+	if se == nil {
+		se = errors.InternalError("gateway error without a status").WithCause(err)
+	}
+	if hs != nil && hs.HTTPStatus != 0 && hs.HTTPStatus != se.HTTPCode {
+		// The gateway chose the HTTP status itself, such as 405 for a path
+		// served under another method or 400 for a malformed escape in the
+		// path; the wrapped error's code would turn those into 501 and 500.
+		se = se.WithCause(err)
+		se.HTTPCode = hs.HTTPStatus
+	}
+	w.Header().Del("Trailer")
+	w.Header().Del("Transfer-Encoding")
+	httpkit.JSONProblem(w, r, se)
+}
+
+// GatewayRoutingErrorHandler is a runtime.RoutingErrorHandlerFunc that writes
+// a routing failure with GatewayErrorHandler under the gateway's own status:
+// 404 for an unknown path, 405 for a path served under another method and 400
+// for a malformed path.
+func GatewayRoutingErrorHandler(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, httpStatus int) {
+	code := codes.Internal
+	switch httpStatus {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusMethodNotAllowed:
+		code = codes.Unimplemented
+	}
+	err := status.Error(code, http.StatusText(httpStatus))
+	GatewayErrorHandler(ctx, mux, m, w, r, &runtime.HTTPStatusError{HTTPStatus: httpStatus, Err: err})
+}
+
+// GatewayMetadata is a runtime.WithMetadata annotator that forwards the
+// request ID set by httpkit.RequestID as x-request-id, and the trace context
+// of ctx with the global propagator, so backend spans join the HTTP trace.
+func GatewayMetadata(ctx context.Context, _ *http.Request) metadata.MD {
+	md := metadata.MD{}
+	if id := requestid.From(ctx); id != "" {
+		md.Set("x-request-id", id)
+	}
+	otelapi.GetTextMapPropagator().Inject(ctx, metadataCarrier{md: md})
+	return md
+}
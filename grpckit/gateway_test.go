@@ -0,0 +1,104 @@
+package grpckit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ai8future/chassis-go/v11/httpkit"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGatewayForwardsMetadataAndWritesProblems(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	otelapi.SetTracerProvider(tp)
+	otelapi.SetTextMapPropagator(propagation.TraceContext{})
+
+	lis := bufconn.Listen(bufSize)
+	defer lis.Close()
+	var incoming metadata.MD
+	var healthErr error
+	server := grpc.NewServer()
+	RegisterHealth(server, func(ctx context.Context) error {
+		incoming, _ = metadata.FromIncomingContext(ctx)
+		return healthErr
+	})
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+	conn := dialBufConn(t, lis)
+	defer conn.Close()
+
+	_, handler := Gateway(GatewayConfig{
+		Logger:     newTestLogger(&bytes.Buffer{}),
+		MuxOptions: []runtime.ServeMuxOption{runtime.WithHealthzEndpoint(healthpb.NewHealthClient(conn))},
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("healthy: status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if got := incoming.Get("x-request-id"); len(got) != 1 || got[0] != rec.Header().Get("X-Request-ID") {
+		t.Fatalf("x-request-id = %v, want %q", got, rec.Header().Get("X-Request-ID"))
+	}
+	if len(incoming.Get("traceparent")) != 1 {
+		t.Fatalf("traceparent not forwarded: %v", incoming)
+	}
+
+	healthErr = errors.New("db down")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assertProblem(t, rec, http.StatusServiceUnavailable)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/nowhere", nil))
+	assertProblem(t, rec, http.StatusNotFound)
+}
+
+func TestGatewayKeepsRoutingStatus(t *testing.T) {
+	gw, handler := Gateway(GatewayConfig{Logger: newTestLogger(&bytes.Buffer{})})
+	if err := gw.HandlePath(http.MethodGet, "/v1/items/{name}", func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+		w.WriteHeader(http.StatusOK)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/items/a", nil))
+	assertProblem(t, rec, http.StatusMethodNotAllowed)
+
+	// A malformed escape in the path reaches the error handler as a 400
+	// wrapping a MalformedSequenceError, which carries no gRPC status.
+	rec = httptest.NewRecorder()
+	httpkit.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := &runtime.HTTPStatusError{HTTPStatus: http.StatusBadRequest, Err: runtime.MalformedSequenceError("%zz")}
+		GatewayErrorHandler(r.Context(), gw, nil, w, r, err)
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/items/a", nil))
+	assertProblem(t, rec, http.StatusBadRequest)
+}
+
+func assertProblem(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if rec.Code != status || rec.Header().Get("Content-Type") != "application/problem+json" {
+		t.Fatalf("status = %d, Content-Type %q, want %d problem+json", rec.Code, rec.Header().Get("Content-Type"), status)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if body["status"] != float64(status) || body["request_id"] != rec.Header().Get("X-Request-ID") {
+		t.Fatalf("problem = %v", body)
+	}
+}
@@ -2,10 +2,13 @@ package grpckit
 
 import (
 	"context"
+	"errors"
 
 	chassis "github.com/ai8future/chassis-go/v11"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 // HealthChecker is a function that performs health checks and returns an error
@@ -15,25 +18,62 @@ type HealthChecker func(ctx context.Context) error
 
 // RegisterHealth registers a grpc.health.v1.Health service on the given gRPC
 // server. The Check RPC calls the provided checker and maps the result to a
-// gRPC health status: SERVING when the checker returns nil, NOT_SERVING when
-// it returns an error.
-func RegisterHealth(server *grpc.Server, checker HealthChecker) {
+// gRPC health status: SERVING when the checker returns nil or a degraded
+// error (one wrapped with health.Degraded), NOT_SERVING for any other error.
+// gRPC health has no degraded state, and a degraded service still serves.
+//
+// checker answers for the server as a whole, the empty service name, and
+// for every service name unless WithServiceHealth options are given to
+// report individual services; then requests for a service name that was
+// not registered fail with codes.NotFound, as the health protocol requires.
+func RegisterHealth(server *grpc.Server, checker HealthChecker, opts ...HealthOption) {
 	chassis.AssertVersionChecked()
-	healthpb.RegisterHealthServer(server, &healthServer{checker: checker})
+	h := &healthServer{checkers: map[string]HealthChecker{"": checker}}
+	for _, opt := range opts {
+		opt(h)
+	}
+	healthpb.RegisterHealthServer(server, h)
+}
+
+// HealthOption configures RegisterHealth.
+type HealthOption func(*healthServer)
+
+// WithServiceHealth reports the health of the gRPC service named service,
+// such as "mypkg.OrderService", from its own checker, typically CheckFunc
+// over the checks that service depends on. A multi-service server can then
+// report one service NOT_SERVING while the others stay SERVING.
+func WithServiceHealth(service string, checker HealthChecker) HealthOption {
+	return func(h *healthServer) {
+		h.checkers[service] = checker
+	}
 }
 
 type healthServer struct {
 	healthpb.UnimplementedHealthServer
-	checker HealthChecker
+	checkers map[string]HealthChecker
 }
 
 func (h *healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
-	err := h.checker(ctx)
+	checker, ok := h.checkers[req.GetService()]
+	if !ok && len(h.checkers) == 1 {
+		checker, ok = h.checkers[""], true
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	err := checker(ctx)
 
 	st := healthpb.HealthCheckResponse_SERVING
-	if err != nil {
+	if err != nil && !isDegraded(err) {
 		st = healthpb.HealthCheckResponse_NOT_SERVING
 	}
 
 	return &healthpb.HealthCheckResponse{Status: st}, nil
 }
+
+// isDegraded reports whether err marks a degraded condition, recognised by a
+// Degraded() bool method as on health.Degraded errors.
+func isDegraded(err error) bool {
+	var d interface{ Degraded() bool }
+	return errors.As(err, &d) && d.Degraded()
+}
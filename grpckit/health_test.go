@@ -3,13 +3,17 @@ package grpckit
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"testing"
 	"time"
 
+	"github.com/ai8future/chassis-go/v11/health"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 )
 
@@ -78,3 +82,55 @@ func TestRegisterHealthNotServing(t *testing.T) {
 		t.Fatalf("status = %v, want NOT_SERVING", resp.Status)
 	}
 }
+
+func TestRegisterHealthDegradedIsServing(t *testing.T) {
+	h := &healthServer{checkers: map[string]HealthChecker{"": func(ctx context.Context) error {
+		return fmt.Errorf("replica: %w", health.Degraded(errors.New("lagging")))
+	}}}
+	resp, err := h.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("status = %v, want SERVING", resp.Status)
+	}
+}
+
+func TestRegisterHealthPerService(t *testing.T) {
+	lis := bufconn.Listen(bufSize)
+	defer lis.Close()
+
+	server := grpc.NewServer()
+	RegisterHealth(server, func(ctx context.Context) error { return nil },
+		WithServiceHealth("test.Orders", func(ctx context.Context) error { return errors.New("db down") }),
+		WithServiceHealth("test.Users", func(ctx context.Context) error { return nil }),
+	)
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	conn := dialBufConn(t, lis)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for service, want := range map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"":            healthpb.HealthCheckResponse_SERVING,
+		"test.Orders": healthpb.HealthCheckResponse_NOT_SERVING,
+		"test.Users":  healthpb.HealthCheckResponse_SERVING,
+	} {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("%q: Check failed: %v", service, err)
+		}
+		if resp.Status != want {
+			t.Errorf("%q: status = %v, want %v", service, resp.Status, want)
+		}
+	}
+
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "test.Unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("unknown service: err = %v, want NotFound", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	chassis "github.com/ai8future/chassis-go/v11"
 	"github.com/ai8future/chassis-go/v11/internal/otelutil"
 	"github.com/ai8future/chassis-go/v11/registry"
+	"github.com/ai8future/chassis-go/v11/requestid"
 	otelapi "go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	otelcodes "go.opentelemetry.io/otel/codes"
@@ -53,6 +54,7 @@ func UnaryLogging(logger *slog.Logger) grpc.UnaryServerInterceptor {
 		if err != nil {
 			attrs = append(attrs, slog.String("error", err.Error()))
 		}
+		attrs = appendRequestID(ctx, attrs)
 
 		logger.LogAttrs(ctx, slog.LevelInfo, "unary RPC", attrs...)
 		return resp, err
@@ -60,7 +62,8 @@ func UnaryLogging(logger *slog.Logger) grpc.UnaryServerInterceptor {
 }
 
 // UnaryRecovery returns a unary server interceptor that catches panics in the
-// handler, logs them at Error level, and returns a codes.Internal gRPC status.
+// handler, logs them at Error level, counts them in the rpc.server.panics
+// counter, and returns a codes.Internal gRPC status.
 func UnaryRecovery(logger *slog.Logger) grpc.UnaryServerInterceptor {
 	chassis.AssertVersionChecked()
 	return func(
@@ -72,11 +75,12 @@ func UnaryRecovery(logger *slog.Logger) grpc.UnaryServerInterceptor {
 		registry.AssertActive()
 		defer func() {
 			if r := recover(); r != nil {
-				logger.LogAttrs(ctx, slog.LevelError, "panic recovered",
+				countPanic(ctx, info.FullMethod)
+				logger.LogAttrs(ctx, slog.LevelError, "panic recovered", appendRequestID(ctx, []slog.Attr{
 					slog.String("method", info.FullMethod),
 					slog.Any("panic", r),
 					slog.String("stack", string(debug.Stack())),
-				)
+				})...)
 				err = status.Errorf(codes.Internal, "internal server error")
 			}
 		}()
@@ -106,6 +110,7 @@ func StreamLogging(logger *slog.Logger) grpc.StreamServerInterceptor {
 		if err != nil {
 			attrs = append(attrs, slog.String("error", err.Error()))
 		}
+		attrs = appendRequestID(ctx(ss), attrs)
 
 		logger.LogAttrs(ctx(ss), slog.LevelInfo, "stream RPC", attrs...)
 		return err
@@ -113,7 +118,8 @@ func StreamLogging(logger *slog.Logger) grpc.StreamServerInterceptor {
 }
 
 // StreamRecovery returns a stream server interceptor that catches panics in the
-// handler, logs them at Error level, and returns a codes.Internal gRPC status.
+// handler, logs them at Error level, counts them in the rpc.server.panics
+// counter, and returns a codes.Internal gRPC status.
 func StreamRecovery(logger *slog.Logger) grpc.StreamServerInterceptor {
 	chassis.AssertVersionChecked()
 	return func(
@@ -125,11 +131,12 @@ func StreamRecovery(logger *slog.Logger) grpc.StreamServerInterceptor {
 		registry.AssertActive()
 		defer func() {
 			if r := recover(); r != nil {
-				logger.LogAttrs(ctx(ss), slog.LevelError, "panic recovered",
+				countPanic(ctx(ss), info.FullMethod)
+				logger.LogAttrs(ctx(ss), slog.LevelError, "panic recovered", appendRequestID(ctx(ss), []slog.Attr{
 					slog.String("method", info.FullMethod),
 					slog.Any("panic", r),
 					slog.String("stack", string(debug.Stack())),
-				)
+				})...)
 				err = status.Errorf(codes.Internal, "internal server error")
 			}
 		}()
@@ -142,6 +149,14 @@ func ctx(ss grpc.ServerStream) context.Context {
 	return ss.Context()
 }
 
+// appendRequestID adds the request ID in ctx, if any, to attrs.
+func appendRequestID(ctx context.Context, attrs []slog.Attr) []slog.Attr {
+	if id := requestid.From(ctx); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	return attrs
+}
+
 // grpcCodeFromError extracts the gRPC status code from an error.
 // Returns codes.OK when err is nil, codes.Unknown for non-gRPC errors.
 func grpcCodeFromError(err error) codes.Code {
@@ -155,9 +170,12 @@ func grpcCodeFromError(err error) codes.Code {
 }
 
 // UnaryMetrics returns a unary server interceptor that records rpc.server.duration
-// as an OTel histogram with method and status code attributes.
-func UnaryMetrics() grpc.UnaryServerInterceptor {
+// as an OTel histogram with method and status code attributes, and counts
+// RPCs that end with a non-OK status in rpc.server.errors with the same
+// attributes. With WithRecorder it also records into a metrics.Recorder.
+func UnaryMetrics(opts ...MetricsOption) grpc.UnaryServerInterceptor {
 	chassis.AssertVersionChecked()
+	rm := newRecorderMetrics(opts)
 	return func(
 		ctx context.Context,
 		req any,
@@ -169,24 +187,25 @@ func UnaryMetrics() grpc.UnaryServerInterceptor {
 		resp, err := handler(ctx, req)
 		duration := time.Since(start).Seconds()
 
-		if h := getRPCDurationHistogram(); h != nil {
-			h.Record(ctx, duration,
-				metric.WithAttributes(
-					attribute.String("rpc.method", info.FullMethod),
-					attribute.String("rpc.system", "grpc"),
-					attribute.Int("rpc.grpc.status_code", int(grpcCodeFromError(err))),
-				),
-			)
-		}
+		recordOutcome(ctx, info.FullMethod, duration, err)
+		rm.record(ctx, info.FullMethod, duration, err)
 
 		return resp, err
 	}
 }
 
 // StreamMetrics returns a stream server interceptor that records rpc.server.duration
-// as an OTel histogram with method and status code attributes.
-func StreamMetrics() grpc.StreamServerInterceptor {
+// as an OTel histogram with method and status code attributes, counts failed
+// streams in rpc.server.errors as UnaryMetrics does, and counts the
+// messages each stream receives and sends as rpc.server.messages_received and
+// rpc.server.messages_sent. It also records the messages per stream as
+// rpc.server.requests_per_rpc and rpc.server.responses_per_rpc, and the size
+// of each protobuf message as rpc.server.request.size and
+// rpc.server.response.size. With WithRecorder it also records into a
+// metrics.Recorder.
+func StreamMetrics(opts ...MetricsOption) grpc.StreamServerInterceptor {
 	chassis.AssertVersionChecked()
+	rm := newRecorderMetrics(opts)
 	return func(
 		srv any,
 		ss grpc.ServerStream,
@@ -195,18 +214,13 @@ func StreamMetrics() grpc.StreamServerInterceptor {
 	) error {
 		registry.AssertActive()
 		start := time.Now()
-		err := handler(srv, ss)
+		cs := &countingStream{ServerStream: ss, method: info.FullMethod, rm: rm}
+		err := handler(srv, cs)
 		duration := time.Since(start).Seconds()
+		cs.finish(ctx(ss))
 
-		if h := getRPCDurationHistogram(); h != nil {
-			h.Record(ctx(ss), duration,
-				metric.WithAttributes(
-					attribute.String("rpc.method", info.FullMethod),
-					attribute.String("rpc.system", "grpc"),
-					attribute.Int("rpc.grpc.status_code", int(grpcCodeFromError(err))),
-				),
-			)
-		}
+		recordOutcome(ctx(ss), info.FullMethod, duration, err)
+		rm.record(ctx(ss), info.FullMethod, duration, err)
 
 		return err
 	}
@@ -298,7 +312,7 @@ func StreamTracing() grpc.StreamServerInterceptor {
 		)
 		defer span.End()
 
-		wrapped := &tracedStream{ServerStream: ss, ctx: sctx}
+		wrapped := &contextStream{ServerStream: ss, ctx: sctx}
 		err := handler(srv, wrapped)
 		if err != nil {
 			st, _ := status.FromError(err)
@@ -311,13 +325,13 @@ func StreamTracing() grpc.StreamServerInterceptor {
 	}
 }
 
-// tracedStream wraps a grpc.ServerStream to override its Context with one
-// that carries the tracing span.
-type tracedStream struct {
+// contextStream wraps a grpc.ServerStream to override its Context, e.g. with
+// one that carries the tracing span or request ID.
+type contextStream struct {
 	grpc.ServerStream
 	ctx context.Context
 }
 
-func (s *tracedStream) Context() context.Context {
+func (s *contextStream) Context() context.Context {
 	return s.ctx
 }
@@ -0,0 +1,250 @@
+package grpckit
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ai8future/chassis-go/v11/errors"
+	"github.com/ai8future/chassis-go/v11/internal/otelutil"
+	"github.com/ai8future/chassis-go/v11/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	getMessagesReceivedCounter = otelutil.LazyCounter(
+		tracerName,
+		"rpc.server.messages_received",
+		metric.WithDescription("Messages received on gRPC server streams"),
+	)
+	getMessagesSentCounter = otelutil.LazyCounter(
+		tracerName,
+		"rpc.server.messages_sent",
+		metric.WithDescription("Messages sent on gRPC server streams"),
+	)
+	getPanicCounter = otelutil.LazyCounter(
+		tracerName,
+		"rpc.server.panics",
+		metric.WithDescription("gRPC handler panics caught by Recovery"),
+	)
+	getErrorCounter = otelutil.LazyCounter(
+		tracerName,
+		"rpc.server.errors",
+		metric.WithDescription("gRPC server requests that ended with a non-OK status"),
+	)
+	getRequestsPerRPCHistogram = otelutil.LazyHistogram(
+		tracerName,
+		"rpc.server.requests_per_rpc",
+		metric.WithUnit("{count}"),
+		metric.WithDescription("Messages received per gRPC server stream"),
+		metric.WithExplicitBucketBoundaries(perRPCBuckets...),
+	)
+	getResponsesPerRPCHistogram = otelutil.LazyHistogram(
+		tracerName,
+		"rpc.server.responses_per_rpc",
+		metric.WithUnit("{count}"),
+		metric.WithDescription("Messages sent per gRPC server stream"),
+		metric.WithExplicitBucketBoundaries(perRPCBuckets...),
+	)
+	getRequestSizeHistogram = otelutil.LazyHistogram(
+		tracerName,
+		"rpc.server.request.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of messages received on gRPC server streams"),
+		metric.WithExplicitBucketBoundaries(metrics.ContentBuckets...),
+	)
+	getResponseSizeHistogram = otelutil.LazyHistogram(
+		tracerName,
+		"rpc.server.response.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of messages sent on gRPC server streams"),
+		metric.WithExplicitBucketBoundaries(metrics.ContentBuckets...),
+	)
+)
+
+// perRPCBuckets are the bucket boundaries for messages per stream.
+var perRPCBuckets = []float64{1, 2, 5, 10, 25, 50, 100, 250, 1000, 10000}
+
+// MetricsOption configures UnaryMetrics and StreamMetrics.
+type MetricsOption func(*metricsOptions)
+
+type metricsOptions struct {
+	recorder *metrics.Recorder
+}
+
+// WithRecorder additionally records every RPC into rec, under rec's prefix
+// and subject to its cardinality limits:
+//
+//   - {prefix}_grpc_server_requests_total{method, code}
+//   - {prefix}_grpc_server_duration_seconds{method}
+//   - {prefix}_grpc_server_messages_received_total{method} and
+//     _grpc_server_messages_sent_total{method}, for streams
+//   - {prefix}_grpc_server_requests_per_rpc{method} and
+//     _grpc_server_responses_per_rpc{method}, messages per stream
+//   - {prefix}_grpc_server_received_bytes_total{method} and
+//     _grpc_server_sent_bytes_total{method}, for streams of protobuf messages
+//
+// method is the full method name, such as "/pkg.Service/Method", and code
+// the status code name, such as "OK" or "NotFound".
+func WithRecorder(rec *metrics.Recorder) MetricsOption {
+	return func(o *metricsOptions) {
+		o.recorder = rec
+	}
+}
+
+// recorderMetrics holds the Recorder instruments; a nil *recorderMetrics
+// records nothing.
+type recorderMetrics struct {
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+	received *metrics.CounterVec
+	sent     *metrics.CounterVec
+	reqsPer  *metrics.HistogramVec
+	respsPer *metrics.HistogramVec
+	recvBy   *metrics.CounterVec
+	sentBy   *metrics.CounterVec
+}
+
+func newRecorderMetrics(opts []MetricsOption) *recorderMetrics {
+	var o metricsOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.recorder == nil {
+		return nil
+	}
+	rec := o.recorder
+	return &recorderMetrics{
+		requests: rec.Counter("grpc_server_requests_total"),
+		duration: rec.Histogram("grpc_server_duration_seconds", metrics.DurationBuckets),
+		received: rec.Counter("grpc_server_messages_received_total"),
+		sent:     rec.Counter("grpc_server_messages_sent_total"),
+		reqsPer:  rec.Histogram("grpc_server_requests_per_rpc", perRPCBuckets),
+		respsPer: rec.Histogram("grpc_server_responses_per_rpc", perRPCBuckets),
+		recvBy:   rec.Counter("grpc_server_received_bytes_total"),
+		sentBy:   rec.Counter("grpc_server_sent_bytes_total"),
+	}
+}
+
+// recordOutcome records rpc.server.duration for an RPC and, when it failed,
+// counts it in rpc.server.errors and, for a ServiceError, errors.created.
+func recordOutcome(ctx context.Context, method string, seconds float64, err error) {
+	attrs := metric.WithAttributes(
+		attribute.String("rpc.method", method),
+		attribute.String("rpc.system", "grpc"),
+		attribute.Int("rpc.grpc.status_code", int(grpcCodeFromError(err))),
+	)
+	if h := getRPCDurationHistogram(); h != nil {
+		h.Record(ctx, seconds, attrs)
+	}
+	if err == nil {
+		return
+	}
+	if c := getErrorCounter(); c != nil {
+		c.Add(ctx, 1, attrs)
+	}
+	errors.Count(err)
+}
+
+// countPanic counts a panic recovered from method in rpc.server.panics.
+func countPanic(ctx context.Context, method string) {
+	if c := getPanicCounter(); c != nil {
+		c.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("rpc.method", method),
+			attribute.String("rpc.system", "grpc"),
+		))
+	}
+}
+
+func (rm *recorderMetrics) record(ctx context.Context, method string, seconds float64, err error) {
+	if rm == nil {
+		return
+	}
+	rm.requests.Add(ctx, 1, "method", method, "code", grpcCodeFromError(err).String())
+	rm.duration.Observe(ctx, seconds, "method", method)
+}
+
+// countingStream counts the messages and bytes a server stream receives and
+// sends. Receiving and sending may happen on different goroutines, so the
+// per-stream totals are atomic.
+type countingStream struct {
+	grpc.ServerStream
+	method         string
+	rm             *recorderMetrics
+	received, sent atomic.Int64
+}
+
+func (s *countingStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received.Add(1)
+		s.count(m, false)
+	}
+	return err
+}
+
+func (s *countingStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(1)
+		s.count(m, true)
+	}
+	return err
+}
+
+// count records one message m, received or sent. Sizes are recorded only for
+// protobuf messages.
+func (s *countingStream) count(m any, sent bool) {
+	ctx := s.Context()
+	counter, size := getMessagesReceivedCounter, getRequestSizeHistogram
+	if sent {
+		counter, size = getMessagesSentCounter, getResponseSizeHistogram
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("rpc.method", s.method),
+		attribute.String("rpc.system", "grpc"),
+	)
+	if c := counter(); c != nil {
+		c.Add(ctx, 1, attrs)
+	}
+	msg, isProto := m.(proto.Message)
+	var n float64
+	if isProto {
+		n = float64(proto.Size(msg))
+		if h := size(); h != nil {
+			h.Record(ctx, n, attrs)
+		}
+	}
+	if s.rm == nil {
+		return
+	}
+	messages, bytes := s.rm.received, s.rm.recvBy
+	if sent {
+		messages, bytes = s.rm.sent, s.rm.sentBy
+	}
+	messages.Add(ctx, 1, "method", s.method)
+	if isProto {
+		bytes.Add(ctx, n, "method", s.method)
+	}
+}
+
+// finish records the stream's message totals once the handler returns.
+func (s *countingStream) finish(ctx context.Context) {
+	attrs := metric.WithAttributes(
+		attribute.String("rpc.method", s.method),
+		attribute.String("rpc.system", "grpc"),
+	)
+	received, sent := float64(s.received.Load()), float64(s.sent.Load())
+	if h := getRequestsPerRPCHistogram(); h != nil {
+		h.Record(ctx, received, attrs)
+	}
+	if h := getResponsesPerRPCHistogram(); h != nil {
+		h.Record(ctx, sent, attrs)
+	}
+	if s.rm != nil {
+		s.rm.reqsPer.Observe(ctx, received, "method", s.method)
+		s.rm.respsPer.Observe(ctx, sent, "method", s.method)
+	}
+}
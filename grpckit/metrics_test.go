@@ -0,0 +1,175 @@
+package grpckit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ai8future/chassis-go/v11/metrics"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// setupTestRecorder installs a manual-reader MeterProvider and returns a
+// Recorder on it together with a function that collects a sum by name and
+// label.
+func setupTestRecorder(t *testing.T) (*metrics.Recorder, func(name, label, value string) float64) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	prev := otelapi.GetMeterProvider()
+	otelapi.SetMeterProvider(mp)
+	t.Cleanup(func() {
+		otelapi.SetMeterProvider(prev)
+		mp.Shutdown(context.Background())
+	})
+
+	return metrics.New("grpcsvc", nil), func(name, label, value string) float64 {
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Fatalf("Collect failed: %v", err)
+		}
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name != name {
+					continue
+				}
+				switch data := m.Data.(type) {
+				case metricdata.Sum[float64]:
+					for _, dp := range data.DataPoints {
+						if v, ok := dp.Attributes.Value(attribute.Key(label)); ok && v.AsString() == value {
+							return dp.Value
+						}
+					}
+				case metricdata.Histogram[float64]:
+					for _, dp := range data.DataPoints {
+						if v, ok := dp.Attributes.Value(attribute.Key(label)); ok && v.AsString() == value {
+							return float64(dp.Count)
+						}
+					}
+				}
+			}
+		}
+		return 0
+	}
+}
+
+// messageStream is a ServerStream that yields n messages and accepts sends.
+type messageStream struct {
+	mockServerStream
+	n int
+}
+
+func (s *messageStream) RecvMsg(any) error {
+	if s.n == 0 {
+		return io.EOF
+	}
+	s.n--
+	return nil
+}
+
+func (s *messageStream) SendMsg(any) error { return nil }
+
+func TestUnaryMetricsWithRecorder(t *testing.T) {
+	rec, value := setupTestRecorder(t)
+	interceptor := UnaryMetrics(WithRecorder(rec))
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}
+
+	interceptor(context.Background(), "req", info, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	interceptor(context.Background(), "req", info, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+
+	if got := value("grpcsvc_grpc_server_requests_total", "code", "OK"); got != 1 {
+		t.Errorf("OK requests = %v, want 1", got)
+	}
+	if got := value("grpcsvc_grpc_server_requests_total", "code", "NotFound"); got != 1 {
+		t.Errorf("NotFound requests = %v, want 1", got)
+	}
+	if got := value("grpcsvc_grpc_server_duration_seconds", "method", "/test.Service/Get"); got != 2 {
+		t.Errorf("duration observations = %v, want 2", got)
+	}
+}
+
+func TestStreamMetricsCountsMessages(t *testing.T) {
+	rec, value := setupTestRecorder(t)
+	interceptor := StreamMetrics(WithRecorder(rec))
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Chat"}
+	ss := &messageStream{mockServerStream: mockServerStream{ctx: context.Background()}, n: 3}
+
+	err := interceptor(nil, ss, info, func(srv any, stream grpc.ServerStream) error {
+		for {
+			if err := stream.RecvMsg(nil); errors.Is(err, io.EOF) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err := stream.SendMsg(nil); err != nil {
+				return err
+			}
+			if err := stream.SendMsg(nil); err != nil {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := value("grpcsvc_grpc_server_messages_received_total", "method", "/test.Service/Chat"); got != 3 {
+		t.Errorf("received = %v, want 3", got)
+	}
+	if got := value("grpcsvc_grpc_server_messages_sent_total", "method", "/test.Service/Chat"); got != 6 {
+		t.Errorf("sent = %v, want 6", got)
+	}
+	if got := value("grpcsvc_grpc_server_requests_total", "code", "OK"); got != 1 {
+		t.Errorf("requests = %v, want 1", got)
+	}
+}
+
+func TestStreamMetricsPerRPCAndBytes(t *testing.T) {
+	rec, value := setupTestRecorder(t)
+	interceptor := StreamMetrics(WithRecorder(rec))
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Watch"}
+	ss := &messageStream{mockServerStream: mockServerStream{ctx: context.Background()}, n: 2}
+	msg := &healthpb.HealthCheckRequest{Service: "orders"}
+
+	err := interceptor(nil, ss, info, func(srv any, stream grpc.ServerStream) error {
+		for {
+			if err := stream.RecvMsg(msg); errors.Is(err, io.EOF) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err := stream.SendMsg("not a proto"); err != nil {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := value("grpcsvc_grpc_server_requests_per_rpc", "method", "/test.Service/Watch"); got != 1 {
+		t.Errorf("requests_per_rpc observations = %v, want 1 per stream", got)
+	}
+	if got := value("grpcsvc_grpc_server_responses_per_rpc", "method", "/test.Service/Watch"); got != 1 {
+		t.Errorf("responses_per_rpc observations = %v, want 1 per stream", got)
+	}
+	if got, want := value("grpcsvc_grpc_server_received_bytes_total", "method", "/test.Service/Watch"), float64(2*proto.Size(msg)); got != want {
+		t.Errorf("received bytes = %v, want %v", got, want)
+	}
+	if got := value("grpcsvc_grpc_server_sent_bytes_total", "method", "/test.Service/Watch"); got != 0 {
+		t.Errorf("sent bytes = %v, want 0 for non-proto messages", got)
+	}
+}
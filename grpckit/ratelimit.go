@@ -0,0 +1,147 @@
+package grpckit
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+	"github.com/ai8future/chassis-go/v11/errors"
+	"github.com/ai8future/chassis-go/v11/guard"
+	"github.com/ai8future/chassis-go/v11/registry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// pushbackKey is the trailer gRPC clients with retry policies read to delay
+// their next attempt (gRFC A6).
+const pushbackKey = "grpc-retry-pushback-ms"
+
+// RateKeyFunc extracts a rate limit key from an RPC's context and full
+// method name.
+type RateKeyFunc func(ctx context.Context, fullMethod string) string
+
+// PeerIP returns a RateKeyFunc keyed by the client's IP address, without the
+// port.
+func PeerIP() RateKeyFunc {
+	return func(ctx context.Context, _ string) string {
+		return peerHost(ctx)
+	}
+}
+
+// MetadataKey returns a RateKeyFunc keyed by the first value of the incoming
+// metadata entry name, such as "x-api-key". Falls back to the peer IP if the
+// entry is absent.
+func MetadataKey(name string) RateKeyFunc {
+	return func(ctx context.Context, _ string) string {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if vals := md.Get(name); len(vals) > 0 && vals[0] != "" {
+				return vals[0]
+			}
+		}
+		return peerHost(ctx)
+	}
+}
+
+// MethodKey returns a RateKeyFunc keyed by the full method name, limiting
+// each RPC method as a whole across all clients.
+func MethodKey() RateKeyFunc {
+	return func(_ context.Context, fullMethod string) string {
+		return fullMethod
+	}
+}
+
+// peerHost returns the host portion of the peer address in ctx, the full
+// address if it has no port, or "" if there is no peer.
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// RateLimitConfig configures UnaryRateLimit and StreamRateLimit, mirroring
+// guard.RateLimitConfig.
+type RateLimitConfig struct {
+	Rate    int
+	Window  time.Duration
+	KeyFunc RateKeyFunc // REQUIRED
+	MaxKeys int         // REQUIRED: upper bound on tracked keys
+
+	// OnReject, if set, is called for every rejected RPC before the error is
+	// returned, for example to count rejections.
+	OnReject func(ctx context.Context, fullMethod string)
+}
+
+// newRateLimiter returns cfg's limiter. guard.NewLimiter validates Rate,
+// Window and MaxKeys.
+func newRateLimiter(cfg RateLimitConfig) *guard.Limiter {
+	if cfg.KeyFunc == nil {
+		panic("grpckit: RateLimitConfig.KeyFunc must not be nil")
+	}
+	return guard.NewLimiter(cfg.Rate, cfg.Window, cfg.MaxKeys)
+}
+
+// rejection returns the pushback trailer telling a rejected client when to
+// retry, and the error for the RPC.
+func rejection(wait time.Duration) (metadata.MD, error) {
+	trailer := metadata.Pairs(pushbackKey, strconv.FormatInt(wait.Milliseconds(), 10))
+	return trailer, errors.RateLimitError("rate limit exceeded").WithRetryAfter(wait).GRPCStatus().Err()
+}
+
+// UnaryRateLimit returns a unary server interceptor enforcing per-key token
+// bucket rate limiting with guard's limiter, the gRPC counterpart of
+// guard.RateLimit. Rejected RPCs fail with codes.ResourceExhausted carrying
+// a RetryInfo detail, and the grpc-retry-pushback-ms trailer is set so
+// clients with a retry policy back off until a token is available. Panics if
+// Rate, Window, KeyFunc, or MaxKeys are invalid.
+func UnaryRateLimit(cfg RateLimitConfig) grpc.UnaryServerInterceptor {
+	chassis.AssertVersionChecked()
+	lim := newRateLimiter(cfg)
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		registry.AssertActive()
+		if ok, wait := lim.Allow(cfg.KeyFunc(ctx, info.FullMethod)); !ok {
+			if cfg.OnReject != nil {
+				cfg.OnReject(ctx, info.FullMethod)
+			}
+			trailer, err := rejection(wait)
+			_ = grpc.SetTrailer(ctx, trailer)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimit returns a stream server interceptor that applies the same
+// limit as UnaryRateLimit when a stream is opened. Messages within an
+// admitted stream are not limited.
+func StreamRateLimit(cfg RateLimitConfig) grpc.StreamServerInterceptor {
+	chassis.AssertVersionChecked()
+	lim := newRateLimiter(cfg)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		registry.AssertActive()
+		ctx := ss.Context()
+		if ok, wait := lim.Allow(cfg.KeyFunc(ctx, info.FullMethod)); !ok {
+			if cfg.OnReject != nil {
+				cfg.OnReject(ctx, info.FullMethod)
+			}
+			trailer, err := rejection(wait)
+			ss.SetTrailer(trailer)
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,129 @@
+package grpckit
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestRateKeyFuncs(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 5555},
+	})
+	if got := PeerIP()(ctx, "/svc/M"); got != "10.1.2.3" {
+		t.Errorf("PeerIP = %q, want 10.1.2.3", got)
+	}
+	if got := MethodKey()(ctx, "/svc/M"); got != "/svc/M" {
+		t.Errorf("MethodKey = %q, want /svc/M", got)
+	}
+	if got := MetadataKey("x-api-key")(ctx, "/svc/M"); got != "10.1.2.3" {
+		t.Errorf("MetadataKey without entry = %q, want the peer IP", got)
+	}
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", "tenant-a"))
+	if got := MetadataKey("x-api-key")(ctx, "/svc/M"); got != "tenant-a" {
+		t.Errorf("MetadataKey = %q, want tenant-a", got)
+	}
+}
+
+func TestUnaryRateLimitRejectsWithPushback(t *testing.T) {
+	lis := bufconn.Listen(bufSize)
+	defer lis.Close()
+
+	var rejected int
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(UnaryRateLimit(RateLimitConfig{
+		Rate:     2,
+		Window:   time.Hour,
+		KeyFunc:  MethodKey(),
+		MaxKeys:  10,
+		OnReject: func(context.Context, string) { rejected++ },
+	})))
+	RegisterHealth(server, func(ctx context.Context) error { return nil })
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	conn := dialBufConn(t, lis)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+			t.Fatalf("call %d: %v", i+1, err)
+		}
+	}
+	var trailer metadata.MD
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Trailer(&trailer))
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("code = %v, want ResourceExhausted", st.Code())
+	}
+	if rejected != 1 {
+		t.Fatalf("OnReject called %d times, want 1", rejected)
+	}
+
+	vals := trailer.Get(pushbackKey)
+	if len(vals) != 1 {
+		t.Fatalf("trailer %s = %v, want one value", pushbackKey, vals)
+	}
+	ms, _ := strconv.Atoi(vals[0])
+	if ms <= 0 {
+		t.Fatalf("pushback = %q ms, want positive", vals[0])
+	}
+	var info *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			info = ri
+		}
+	}
+	if info == nil || info.RetryDelay.AsDuration() <= 0 {
+		t.Fatalf("RetryInfo = %v, want a positive delay", info)
+	}
+}
+
+// trailerStream records the trailer set on a stream.
+type trailerStream struct {
+	mockServerStream
+	trailer metadata.MD
+}
+
+func (s *trailerStream) SetTrailer(md metadata.MD) { s.trailer = metadata.Join(s.trailer, md) }
+
+func TestStreamRateLimitRejectsOnOpen(t *testing.T) {
+	interceptor := StreamRateLimit(RateLimitConfig{Rate: 1, Window: time.Hour, KeyFunc: MethodKey(), MaxKeys: 10})
+	info := &grpc.StreamServerInfo{FullMethod: "/svc/Watch"}
+	ss := &trailerStream{mockServerStream: mockServerStream{ctx: context.Background()}}
+	var calls int
+	handler := func(any, grpc.ServerStream) error { calls++; return nil }
+
+	if err := interceptor(nil, ss, info, handler); err != nil {
+		t.Fatalf("first stream: %v", err)
+	}
+	err := interceptor(nil, ss, info, handler)
+	if status.Code(err) != codes.ResourceExhausted || calls != 1 {
+		t.Fatalf("second stream: err = %v after %d handler calls, want ResourceExhausted after 1", err, calls)
+	}
+	if len(ss.trailer.Get(pushbackKey)) != 1 {
+		t.Fatalf("trailer = %v, want %s", ss.trailer, pushbackKey)
+	}
+}
+
+func TestRateLimitPanicsOnInvalidConfig(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for nil KeyFunc")
+		}
+	}()
+	UnaryRateLimit(RateLimitConfig{Rate: 1, Window: time.Second, MaxKeys: 1})
+}
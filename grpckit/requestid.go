@@ -0,0 +1,80 @@
+package grpckit
+
+import (
+	"context"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+	"github.com/ai8future/chassis-go/v11/registry"
+	"github.com/ai8future/chassis-go/v11/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDKey is the metadata key carrying request IDs, the gRPC form of the
+// X-Request-ID header.
+const requestIDKey = "x-request-id"
+
+// maxRequestIDLen bounds accepted incoming request IDs.
+const maxRequestIDLen = 128
+
+// incomingRequestID returns the caller's request ID from ctx's metadata, or a
+// new one if it is absent or not a plausible ID.
+func incomingRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(requestIDKey); len(vals) > 0 && validRequestID(vals[0]) {
+			return vals[0]
+		}
+	}
+	return requestid.New()
+}
+
+// validRequestID reports whether id is 1 to maxRequestIDLen letters, digits,
+// and '-', '_', '.' or ':', so IDs from callers cannot inject into logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// UnaryRequestID returns a unary server interceptor that takes the request ID
+// from the x-request-id metadata sent by the caller, or generates one, and
+// stores it in the context, where requestid.From (and httpkit.RequestIDFrom)
+// reads it as in HTTP handlers. The ID is echoed in the x-request-id trailer, and the Logging
+// and Recovery interceptors include it in their logs. Place it first in the
+// chain so that Recovery, which runs outside the others, sees it too.
+func UnaryRequestID() grpc.UnaryServerInterceptor {
+	chassis.AssertVersionChecked()
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		registry.AssertActive()
+		id := incomingRequestID(ctx)
+		ctx = requestid.NewContext(ctx, id)
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(requestIDKey, id))
+		return handler(ctx, req)
+	}
+}
+
+// StreamRequestID returns a stream server interceptor that does for streams
+// what UnaryRequestID does for unary RPCs.
+func StreamRequestID() grpc.StreamServerInterceptor {
+	chassis.AssertVersionChecked()
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		registry.AssertActive()
+		id := incomingRequestID(ss.Context())
+		ss.SetTrailer(metadata.Pairs(requestIDKey, id))
+		return handler(srv, &contextStream{ServerStream: ss, ctx: requestid.NewContext(ss.Context(), id)})
+	}
+}
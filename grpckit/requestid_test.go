@@ -0,0 +1,98 @@
+package grpckit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ai8future/chassis-go/v11/httpkit"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestValidRequestID(t *testing.T) {
+	tests := map[string]bool{
+		"3f2b9c1e-8d4a-4c7e-9b1a-0e6f5d4c3b2a": true,
+		"req_42.a:b":                           true,
+		"":                                     false,
+		"has space":                            false,
+		"line\nbreak":                          false,
+		strings.Repeat("a", maxRequestIDLen+1): false,
+	}
+	for id, want := range tests {
+		if got := validRequestID(id); got != want {
+			t.Errorf("validRequestID(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestRequestID_PropagatesAndEchoes(t *testing.T) {
+	lis := bufconn.Listen(bufSize)
+	var buf bytes.Buffer
+	srv, _ := NewServer(ServerConfig{Logger: newTestLogger(&buf)})
+	var seen string
+	RegisterHealth(srv, func(ctx context.Context) error {
+		seen = httpkit.RequestIDFrom(ctx)
+		return nil
+	})
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+	conn := dialBufConn(t, lis)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var trailer metadata.MD
+	callCtx := metadata.AppendToOutgoingContext(ctx, "x-request-id", "abc-123")
+	if _, err := client.Check(callCtx, &healthpb.HealthCheckRequest{}, grpc.Trailer(&trailer)); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if seen != "abc-123" {
+		t.Fatalf("handler saw request ID %q, want abc-123", seen)
+	}
+	if got := trailer.Get("x-request-id"); len(got) != 1 || got[0] != "abc-123" {
+		t.Fatalf("trailer x-request-id = %v, want abc-123", got)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("decode log: %v (%s)", err, buf.String())
+	}
+	if entry["request_id"] != "abc-123" {
+		t.Fatalf("log request_id = %v, want abc-123", entry["request_id"])
+	}
+
+	trailer = nil
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Trailer(&trailer)); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if got := trailer.Get("x-request-id"); len(got) != 1 || got[0] == "" || got[0] != seen {
+		t.Fatalf("generated ID: trailer %v, handler saw %q", got, seen)
+	}
+}
+
+func TestStreamRequestID(t *testing.T) {
+	interceptor := StreamRequestID()
+	ss := &trailerStream{mockServerStream: mockServerStream{
+		ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "bad id")),
+	}}
+	var seen string
+	err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: "/svc/Watch"}, func(_ any, stream grpc.ServerStream) error {
+		seen = httpkit.RequestIDFrom(stream.Context())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen == "" || seen == "bad id" {
+		t.Fatalf("request ID = %q, want a generated ID replacing the invalid one", seen)
+	}
+	if got := ss.trailer.Get("x-request-id"); len(got) != 1 || got[0] != seen {
+		t.Fatalf("trailer = %v, want %q", got, seen)
+	}
+}
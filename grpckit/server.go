@@ -0,0 +1,156 @@
+package grpckit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+	"github.com/ai8future/chassis-go/v11/lifecycle"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+)
+
+// Defaults applied by NewServer to zero ServerConfig fields.
+const (
+	DefaultAddr           = ":50051"
+	DefaultMaxMessageSize = 4 << 20 // 4 MiB, gRPC's default receive limit
+	DefaultDrainTimeout   = lifecycle.DefaultDrainTimeout
+)
+
+// DefaultKeepalive is the keepalive policy NewServer applies when
+// ServerConfig.Keepalive is zero. Idle connections are closed after five
+// minutes, and live ones are pinged every minute so dead peers behind load
+// balancers are noticed.
+var DefaultKeepalive = keepalive.ServerParameters{
+	MaxConnectionIdle: 5 * time.Minute,
+	Time:              time.Minute,
+	Timeout:           20 * time.Second,
+}
+
+// DefaultKeepaliveEnforcement is the client ping policy NewServer applies
+// when ServerConfig.KeepaliveEnforcement is zero. Clients may ping every 10
+// seconds, even without active streams; faster pings get the connection
+// closed with ENHANCE_YOUR_CALM.
+var DefaultKeepaliveEnforcement = keepalive.EnforcementPolicy{
+	MinTime:             10 * time.Second,
+	PermitWithoutStream: true,
+}
+
+// ServerConfig configures NewServer. The zero value is a usable server on
+// DefaultAddr with the default limits and keepalive policy.
+type ServerConfig struct {
+	// Logger receives the Recovery and Logging interceptor output. Nil uses
+	// slog.Default().
+	Logger *slog.Logger
+	// Addr is the address the component listens on when Listener is nil.
+	// Empty means DefaultAddr.
+	Addr string
+	// Listener, if set, is served instead of listening on Addr.
+	Listener net.Listener
+	// MaxRecvMsgSize and MaxSendMsgSize cap message sizes in bytes. Zero
+	// means DefaultMaxMessageSize.
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+	// Keepalive and KeepaliveEnforcement replace DefaultKeepalive and
+	// DefaultKeepaliveEnforcement when non-zero.
+	Keepalive            keepalive.ServerParameters
+	KeepaliveEnforcement keepalive.EnforcementPolicy
+	// Reflection registers the server reflection service for tools such as
+	// grpcurl. Leave it off for public endpoints.
+	Reflection bool
+	// Metrics configures the metrics interceptors, e.g. WithRecorder.
+	Metrics []MetricsOption
+	// UnaryInterceptors and StreamInterceptors run after the standard chain,
+	// closest to the handler, e.g. UnaryValidation.
+	UnaryInterceptors  []grpc.UnaryServerInterceptor
+	StreamInterceptors []grpc.StreamServerInterceptor
+	// ServerOptions are appended to the options NewServer builds, so they
+	// can override any of them.
+	ServerOptions []grpc.ServerOption
+	// DrainTimeout bounds how long the component waits for in-flight RPCs
+	// after its context is cancelled. Zero means DefaultDrainTimeout.
+	DrainTimeout time.Duration
+}
+
+// NewServer returns a gRPC server with the standard interceptor chain —
+// RequestID, Recovery, Tracing, Metrics, Logging, then cfg's own
+// interceptors — the keepalive policy and message size limits applied, and a
+// component that serves it. Register services on the server before running
+// the component:
+//
+//	srv, serve := grpckit.NewServer(grpckit.ServerConfig{Logger: logger})
+//	pb.RegisterOrderServiceServer(srv, orders)
+//	grpckit.RegisterHealth(srv, health.CheckFunc(checks))
+//	lifecycle.Run(ctx, lifecycle.Named("grpc", serve))
+//
+// The component listens on cfg.Listener or cfg.Addr and runs the server with
+// lifecycle.GRPCServer: it calls lifecycle.Started once listening, so it can
+// be wrapped in lifecycle.WaitStarted, serves until its context is
+// cancelled, then stops gracefully, forcibly after the drain timeout.
+func NewServer(cfg ServerConfig) (*grpc.Server, lifecycle.Component) {
+	chassis.AssertVersionChecked()
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = DefaultAddr
+	}
+	if cfg.MaxRecvMsgSize <= 0 {
+		cfg.MaxRecvMsgSize = DefaultMaxMessageSize
+	}
+	if cfg.MaxSendMsgSize <= 0 {
+		cfg.MaxSendMsgSize = DefaultMaxMessageSize
+	}
+	if cfg.Keepalive == (keepalive.ServerParameters{}) {
+		cfg.Keepalive = DefaultKeepalive
+	}
+	if cfg.KeepaliveEnforcement == (keepalive.EnforcementPolicy{}) {
+		cfg.KeepaliveEnforcement = DefaultKeepaliveEnforcement
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = DefaultDrainTimeout
+	}
+
+	unary := append([]grpc.UnaryServerInterceptor{
+		UnaryRequestID(),
+		UnaryRecovery(logger),
+		UnaryTracing(),
+		UnaryMetrics(cfg.Metrics...),
+		UnaryLogging(logger),
+	}, cfg.UnaryInterceptors...)
+	stream := append([]grpc.StreamServerInterceptor{
+		StreamRequestID(),
+		StreamRecovery(logger),
+		StreamTracing(),
+		StreamMetrics(cfg.Metrics...),
+		StreamLogging(logger),
+	}, cfg.StreamInterceptors...)
+
+	opts := append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+		grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(cfg.MaxSendMsgSize),
+		grpc.KeepaliveParams(cfg.Keepalive),
+		grpc.KeepaliveEnforcementPolicy(cfg.KeepaliveEnforcement),
+	}, cfg.ServerOptions...)
+	srv := grpc.NewServer(opts...)
+	if cfg.Reflection {
+		reflection.Register(srv)
+	}
+	return srv, func(ctx context.Context) error {
+		ln := cfg.Listener
+		if ln == nil {
+			var err error
+			if ln, err = net.Listen("tcp", cfg.Addr); err != nil {
+				return fmt.Errorf("grpckit: listen: %w", err)
+			}
+		}
+		return lifecycle.GRPCServer(srv, ln, lifecycle.WithDrainTimeout(cfg.DrainTimeout))(ctx)
+	}
+}
@@ -0,0 +1,103 @@
+package grpckit
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ai8future/chassis-go/v11/lifecycle"
+	"github.com/ai8future/chassis-go/v11/registry"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestNewServer_ServesWithChainAndDrains(t *testing.T) {
+	lis := bufconn.Listen(bufSize)
+	var buf bytes.Buffer
+	srv, serve := NewServer(ServerConfig{Logger: newTestLogger(&buf), Listener: lis, Reflection: true})
+	RegisterHealth(srv, func(ctx context.Context) error { panic("checker exploded") })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serve(ctx) }()
+
+	conn := dialBufConn(t, lis)
+	defer conn.Close()
+	callCtx, callCancel := context.WithTimeout(context.Background(), time.Second)
+	defer callCancel()
+
+	_, err := healthpb.NewHealthClient(conn).Check(callCtx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("Check error = %v, want Internal from Recovery", err)
+	}
+	if log := buf.String(); !strings.Contains(log, "panic recovered") {
+		t.Fatalf("expected recovery output, got: %s", log)
+	}
+
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(callCtx)
+	if err != nil {
+		t.Fatalf("reflection stream: %v", err)
+	}
+	if err := stream.Send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		t.Fatalf("reflection send: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("reflection recv: %v", err)
+	}
+	if len(resp.GetListServicesResponse().GetService()) == 0 {
+		t.Fatal("reflection listed no services")
+	}
+	_ = stream.CloseSend()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("serve returned %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not return after cancel")
+	}
+}
+
+func TestNewServer_SignalsStarted(t *testing.T) {
+	// Run initialises and shuts down the registry itself; restore the
+	// package's test registry afterwards.
+	registry.ResetForTest(t.TempDir())
+	t.Cleanup(initRegistryForTest)
+
+	_, serve := NewServer(ServerConfig{Listener: bufconn.Listen(bufSize)})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	next := func(ctx context.Context) error {
+		cancel() // phase 1 only runs once the server has started
+		return nil
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- lifecycle.Run(ctx, lifecycle.WaitStarted(lifecycle.Named("grpc", serve)), lifecycle.Phase(1, next))
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the next phase never started; NewServer's component should call lifecycle.Started")
+	}
+}
+
+func TestNewServer_ListenError(t *testing.T) {
+	_, serve := NewServer(ServerConfig{Addr: "256.0.0.1:0"})
+	if err := serve(context.Background()); err == nil || !strings.Contains(err.Error(), "grpckit: listen") {
+		t.Fatalf("serve error = %v, want a listen error", err)
+	}
+}
@@ -0,0 +1,193 @@
+package grpckit
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync/atomic"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+	"google.golang.org/grpc/credentials"
+)
+
+// TLSConfig names the PEM files ServerTLS and ClientTLS load.
+type TLSConfig struct {
+	// CertFile and KeyFile hold this side's certificate chain and private
+	// key. Required for ServerTLS; for ClientTLS they enable mutual TLS.
+	CertFile string
+	KeyFile  string
+	// CAFile holds the CA certificates that verify the peer. For ServerTLS
+	// it turns on mutual TLS: clients must present a certificate issued by
+	// one of them. For ClientTLS, empty uses the system roots.
+	CAFile string
+	// SPIFFEIDs, if set, lists the only peer SPIFFE IDs accepted, such as
+	// "spiffe://example.org/ns/prod/sa/billing", matched against the URI SAN
+	// of the peer's certificate. Requires CAFile. Clients then skip the
+	// host name check, since SPIFFE certificates name workloads, not hosts.
+	SPIFFEIDs []string
+	// MinVersion is the minimum TLS version. Zero means TLS 1.2.
+	MinVersion uint16
+}
+
+// TLSCredentials are gRPC transport credentials whose certificates can be
+// re-read from disk while the server or connection is running. Pass them to
+// grpc.Creds or grpc.WithTransportCredentials, and call Reload on SIGHUP:
+//
+//	creds, err := grpckit.ServerTLS(grpckit.TLSConfig{CertFile: crt, KeyFile: key, CAFile: ca})
+//	srv, serve := grpckit.NewServer(grpckit.ServerConfig{ServerOptions: []grpc.ServerOption{grpc.Creds(creds)}})
+//	lifecycle.Run(ctx, serve, lifecycle.OnReload(creds.Reload))
+//
+// New handshakes use the reloaded files; established connections keep the
+// certificates they were made with.
+type TLSCredentials struct {
+	credentials.TransportCredentials
+	cfg      TLSConfig
+	material atomic.Pointer[tlsMaterial]
+}
+
+// tlsMaterial is one loaded set of certificate files.
+type tlsMaterial struct {
+	cert *tls.Certificate // nil for a client without a certificate
+	pool *x509.CertPool   // nil when no CAFile is set
+}
+
+// ServerTLS returns server credentials for cfg. Panics if CertFile or
+// KeyFile is empty, or SPIFFEIDs is set without CAFile; returns an error if
+// the files cannot be loaded.
+func ServerTLS(cfg TLSConfig) (*TLSCredentials, error) {
+	chassis.AssertVersionChecked()
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		panic("grpckit: ServerTLS requires TLSConfig.CertFile and KeyFile")
+	}
+	c, err := newTLSCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tc := &tls.Config{
+		MinVersion: cfg.MinVersion,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return c.material.Load().cert, nil
+		},
+	}
+	if cfg.CAFile != "" {
+		// Verified in VerifyConnection against the current pool, so a
+		// reloaded CAFile takes effect without rebuilding the config.
+		tc.ClientAuth = tls.RequireAnyClientCert
+		tc.VerifyConnection = func(cs tls.ConnectionState) error {
+			return c.verifyPeer(cs, "", x509.ExtKeyUsageClientAuth)
+		}
+	}
+	c.TransportCredentials = credentials.NewTLS(tc)
+	return c, nil
+}
+
+// ClientTLS returns client credentials for cfg. The server's certificate is
+// verified against CAFile, or the system roots, and its host name against
+// the dial target unless SPIFFEIDs is set. Panics if SPIFFEIDs is set without
+// CAFile, or only one of CertFile and KeyFile is set; returns an error if the
+// files cannot be loaded.
+func ClientTLS(cfg TLSConfig) (*TLSCredentials, error) {
+	chassis.AssertVersionChecked()
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		panic("grpckit: ClientTLS requires both TLSConfig.CertFile and KeyFile, or neither")
+	}
+	c, err := newTLSCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tc := &tls.Config{
+		MinVersion: cfg.MinVersion,
+		// Verification happens in VerifyConnection against the current
+		// pool, which the standard check cannot reload.
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			host := cs.ServerName
+			if len(cfg.SPIFFEIDs) > 0 {
+				host = ""
+			}
+			return c.verifyPeer(cs, host, x509.ExtKeyUsageServerAuth)
+		},
+	}
+	if cfg.CertFile != "" {
+		tc.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return c.material.Load().cert, nil
+		}
+	}
+	c.TransportCredentials = credentials.NewTLS(tc)
+	return c, nil
+}
+
+func newTLSCredentials(cfg TLSConfig) (*TLSCredentials, error) {
+	if len(cfg.SPIFFEIDs) > 0 && cfg.CAFile == "" {
+		panic("grpckit: TLSConfig.SPIFFEIDs requires CAFile")
+	}
+	if cfg.MinVersion == 0 {
+		cfg.MinVersion = tls.VersionTLS12
+	}
+	c := &TLSCredentials{cfg: cfg}
+	if err := c.Reload(context.Background()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload re-reads the certificate, key and CA files. If any of them fails to
+// load, the current certificates stay in use and the error is returned. Its
+// signature matches lifecycle.OnReload.
+func (c *TLSCredentials) Reload(context.Context) error {
+	m := &tlsMaterial{}
+	if c.cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.cfg.CertFile, c.cfg.KeyFile)
+		if err != nil {
+			return fmt.Errorf("grpckit: load TLS key pair: %w", err)
+		}
+		m.cert = &cert
+	}
+	if c.cfg.CAFile != "" {
+		pem, err := os.ReadFile(c.cfg.CAFile)
+		if err != nil {
+			return fmt.Errorf("grpckit: read TLS CA file: %w", err)
+		}
+		m.pool = x509.NewCertPool()
+		if !m.pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("grpckit: no certificates in TLS CA file %s", c.cfg.CAFile)
+		}
+	}
+	c.material.Store(m)
+	return nil
+}
+
+// verifyPeer verifies the peer's chain against the current pool, the system
+// roots when there is none, and host when it is not empty, then checks the
+// SPIFFE ID allow-list.
+func (c *TLSCredentials) verifyPeer(cs tls.ConnectionState, host string, usage x509.ExtKeyUsage) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("grpckit: peer presented no certificate")
+	}
+	leaf := cs.PeerCertificates[0]
+	opts := x509.VerifyOptions{
+		Roots:         c.material.Load().pool,
+		Intermediates: x509.NewCertPool(),
+		DNSName:       host,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := leaf.Verify(opts); err != nil {
+		return fmt.Errorf("grpckit: verify peer certificate: %w", err)
+	}
+	if len(c.cfg.SPIFFEIDs) == 0 {
+		return nil
+	}
+	for _, u := range leaf.URIs {
+		if u.Scheme == "spiffe" && slices.Contains(c.cfg.SPIFFEIDs, u.String()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("grpckit: peer SPIFFE ID not allowed (URIs %v)", leaf.URIs)
+}
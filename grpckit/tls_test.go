@@ -0,0 +1,217 @@
+package grpckit
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testCA issues certificates for the TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create CA: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue writes a leaf certificate and key for cn to dir and returns their
+// paths. spiffeID, if set, becomes the URI SAN.
+func (ca *testCA) issue(t *testing.T, dir, cn, spiffeID string, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	if spiffeID != "" {
+		u, _ := url.Parse(spiffeID)
+		tmpl.URIs = []*url.URL{u}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("issue %s: %v", cn, err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	certFile, keyFile := filepath.Join(dir, cn+".crt"), filepath.Join(dir, cn+".key")
+	writeFile(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeFile(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	return certFile, keyFile
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serveTLS starts a health server with creds on a bufconn listener and
+// returns a function that calls Check over a connection with clientCreds,
+// reporting the server certificate's serial number.
+func serveTLS(t *testing.T, creds credentials.TransportCredentials) func(clientCreds credentials.TransportCredentials) (*big.Int, error) {
+	t.Helper()
+	lis := bufconn.Listen(bufSize)
+	server := grpc.NewServer(grpc.Creds(creds))
+	RegisterHealth(server, func(context.Context) error { return nil })
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	return func(clientCreds credentials.TransportCredentials) (*big.Int, error) {
+		conn, err := grpc.NewClient("passthrough:///server.test",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(clientCreds),
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		var p peer.Peer
+		if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Peer(&p)); err != nil {
+			return nil, err
+		}
+		info := p.AuthInfo.(credentials.TLSInfo)
+		return info.State.PeerCertificates[0].SerialNumber, nil
+	}
+}
+
+func TestTLS_MutualWithSPIFFE(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, caFile, ca.pem)
+	srvCert, srvKey := ca.issue(t, dir, "server.test", "spiffe://example.org/server", x509.ExtKeyUsageServerAuth)
+	goodCert, goodKey := ca.issue(t, dir, "billing", "spiffe://example.org/billing", x509.ExtKeyUsageClientAuth)
+	badCert, badKey := ca.issue(t, dir, "intruder", "spiffe://example.org/intruder", x509.ExtKeyUsageClientAuth)
+
+	serverCreds, err := ServerTLS(TLSConfig{
+		CertFile: srvCert, KeyFile: srvKey, CAFile: caFile,
+		SPIFFEIDs: []string{"spiffe://example.org/billing"},
+	})
+	if err != nil {
+		t.Fatalf("ServerTLS: %v", err)
+	}
+	call := serveTLS(t, serverCreds)
+
+	client := func(cert, key string) credentials.TransportCredentials {
+		c, err := ClientTLS(TLSConfig{CertFile: cert, KeyFile: key, CAFile: caFile, SPIFFEIDs: []string{"spiffe://example.org/server"}})
+		if err != nil {
+			t.Fatalf("ClientTLS: %v", err)
+		}
+		return c
+	}
+	if _, err := call(client(goodCert, goodKey)); err != nil {
+		t.Fatalf("allowed client: %v", err)
+	}
+	if _, err := call(client(badCert, badKey)); err == nil {
+		t.Fatal("client with a disallowed SPIFFE ID was accepted")
+	}
+	noCert, _ := ClientTLS(TLSConfig{CAFile: caFile})
+	if _, err := call(noCert); err == nil {
+		t.Fatal("client without a certificate was accepted")
+	}
+}
+
+func TestTLS_ReloadServesNewCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, caFile, ca.pem)
+	certFile, keyFile := ca.issue(t, dir, "server.test", "", x509.ExtKeyUsageServerAuth)
+
+	serverCreds, err := ServerTLS(TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("ServerTLS: %v", err)
+	}
+	call := serveTLS(t, serverCreds)
+	clientCreds, err := ClientTLS(TLSConfig{CAFile: caFile})
+	if err != nil {
+		t.Fatalf("ClientTLS: %v", err)
+	}
+
+	first, err := call(clientCreds)
+	if err != nil {
+		t.Fatalf("before reload: %v", err)
+	}
+	ca.issue(t, dir, "server.test", "", x509.ExtKeyUsageServerAuth) // overwrites the files
+	if err := serverCreds.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	second, err := call(clientCreds)
+	if err != nil {
+		t.Fatalf("after reload: %v", err)
+	}
+	if first.Cmp(second) == 0 {
+		t.Fatal("server still presents the old certificate after Reload")
+	}
+
+	writeFile(t, keyFile, []byte("garbage"))
+	if err := serverCreds.Reload(context.Background()); err == nil {
+		t.Fatal("Reload with a bad key succeeded")
+	}
+	if third, err := call(clientCreds); err != nil || third.Cmp(second) != 0 {
+		t.Fatalf("after failed reload: serial %v, err %v; want the previous certificate", third, err)
+	}
+}
+
+func TestClientTLS_VerifiesHostName(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, caFile, ca.pem)
+	certFile, keyFile := ca.issue(t, dir, "other.test", "", x509.ExtKeyUsageServerAuth)
+
+	serverCreds, err := ServerTLS(TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("ServerTLS: %v", err)
+	}
+	clientCreds, _ := ClientTLS(TLSConfig{CAFile: caFile})
+	if _, err := serveTLS(t, serverCreds)(clientCreds); err == nil {
+		t.Fatal("certificate for other.test accepted for server.test")
+	}
+}
@@ -0,0 +1,76 @@
+package grpckit
+
+import (
+	"context"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+	"github.com/ai8future/chassis-go/v11/registry"
+	"github.com/ai8future/chassis-go/v11/secval"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryValidation returns a unary server interceptor that checks each
+// request message with v.ValidateMessage before the handler runs, the gRPC
+// counterpart of guard.ValidateRequest. Violations are returned as the
+// secval.ServiceError of the violation: codes.InvalidArgument with a
+// BadRequest detail naming the field and rule.
+// A nil v uses the zero secval.Policy.
+func UnaryValidation(v *secval.Validator) grpc.UnaryServerInterceptor {
+	chassis.AssertVersionChecked()
+	if v == nil {
+		v = secval.New(secval.Policy{})
+	}
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		registry.AssertActive()
+		if err := validateMessage(v, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamValidation returns a stream server interceptor that checks every
+// message received from the client with v.ValidateMessage. A violation is
+// returned from RecvMsg as codes.InvalidArgument. A nil v uses the zero
+// secval.Policy.
+func StreamValidation(v *secval.Validator) grpc.StreamServerInterceptor {
+	chassis.AssertVersionChecked()
+	if v == nil {
+		v = secval.New(secval.Policy{})
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		registry.AssertActive()
+		return handler(srv, &validatedStream{ServerStream: ss, v: v})
+	}
+}
+
+// validatedStream wraps a grpc.ServerStream to validate received messages.
+type validatedStream struct {
+	grpc.ServerStream
+	v *secval.Validator
+}
+
+func (s *validatedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return validateMessage(s.v, m)
+}
+
+// validateMessage validates m if it is a protobuf message.
+func validateMessage(v *secval.Validator, m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil
+	}
+	if err := v.ValidateMessage(msg); err != nil {
+		return secval.ServiceError(err)
+	}
+	return nil
+}
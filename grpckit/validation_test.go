@@ -0,0 +1,78 @@
+package grpckit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ai8future/chassis-go/v11/secval"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestUnaryValidationRejectsDangerousKeys(t *testing.T) {
+	interceptor := UnaryValidation(nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		t.Fatal("handler should not be called for an invalid request")
+		return nil, nil
+	}
+
+	req, _ := structpb.NewStruct(map[string]any{"__proto__": map[string]any{"admin": true}})
+	_, err := interceptor(context.Background(), req, info, handler)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	var br *errdetails.BadRequest
+	for _, d := range status.Convert(err).Details() {
+		if d, ok := d.(*errdetails.BadRequest); ok {
+			br = d
+		}
+	}
+	if br == nil || br.FieldViolations[0].Field != "__proto__" || br.FieldViolations[0].Reason != secval.RuleDangerousKey {
+		t.Errorf("BadRequest detail = %v", br)
+	}
+}
+
+func TestUnaryValidationPassesCleanRequest(t *testing.T) {
+	interceptor := UnaryValidation(secval.New(secval.Policy{MaxDepth: 5}))
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+
+	req, _ := structpb.NewStruct(map[string]any{"name": "x"})
+	resp, err := interceptor(context.Background(), req, info, handler)
+	if err != nil || resp != "ok" {
+		t.Fatalf("resp = %v, err = %v", resp, err)
+	}
+}
+
+// recvStream is a grpc.ServerStream that yields one message on RecvMsg.
+type recvStream struct {
+	grpc.ServerStream
+	msg *structpb.Struct
+}
+
+func (s *recvStream) Context() context.Context { return context.Background() }
+
+func (s *recvStream) RecvMsg(m any) error {
+	m.(*structpb.Struct).Fields = s.msg.Fields
+	return nil
+}
+
+func TestStreamValidationRejectsOnRecv(t *testing.T) {
+	interceptor := StreamValidation(secval.New(secval.Policy{MaxKeys: 1}))
+	info := &grpc.StreamServerInfo{FullMethod: "/svc/Stream"}
+	msg, _ := structpb.NewStruct(map[string]any{"a": 1, "b": 2})
+
+	var recvErr error
+	handler := func(srv any, ss grpc.ServerStream) error {
+		recvErr = ss.RecvMsg(&structpb.Struct{})
+		return recvErr
+	}
+	err := interceptor(nil, &recvStream{msg: msg}, info, handler)
+	if status.Code(recvErr) != codes.InvalidArgument || status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got recv=%v err=%v", recvErr, err)
+	}
+}
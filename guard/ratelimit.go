@@ -16,6 +16,11 @@ type RateLimitConfig struct {
 	Window  time.Duration
 	KeyFunc KeyFunc // REQUIRED
 	MaxKeys int     // REQUIRED: upper bound on tracked keys
+
+	// OnReject, if set, is called for every rejected request before the
+	// 429 response is written, for example to count rejections with
+	// metrics.CountRateLimitRejections.
+	OnReject func(r *http.Request)
 }
 
 type bucket struct {
@@ -49,7 +54,9 @@ func newLimiter(rate int, window time.Duration, maxKeys int) *limiter {
 	}
 }
 
-func (l *limiter) allow(key string) bool {
+// allow takes a token from key's bucket. When the bucket is empty it reports
+// false and how long until the next token is available.
+func (l *limiter) allow(key string) (bool, time.Duration) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	now := time.Now()
@@ -79,9 +86,10 @@ func (l *limiter) allow(key string) bool {
 	b.lastFill = now
 	if b.tokens >= 1 {
 		b.tokens--
-		return true
+		return true, 0
 	}
-	return false
+	wait := time.Duration((1 - b.tokens) / float64(l.rate) * float64(l.window))
+	return false, wait
 }
 
 // evictLRU removes the least recently used entry. Must be called with mu held.
@@ -95,7 +103,38 @@ func (l *limiter) evictLRU() {
 	delete(l.entries, key)
 }
 
+// Limiter is the per-key token bucket behind RateLimit, for transports other
+// than net/http such as grpckit. It is safe for concurrent use.
+type Limiter struct {
+	lim *limiter
+}
+
+// NewLimiter returns a Limiter allowing rate requests per window for each
+// key, tracking at most maxKeys keys and evicting the least recently used.
+// Panics if rate, window or maxKeys is not positive.
+func NewLimiter(rate int, window time.Duration, maxKeys int) *Limiter {
+	chassis.AssertVersionChecked()
+	if rate <= 0 {
+		panic("guard: NewLimiter rate must be > 0")
+	}
+	if window <= 0 {
+		panic("guard: NewLimiter window must be > 0")
+	}
+	if maxKeys <= 0 {
+		panic("guard: NewLimiter maxKeys must be > 0")
+	}
+	return &Limiter{lim: newLimiter(rate, window, maxKeys)}
+}
+
+// Allow takes a token for key. When none is left it returns false and how
+// long until the next token is available.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	return l.lim.allow(key)
+}
+
 // RateLimit returns middleware enforcing per-key rate limiting with token bucket.
+// Rejected requests get a 429 whose Retry-After header is the wait until the
+// key's next token, rounded up to whole seconds.
 // Panics if Rate, Window, KeyFunc, or MaxKeys are invalid.
 func RateLimit(cfg RateLimitConfig) func(http.Handler) http.Handler {
 	chassis.AssertVersionChecked()
@@ -115,9 +154,11 @@ func RateLimit(cfg RateLimitConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			key := cfg.KeyFunc(r)
-			if !lim.allow(key) {
-				w.Header().Set("Retry-After", "1")
-				writeProblem(w, r, errors.RateLimitError("rate limit exceeded"))
+			if ok, wait := lim.allow(key); !ok {
+				if cfg.OnReject != nil {
+					cfg.OnReject(r)
+				}
+				writeProblem(w, r, errors.RateLimitError("rate limit exceeded").WithRetryAfter(wait))
 				return
 			}
 			next.ServeHTTP(w, r)
@@ -65,6 +65,10 @@ func TestRateLimitRejectsOverLimit(t *testing.T) {
 			if rec.Code != http.StatusTooManyRequests {
 				t.Fatalf("request %d: expected 429, got %d", i+1, rec.Code)
 			}
+			// One token per 30 minutes: the next is 1800s away.
+			if ra := rec.Header().Get("Retry-After"); ra != "1800" {
+				t.Errorf("Retry-After = %q, want 1800", ra)
+			}
 			ct := rec.Header().Get("Content-Type")
 			if ct != "application/problem+json" {
 				t.Fatalf("Content-Type = %q, want application/problem+json", ct)
@@ -257,3 +261,43 @@ func TestRateLimit_PanicsOnZeroMaxKeys(t *testing.T) {
 		MaxKeys: 0,
 	})
 }
+
+func TestRateLimitOnReject(t *testing.T) {
+	var rejected int
+	mw := guard.RateLimit(guard.RateLimitConfig{
+		Rate:     1,
+		Window:   time.Minute,
+		KeyFunc:  guard.RemoteAddr(),
+		MaxKeys:  100,
+		OnReject: func(*http.Request) { rejected++ },
+	})
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if rejected != 2 {
+		t.Fatalf("OnReject called %d times, want 2", rejected)
+	}
+}
+
+func TestLimiterAllowReportsWait(t *testing.T) {
+	lim := guard.NewLimiter(2, time.Minute, 10)
+	for i := 0; i < 2; i++ {
+		if ok, _ := lim.Allow("a"); !ok {
+			t.Fatalf("call %d rejected, want allowed", i+1)
+		}
+	}
+	ok, wait := lim.Allow("a")
+	if ok {
+		t.Fatal("third call allowed, want rejected")
+	}
+	if wait <= 0 || wait > 30*time.Second {
+		t.Fatalf("wait = %v, want about 30s for one token at 2/min", wait)
+	}
+	if ok, _ := lim.Allow("b"); !ok {
+		t.Fatal("other key rejected, want its own bucket")
+	}
+}
@@ -0,0 +1,32 @@
+package guard
+
+import (
+	"net/http"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+	"github.com/ai8future/chassis-go/v11/secval"
+)
+
+// ValidateRequest returns middleware that checks request headers and query
+// parameters with v before the next handler runs, rejecting violations with
+// 400 Bad Request. A nil v uses the zero secval.Policy. The body is not
+// read; validate JSON bodies in the handler.
+//
+// The Problem Details response, built by secval.ServiceError, carries the
+// violation's "in", "rule", "field" and "key" as extension members, so
+// clients can tell which parameter or header was rejected.
+func ValidateRequest(v *secval.Validator) func(http.Handler) http.Handler {
+	chassis.AssertVersionChecked()
+	if v == nil {
+		v = secval.New(secval.Policy{})
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := v.ValidateRequest(r); err != nil {
+				writeProblem(w, r, secval.ServiceError(err))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
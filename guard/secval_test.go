@@ -0,0 +1,73 @@
+package guard_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ai8future/chassis-go/v11/guard"
+	"github.com/ai8future/chassis-go/v11/secval"
+)
+
+func TestValidateRequestRejectsDangerousQuery(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called for a rejected request")
+	})
+	handler := guard.ValidateRequest(nil)(inner)
+
+	req := httptest.NewRequest("GET", "/?__proto__=1", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("Content-Type = %q, want application/problem+json", ct)
+	}
+	var pd map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&pd); err != nil {
+		t.Fatalf("failed to decode problem detail: %v", err)
+	}
+	if pd["type"] != "https://chassis.ai8future.com/errors/validation" {
+		t.Errorf("type = %v", pd["type"])
+	}
+	if pd["in"] != "query" || pd["rule"] != "dangerous_key" || pd["key"] != "__proto__" {
+		t.Errorf("extensions = in:%v rule:%v key:%v", pd["in"], pd["rule"], pd["key"])
+	}
+}
+
+func TestValidateRequestRejectsOversizedHeader(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called for a rejected request")
+	})
+	handler := guard.ValidateRequest(secval.New(secval.Policy{MaxHeaderLen: 16}))(inner)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Trace", "0123456789abcdef0123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestValidateRequestPassesCleanRequest(t *testing.T) {
+	called := false
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := guard.ValidateRequest(nil)(inner)
+
+	req := httptest.NewRequest("GET", "/items?page=2&sort=name", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if !called || rec.Code != http.StatusOK {
+		t.Fatalf("called = %v, status = %d", called, rec.Code)
+	}
+}
@@ -0,0 +1,189 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+	"github.com/ai8future/chassis-go/v11/tick"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ErrNotChecked is reported by a Checker until its first round of checks
+// has completed.
+var ErrNotChecked = errors.New("health: checks have not run yet")
+
+// Checker runs checks in the background and serves the latest results from
+// a cache, so probes answer instantly and a burst of probe requests cannot
+// hammer the dependencies being checked. Create one with NewChecker and run
+// it as a lifecycle component.
+type Checker struct {
+	run      func(ctx context.Context) ([]Result, error)
+	interval time.Duration
+	jitter   time.Duration
+	latest   atomic.Pointer[snapshot]
+
+	refreshMu sync.Mutex // serialises rounds of checks, so results are stored in order
+
+	mu          sync.Mutex // guards checked, listeners and statusGauge
+	checked     bool
+	listeners   []func(Transition)
+	statusGauge metric.Registration
+}
+
+// Transition describes a change of status between two rounds of checks.
+type Transition struct {
+	// Check is the name of the check that changed, or empty for the
+	// overall status of the service.
+	Check string
+	// From is the previous status, or empty on the first round.
+	From string
+	To   string
+	// Err carries the check's error message, or is the aggregate error
+	// returned by All for the overall status. It is nil when the check is
+	// healthy, and for the overall status unless it is unhealthy.
+	Err error
+}
+
+// snapshot is one completed round of checks.
+type snapshot struct {
+	results []Result
+	err     error
+}
+
+// NewChecker returns a Checker that runs checks via All every interval,
+// delayed by a random duration up to jitter so that replicas do not check a
+// shared dependency in lockstep. Each round is bounded by interval. Panics
+// if interval is not positive.
+//
+// Results are exported on the global MeterProvider as
+// health.check.status{check}, 1 when healthy and 0 otherwise, and
+// health.check.duration{check} in seconds; Prometheus names them
+// health_check_status and health_check_duration_seconds. The status gauge
+// is unregistered when Run returns.
+func NewChecker(checks map[string]Check, interval, jitter time.Duration) *Checker {
+	chassis.AssertVersionChecked()
+	if interval <= 0 {
+		panic("health: NewChecker interval must be > 0")
+	}
+	c := &Checker{run: All(checks), interval: interval, jitter: jitter}
+	c.latest.Store(&snapshot{results: []Result{}, err: ErrNotChecked})
+	c.statusGauge = registerStatusGauge(c)
+	return c
+}
+
+// Run checks immediately and then every interval until ctx is cancelled,
+// then stops exporting health.check.status. It has the lifecycle.Component
+// signature:
+//
+//	lifecycle.Run(ctx, checker.Run, server)
+func (c *Checker) Run(ctx context.Context) error {
+	defer c.unregisterStatusGauge()
+	return tick.Every(c.interval, func(ctx context.Context) error {
+		c.Refresh(ctx)
+		return nil
+	}, tick.Immediate(), tick.Jitter(c.jitter))(ctx)
+}
+
+// Refresh runs the checks now and caches the results. Run calls it on every
+// tick; call it directly to re-check on demand. Concurrent calls run their
+// rounds one after another, so the cache always holds the latest round.
+func (c *Checker) Refresh(ctx context.Context) {
+	c.refreshMu.Lock()
+	ctx, cancel := context.WithTimeout(ctx, c.interval)
+	defer cancel()
+	results, err := c.run(ctx)
+	recordDurations(ctx, results)
+
+	c.mu.Lock()
+	prev := c.latest.Load()
+	c.latest.Store(&snapshot{results: results, err: err})
+	var changes []Transition
+	if len(c.listeners) > 0 {
+		changes = transitions(prev, results, err, c.checked)
+	}
+	c.checked = true
+	listeners := c.listeners
+	c.mu.Unlock()
+	c.refreshMu.Unlock()
+
+	for _, t := range changes {
+		for _, fn := range listeners {
+			fn(t)
+		}
+	}
+}
+
+// OnStatusChange registers fn to be called after a round of checks for
+// every check whose status changed, and for the overall status, with
+// Transition.Check empty. The first round reports every status, with an
+// empty From. Use it to log, emit metrics, page, or flip feature flags when
+// a dependency goes down. fn runs synchronously on the refreshing goroutine.
+func (c *Checker) OnStatusChange(fn func(Transition)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners[:len(c.listeners):len(c.listeners)], fn)
+}
+
+// transitions compares a round of checks with the previous snapshot.
+// Per-check transitions come first, in result order, then the overall one.
+func transitions(prev *snapshot, results []Result, err error, checked bool) []Transition {
+	before := make(map[string]string, len(prev.results))
+	for _, r := range prev.results {
+		before[r.Name] = r.Status
+	}
+	var out []Transition
+	for _, r := range results {
+		if from := before[r.Name]; from != r.Status {
+			out = append(out, Transition{Check: r.Name, From: from, To: r.Status, Err: checkErr(r)})
+		}
+	}
+	from := ""
+	if checked {
+		from = overallStatus(prev.results, prev.err)
+	}
+	if to := overallStatus(results, err); from != to {
+		out = append(out, Transition{From: from, To: to, Err: err})
+	}
+	return out
+}
+
+// checkErr recreates a result's error for a Transition.
+func checkErr(r Result) error {
+	if r.Error == "" {
+		return nil
+	}
+	return errors.New(r.Error)
+}
+
+// Results returns the cached results and aggregate error of the latest
+// round, as All would, or ErrNotChecked before the first round completes.
+func (c *Checker) Results() ([]Result, error) {
+	s := c.latest.Load()
+	return slices.Clone(s.results), s.err
+}
+
+// Check returns the cached aggregate error. Its signature matches
+// grpckit.HealthChecker:
+//
+//	grpckit.RegisterHealth(srv, checker.Check)
+func (c *Checker) Check(context.Context) error {
+	_, err := c.Results()
+	return err
+}
+
+// Handler returns an http.Handler that serves the cached results in the
+// same format, and with the same options, as Handler, without running any
+// checks.
+func (c *Checker) Handler(opts ...HandlerOption) http.Handler {
+	o := newHandlerOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results, err := c.Results()
+		writeResults(w, r, results, err, o.detailed(r))
+	})
+}
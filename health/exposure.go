@@ -0,0 +1,83 @@
+package health
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ai8future/chassis-go/v11/guard"
+)
+
+// HandlerOption configures Handler and Checker.Handler.
+type HandlerOption func(*handlerOptions)
+
+type handlerOptions struct {
+	token   string
+	nets    []*net.IPNet
+	keyFunc guard.KeyFunc
+}
+
+// WithDetailToken restricts the per-check results to requests carrying
+// "Authorization: Bearer <token>". Other requests get the bare status and
+// HTTP code, which is all an orchestrator's probe needs.
+func WithDetailToken(token string) HandlerOption {
+	return func(o *handlerOptions) {
+		o.token = token
+	}
+}
+
+// WithDetailNetworks restricts the per-check results to clients whose IP,
+// extracted with keyFunc, is in one of cidrs. A nil keyFunc uses
+// guard.RemoteAddr; behind a proxy use guard.XForwardedFor. Other requests
+// get the bare status and HTTP code. Panics if any CIDR is invalid.
+// Combined with WithDetailToken, either one grants the details.
+func WithDetailNetworks(keyFunc guard.KeyFunc, cidrs ...string) HandlerOption {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic("health: invalid CIDR: " + cidr + ": " + err.Error())
+		}
+		nets = append(nets, n)
+	}
+	if keyFunc == nil {
+		keyFunc = guard.RemoteAddr()
+	}
+	return func(o *handlerOptions) {
+		o.nets = nets
+		o.keyFunc = keyFunc
+	}
+}
+
+func newHandlerOptions(opts []HandlerOption) *handlerOptions {
+	o := &handlerOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// detailed reports whether r may see the per-check results.
+func (o *handlerOptions) detailed(r *http.Request) bool {
+	if o.token == "" && o.keyFunc == nil {
+		return true
+	}
+	if o.token != "" {
+		auth := r.Header.Get("Authorization")
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok &&
+			subtle.ConstantTimeCompare([]byte(token), []byte(o.token)) == 1 {
+			return true
+		}
+	}
+	if o.keyFunc != nil {
+		if ip := net.ParseIP(o.keyFunc(r)); ip != nil {
+			for _, n := range o.nets {
+				if n.Contains(ip) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
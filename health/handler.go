@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"sync/atomic"
 
 	chassis "github.com/ai8future/chassis-go/v11"
 )
@@ -15,36 +16,121 @@ type response struct {
 	Checks []Result `json:"checks"`
 }
 
+// bareResponse is the envelope for requests not allowed the details.
+type bareResponse struct {
+	Status string `json:"status"`
+}
+
 // Handler returns an http.Handler that runs all registered checks via All.
-// It responds with 200 when every check passes and 503 when any check fails.
-// The response body is JSON: {"status":"healthy"/"unhealthy","checks":[...]}.
-func Handler(checks map[string]Check) http.Handler {
+// The response body is JSON: {"status":"healthy","checks":[...]}. Status is
+// "healthy" with 200 when every check passes, "degraded" with 200 when some
+// are degraded (see Degraded and NonCritical) but none unhealthy, and
+// "unhealthy" with 503 otherwise.
+//
+// WithDetailToken and WithDetailNetworks limit the "checks" array, which
+// can reveal internal topology and error messages, to authorised callers;
+// everyone else gets {"status":...} with the same HTTP code.
+func Handler(checks map[string]Check, opts ...HandlerOption) http.Handler {
 	chassis.AssertVersionChecked()
 	run := All(checks)
+	o := newHandlerOptions(opts)
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		results, err := run(r.Context())
+		writeResults(w, r, results, err, o.detailed(r))
+	})
+}
+
+// writeResults writes the JSON health response for results and the
+// aggregate error returned by All, omitting the per-check results unless
+// detailed.
+func writeResults(w http.ResponseWriter, r *http.Request, results []Result, err error, detailed bool) {
+	status := overallStatus(results, err)
+	code := http.StatusOK
+	if status == StatusUnhealthy {
+		code = http.StatusServiceUnavailable
+	}
+
+	var body any = bareResponse{Status: status}
+	if detailed {
+		body = response{Status: status, Checks: results}
+	}
+	var buf bytes.Buffer
+	if encErr := json.NewEncoder(&buf).Encode(body); encErr != nil {
+		slog.ErrorContext(r.Context(), "health: failed to encode response", "error", encErr)
+		http.Error(w, `{"status":"error"}`, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store, no-cache, must-revalidate")
+	w.WriteHeader(code)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		slog.ErrorContext(r.Context(), "health: failed to write response", "error", err)
+	}
+}
+
+// ReadinessGate reports whether the service should receive traffic.
+// *lifecycle.Gate implements it.
+type ReadinessGate interface {
+	Ready() bool
+}
+
+// ReadinessToggle is a ReadinessGate switched with SetReady, for services
+// that manage readiness themselves rather than with a *lifecycle.Gate. Pass
+// it to lifecycle.WithReadiness to have it set not ready when shutdown
+// begins, and add lifecycle.WithDrainDelay so load balancers notice before
+// listeners close.
+type ReadinessToggle struct {
+	ready   atomic.Bool
+	drained atomic.Bool
+}
+
+// NewReadinessToggle returns a ReadinessToggle in the given initial state.
+func NewReadinessToggle(ready bool) *ReadinessToggle {
+	t := &ReadinessToggle{}
+	t.ready.Store(ready)
+	return t
+}
 
-		status := "healthy"
+// SetReady sets whether the service should receive traffic.
+func (t *ReadinessToggle) SetReady(ready bool) {
+	t.ready.Store(ready)
+}
+
+// Ready reports the state last set with SetReady, or false once the toggle
+// has been drained.
+func (t *ReadinessToggle) Ready() bool {
+	return !t.drained.Load() && t.ready.Load()
+}
+
+// Drain permanently marks the toggle not ready, satisfying lifecycle.Drainer.
+// Later SetReady calls are recorded but no longer make it ready.
+func (t *ReadinessToggle) Drain() {
+	t.drained.Store(true)
+}
+
+// readinessResponse is the JSON envelope returned by ReadinessHandler.
+type readinessResponse struct {
+	Status string `json:"status"`
+}
+
+// ReadinessHandler returns an http.Handler for a readiness probe backed by
+// gate. It responds 200 {"status":"ready"} while gate is ready and 503
+// {"status":"not_ready"} otherwise — including once lifecycle shutdown has
+// begun, so load balancers stop routing traffic before servers drain.
+func ReadinessHandler(gate ReadinessGate) http.Handler {
+	chassis.AssertVersionChecked()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := "ready"
 		code := http.StatusOK
-		if err != nil {
-			status = "unhealthy"
+		if !gate.Ready() {
+			status = "not_ready"
 			code = http.StatusServiceUnavailable
 		}
-
-		var buf bytes.Buffer
-		if encErr := json.NewEncoder(&buf).Encode(response{
-			Status: status,
-			Checks: results,
-		}); encErr != nil {
-			slog.ErrorContext(r.Context(), "health: failed to encode response", "error", encErr)
-			http.Error(w, `{"status":"error"}`, http.StatusInternalServerError)
-			return
-		}
 		w.Header().Set("Content-Type", "application/json")
 		w.Header().Set("Cache-Control", "no-store, no-cache, must-revalidate")
 		w.WriteHeader(code)
-		if _, err := w.Write(buf.Bytes()); err != nil {
+		if err := json.NewEncoder(w).Encode(readinessResponse{Status: status}); err != nil {
 			slog.ErrorContext(r.Context(), "health: failed to write response", "error", err)
 		}
 	})
@@ -6,21 +6,123 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"sort"
+	"sync"
+	"time"
 
 	chassis "github.com/ai8future/chassis-go/v11"
 	"github.com/ai8future/chassis-go/v11/work"
 )
 
 // Check is the standard health check signature. A nil return indicates a
-// healthy dependency; any non-nil error is treated as unhealthy.
+// healthy dependency, an error wrapped with Degraded a partial outage, and
+// any other non-nil error is treated as unhealthy.
 type Check func(ctx context.Context) error
 
+// Statuses reported for each Result and for the service as a whole.
+const (
+	StatusHealthy   = "healthy"
+	StatusDegraded  = "degraded"
+	StatusUnhealthy = "unhealthy"
+)
+
 // Result represents the outcome of a named health check.
 type Result struct {
 	Name    string `json:"name"`
-	Healthy bool   `json:"healthy"`
+	Status  string `json:"status"`
+	Healthy bool   `json:"healthy"` // Status == StatusHealthy
 	Error   string `json:"error,omitempty"`
+
+	// Details holds the values the check recorded with SetDetail.
+	Details map[string]any `json:"details,omitempty"`
+
+	// Duration is how long the check took.
+	Duration time.Duration `json:"-"`
+}
+
+// Degraded marks err as a degraded condition rather than a failure: the
+// dependency works, but not fully, such as a cache serving stale data or a
+// replica lagging. Return it from a Check to report the check as degraded
+// without making the service unhealthy. Degraded(nil) returns nil.
+func Degraded(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &degradedError{err: err}
+}
+
+// IsDegraded reports whether err, or any error it wraps, was marked with
+// Degraded.
+func IsDegraded(err error) bool {
+	var d *degradedError
+	return errors.As(err, &d)
+}
+
+type degradedError struct {
+	err error
+}
+
+func (e *degradedError) Error() string { return e.err.Error() }
+func (e *degradedError) Unwrap() error { return e.err }
+
+// Degraded lets packages that do not import health, such as grpckit,
+// recognise the condition with errors.As.
+func (e *degradedError) Degraded() bool { return true }
+
+// overallStatus returns the service status for results and the aggregate
+// error returned by All.
+func overallStatus(results []Result, err error) string {
+	if err != nil {
+		return StatusUnhealthy
+	}
+	for _, r := range results {
+		if r.Status == StatusDegraded {
+			return StatusDegraded
+		}
+	}
+	return StatusHealthy
+}
+
+// SetDetail attaches key and value to the Result of the check running with
+// ctx, to be served in the JSON response — latency, a server version,
+// replica lag — so the health endpoint doubles as a diagnostics page. value
+// must be JSON-encodable. It does nothing if ctx is not a check's context.
+//
+//	func(ctx context.Context) error {
+//	    lag, err := replicaLag(ctx)
+//	    health.SetDetail(ctx, "replica_lag_seconds", lag.Seconds())
+//	    return err
+//	}
+func SetDetail(ctx context.Context, key string, value any) {
+	if sink, ok := ctx.Value(detailsKey{}).(*detailSink); ok {
+		sink.set(key, value)
+	}
+}
+
+type detailsKey struct{}
+
+// detailSink collects the details of one check. A check abandoned by
+// WithTimeout may still write to it, so it is locked.
+type detailSink struct {
+	mu sync.Mutex
+	m  map[string]any
+}
+
+func (s *detailSink) set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.m == nil {
+		s.m = make(map[string]any)
+	}
+	s.m[key] = value
+}
+
+// snapshot returns a copy of the details, or nil if there are none.
+func (s *detailSink) snapshot() map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.m)
 }
 
 // namedCheck pairs a name with its check function for use with work.Map.
@@ -49,9 +151,10 @@ func CheckFunc(checks map[string]Check) func(ctx context.Context) error {
 
 // All returns a function that runs every named check in parallel using
 // work.Map. All checks execute regardless of individual failures. The
-// returned error is errors.Join of every failing check (nil when all pass).
-// Original errors are wrapped with the check name using fmt.Errorf so that
-// errors.Is chains are preserved.
+// returned error is errors.Join of every failing check (nil when all pass),
+// excluding degraded ones, which appear only in the results. Original errors
+// are wrapped with the check name using fmt.Errorf so that errors.Is chains
+// are preserved.
 func All(checks map[string]Check) func(ctx context.Context) ([]Result, error) {
 	chassis.AssertVersionChecked()
 	return func(ctx context.Context) ([]Result, error) {
@@ -66,9 +169,21 @@ func All(checks map[string]Check) func(ctx context.Context) ([]Result, error) {
 		}
 
 		crs, _ := work.Map(ctx, entries, func(ctx context.Context, nc namedCheck) (checkResult, error) {
-			err := nc.check(ctx)
-			r := Result{Name: nc.name, Healthy: err == nil}
+			sink := &detailSink{}
+			start := time.Now()
+			err := nc.check(context.WithValue(ctx, detailsKey{}, sink))
+			r := Result{
+				Name:     nc.name,
+				Status:   StatusHealthy,
+				Healthy:  err == nil,
+				Details:  sink.snapshot(),
+				Duration: time.Since(start),
+			}
 			if err != nil {
+				r.Status = StatusUnhealthy
+				if IsDegraded(err) {
+					r.Status = StatusDegraded
+				}
 				r.Error = err.Error()
 			}
 			// Always return nil error so Map collects all results.
@@ -79,7 +194,7 @@ func All(checks map[string]Check) func(ctx context.Context) ([]Result, error) {
 		var errs []error
 		for i, cr := range crs {
 			results[i] = cr.result
-			if cr.err != nil {
+			if cr.result.Status == StatusUnhealthy {
 				errs = append(errs, fmt.Errorf("%s: %w", cr.result.Name, cr.err))
 			}
 		}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -333,3 +335,370 @@ func TestHandler_Unhealthy(t *testing.T) {
 		t.Error("expected to find unhealthy db check with error 'gone'")
 	}
 }
+
+// ---------------------------------------------------------------------------
+// ReadinessHandler tests
+// ---------------------------------------------------------------------------
+
+type fakeGate struct{ ready atomic.Bool }
+
+func (g *fakeGate) Ready() bool { return g.ready.Load() }
+
+func TestReadinessHandler(t *testing.T) {
+	gate := &fakeGate{}
+	h := ReadinessHandler(gate)
+
+	for _, tc := range []struct {
+		ready  bool
+		code   int
+		status string
+	}{
+		{false, http.StatusServiceUnavailable, "not_ready"},
+		{true, http.StatusOK, "ready"},
+	} {
+		gate.ready.Store(tc.ready)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		if rec.Code != tc.code {
+			t.Errorf("ready=%v: code = %d, want %d", tc.ready, rec.Code, tc.code)
+		}
+		var body readinessResponse
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if body.Status != tc.status {
+			t.Errorf("ready=%v: status = %q, want %q", tc.ready, body.Status, tc.status)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Check option tests
+// ---------------------------------------------------------------------------
+
+func TestNewCheck_TimeoutBoundsSlowCheck(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	checks := map[string]Check{
+		// Ignores its context entirely.
+		"slow": NewCheck(func(ctx context.Context) error { <-block; return nil }, WithTimeout(20*time.Millisecond)),
+		"fast": func(ctx context.Context) error { return nil },
+	}
+
+	start := time.Now()
+	results, err := All(checks)(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("All took %s, want the timeout to bound it", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	for _, r := range results {
+		if r.Name == "slow" && (r.Healthy || !strings.Contains(r.Error, "timed out after 20ms")) {
+			t.Errorf("slow result = %+v", r)
+		}
+	}
+}
+
+func TestNewCheck_NonCriticalDegrades(t *testing.T) {
+	checks := map[string]Check{
+		"db":     func(ctx context.Context) error { return nil },
+		"search": NewCheck(func(ctx context.Context) error { return errors.New("down") }, NonCritical()),
+	}
+
+	results, err := All(checks)(context.Background())
+	if err != nil {
+		t.Fatalf("non-critical failure should not be returned, got %v", err)
+	}
+	if r := results[1]; r.Name != "search" || r.Healthy || r.Status != StatusDegraded {
+		t.Errorf("search result = %+v", r)
+	}
+
+	rec := httptest.NewRecorder()
+	Handler(checks).ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status code = %d, want 200", rec.Code)
+	}
+	var body response
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != "degraded" {
+		t.Errorf("status = %q, want degraded", body.Status)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Checker tests
+// ---------------------------------------------------------------------------
+
+func TestChecker_ServesCachedResults(t *testing.T) {
+	var calls atomic.Int32
+	var fail atomic.Bool
+	checker := NewChecker(map[string]Check{
+		"db": func(ctx context.Context) error {
+			calls.Add(1)
+			if fail.Load() {
+				return errors.New("down")
+			}
+			return nil
+		},
+	}, time.Hour, 0)
+
+	if _, err := checker.Results(); !errors.Is(err, ErrNotChecked) {
+		t.Fatalf("before first check: err = %v, want ErrNotChecked", err)
+	}
+	rec := httptest.NewRecorder()
+	checker.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("before first check: code = %d, want 503", rec.Code)
+	}
+
+	checker.Refresh(context.Background())
+	for i := 0; i < 5; i++ {
+		rec := httptest.NewRecorder()
+		checker.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("code = %d, want 200", rec.Code)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("check ran %d times, want 1: probes must be served from cache", n)
+	}
+
+	fail.Store(true)
+	checker.Refresh(context.Background())
+	if err := checker.Check(context.Background()); err == nil {
+		t.Fatal("expected cached failure after refresh")
+	}
+}
+
+func TestChecker_RunChecksImmediately(t *testing.T) {
+	ran := make(chan struct{}, 1)
+	checker := NewChecker(map[string]Check{
+		"db": func(ctx context.Context) error {
+			select {
+			case ran <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+	}, time.Hour, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- checker.Run(ctx) }()
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not check immediately")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run returned %v", err)
+	}
+	if _, err := checker.Results(); err != nil {
+		t.Fatalf("Results after Run: %v", err)
+	}
+}
+
+func TestChecker_ConcurrentRefreshKeepsLatestRound(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	checker := NewChecker(map[string]Check{
+		"db": func(ctx context.Context) error {
+			if calls.Add(1) == 1 {
+				<-release // the first round is slow and healthy
+				return nil
+			}
+			return errors.New("down")
+		},
+	}, time.Hour, 0)
+
+	var wg sync.WaitGroup
+	wg.Go(func() { checker.Refresh(context.Background()) })
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	wg.Go(func() { checker.Refresh(context.Background()) })
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if err := checker.Check(context.Background()); err == nil {
+		t.Fatal("the slow first round overwrote the later failing one")
+	}
+}
+
+func TestDegradedCheck(t *testing.T) {
+	checks := map[string]Check{
+		"db":      func(ctx context.Context) error { return nil },
+		"replica": func(ctx context.Context) error { return Degraded(errors.New("lag 30s")) },
+	}
+
+	results, err := All(checks)(context.Background())
+	if err != nil {
+		t.Fatalf("degraded check should not fail All, got %v", err)
+	}
+	want := map[string]string{"db": StatusHealthy, "replica": StatusDegraded}
+	for _, r := range results {
+		if r.Status != want[r.Name] {
+			t.Errorf("%s: status = %q, want %q", r.Name, r.Status, want[r.Name])
+		}
+	}
+	if Degraded(nil) != nil {
+		t.Error("Degraded(nil) should be nil")
+	}
+	if !IsDegraded(fmt.Errorf("wrapped: %w", Degraded(errors.New("x")))) {
+		t.Error("IsDegraded should see through wrapping")
+	}
+
+	// A degraded and an unhealthy check together are unhealthy.
+	checks["queue"] = func(ctx context.Context) error { return errors.New("down") }
+	rec := httptest.NewRecorder()
+	Handler(checks).ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("code = %d, want 503", rec.Code)
+	}
+	var body response
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != StatusUnhealthy {
+		t.Errorf("status = %q, want unhealthy", body.Status)
+	}
+}
+
+func TestChecker_OnStatusChange(t *testing.T) {
+	var dbErr atomic.Pointer[error]
+	checker := NewChecker(map[string]Check{
+		"cache": func(ctx context.Context) error { return nil },
+		"db": func(ctx context.Context) error {
+			if p := dbErr.Load(); p != nil {
+				return *p
+			}
+			return nil
+		},
+	}, time.Hour, 0)
+
+	var got []string
+	checker.OnStatusChange(func(tr Transition) {
+		got = append(got, tr.Check+":"+tr.From+"->"+tr.To)
+	})
+	expect := func(want ...string) {
+		t.Helper()
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Fatalf("transitions = %v, want %v", got, want)
+		}
+		got = nil
+	}
+
+	checker.Refresh(context.Background())
+	expect("cache:->healthy", "db:->healthy", ":->healthy")
+
+	checker.Refresh(context.Background())
+	expect()
+
+	down := errors.New("connection refused")
+	dbErr.Store(&down)
+	checker.Refresh(context.Background())
+	expect("db:healthy->unhealthy", ":healthy->unhealthy")
+
+	lag := Degraded(errors.New("lagging"))
+	dbErr.Store(&lag)
+	checker.Refresh(context.Background())
+	expect("db:unhealthy->degraded", ":unhealthy->degraded")
+}
+
+func TestReadinessToggle(t *testing.T) {
+	toggle := NewReadinessToggle(false)
+	handler := ReadinessHandler(toggle)
+
+	for _, ready := range []bool{false, true, false} {
+		toggle.SetReady(ready)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+		want := http.StatusServiceUnavailable
+		if ready {
+			want = http.StatusOK
+		}
+		if rec.Code != want {
+			t.Errorf("ready=%v: code = %d, want %d", ready, rec.Code, want)
+		}
+	}
+}
+
+func TestReadinessToggleDrainIsSticky(t *testing.T) {
+	toggle := NewReadinessToggle(true)
+	toggle.Drain()
+	toggle.SetReady(true) // e.g. a dependency watcher recovering mid-shutdown
+	if toggle.Ready() {
+		t.Error("a drained toggle must stay not ready after SetReady(true)")
+	}
+}
+
+func TestSetDetail(t *testing.T) {
+	checks := map[string]Check{
+		"replica": func(ctx context.Context) error {
+			SetDetail(ctx, "version", "16.2")
+			SetDetail(ctx, "lag_seconds", 1.5)
+			return nil
+		},
+		"plain": func(ctx context.Context) error { return nil },
+	}
+	SetDetail(context.Background(), "ignored", true) // outside a check: no-op
+
+	rec := httptest.NewRecorder()
+	Handler(checks).ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, `"details":{"lag_seconds":1.5,"version":"16.2"}`) {
+		t.Errorf("response missing details: %s", body)
+	}
+	if strings.Count(body, `"details"`) != 1 {
+		t.Errorf("checks without details should omit the field: %s", body)
+	}
+}
+
+func TestHandler_DetailExposure(t *testing.T) {
+	checks := map[string]Check{
+		"db": func(ctx context.Context) error { return errors.New("dial 10.1.2.3:5432: refused") },
+	}
+	handler := Handler(checks,
+		WithDetailToken("s3cret"),
+		WithDetailNetworks(nil, "10.0.0.0/8"),
+	)
+
+	cases := []struct {
+		name       string
+		remoteAddr string
+		auth       string
+		detailed   bool
+	}{
+		{"anonymous", "203.0.113.5:1234", "", false},
+		{"wrong token", "203.0.113.5:1234", "Bearer nope", false},
+		{"token", "203.0.113.5:1234", "Bearer s3cret", true},
+		{"allowed network", "10.9.8.7:1234", "", true},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "/healthz", nil)
+		req.RemoteAddr = tc.remoteAddr
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: code = %d, want 503 regardless of details", tc.name, rec.Code)
+		}
+		body := rec.Body.String()
+		if got := strings.Contains(body, `"checks"`); got != tc.detailed {
+			t.Errorf("%s: detailed = %v, want %v: %s", tc.name, got, tc.detailed, body)
+		}
+		if !strings.Contains(body, `"status":"unhealthy"`) {
+			t.Errorf("%s: missing status: %s", tc.name, body)
+		}
+	}
+}
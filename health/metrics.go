@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+
+	"github.com/ai8future/chassis-go/v11/internal/otelutil"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/ai8future/chassis-go/v11/health"
+
+var getDurationHistogram = otelutil.LazyHistogram(
+	meterName,
+	"health.check.duration",
+	metric.WithUnit("s"),
+	metric.WithDescription("Duration of health checks run by a Checker"),
+)
+
+// recordDurations records the duration of every check in results.
+func recordDurations(ctx context.Context, results []Result) {
+	h := getDurationHistogram()
+	for _, r := range results {
+		h.Record(ctx, r.Duration.Seconds(), metric.WithAttributes(attribute.String("check", r.Name)))
+	}
+}
+
+// registerStatusGauge reports c's latest results as health.check.status,
+// 1 for a healthy check and 0 for a degraded or unhealthy one, until the
+// returned registration is unregistered. Nothing is reported before the
+// first round completes. It returns nil if the gauge cannot be created.
+func registerStatusGauge(c *Checker) metric.Registration {
+	meter := otelapi.GetMeterProvider().Meter(meterName)
+	gauge, err := meter.Int64ObservableGauge("health.check.status",
+		metric.WithDescription("Latest health check status: 1 healthy, 0 degraded or unhealthy"))
+	if err != nil {
+		otelapi.Handle(err)
+		return nil
+	}
+	reg, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, r := range c.latest.Load().results {
+			var v int64
+			if r.Status == StatusHealthy {
+				v = 1
+			}
+			o.ObserveInt64(gauge, v, metric.WithAttributes(attribute.String("check", r.Name)))
+		}
+		return nil
+	}, gauge)
+	if err != nil {
+		otelapi.Handle(err)
+		return nil
+	}
+	return reg
+}
+
+// unregisterStatusGauge stops reporting c's health.check.status. It is safe
+// to call more than once.
+func (c *Checker) unregisterStatusGauge() {
+	c.mu.Lock()
+	reg := c.statusGauge
+	c.statusGauge = nil
+	c.mu.Unlock()
+	if reg == nil {
+		return
+	}
+	if err := reg.Unregister(); err != nil {
+		otelapi.Handle(err)
+	}
+}
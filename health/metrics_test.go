@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestCheckerExportsMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	prev := otelapi.GetMeterProvider()
+	otelapi.SetMeterProvider(mp)
+	defer otelapi.SetMeterProvider(prev)
+
+	checker := NewChecker(map[string]Check{
+		"metrics-up":   func(ctx context.Context) error { return nil },
+		"metrics-down": func(ctx context.Context) error { return errors.New("down") },
+	}, time.Hour, 0)
+	checker.Refresh(context.Background())
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	status := map[string]int64{}
+	durations := map[string]uint64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch m.Name {
+			case "health.check.status":
+				for _, dp := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+					check, _ := dp.Attributes.Value(attribute.Key("check"))
+					status[check.AsString()] = dp.Value
+				}
+			case "health.check.duration":
+				for _, dp := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+					check, _ := dp.Attributes.Value(attribute.Key("check"))
+					durations[check.AsString()] += dp.Count
+				}
+			}
+		}
+	}
+
+	if status["metrics-up"] != 1 || status["metrics-down"] != 0 {
+		t.Errorf("health.check.status = %v, want metrics-up=1 metrics-down=0", status)
+	}
+	if _, ok := status["metrics-down"]; !ok {
+		t.Error("health.check.status missing metrics-down")
+	}
+	if durations["metrics-up"] != 1 || durations["metrics-down"] != 1 {
+		t.Errorf("health.check.duration counts = %v, want one each", durations)
+	}
+}
+
+func TestCheckerUnregistersStatusGaugeWhenRunReturns(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	prev := otelapi.GetMeterProvider()
+	otelapi.SetMeterProvider(mp)
+	defer otelapi.SetMeterProvider(prev)
+
+	checker := NewChecker(map[string]Check{
+		"metrics-stopped": func(ctx context.Context) error { return nil },
+	}, time.Hour, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	checker.Run(ctx)
+	checker.Refresh(context.Background())
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "health.check.status" {
+				t.Errorf("health.check.status still reported after Run returned: %+v", m.Data)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CheckOption configures a Check built with NewCheck.
+type CheckOption func(*checkOptions)
+
+type checkOptions struct {
+	timeout     time.Duration
+	nonCritical bool
+}
+
+// WithTimeout fails the check if it has not returned within d. The check's
+// context is cancelled at d, and the probe stops waiting for it even if it
+// ignores the cancellation, so one slow dependency cannot hang the others.
+func WithTimeout(d time.Duration) CheckOption {
+	return func(o *checkOptions) {
+		o.timeout = d
+	}
+}
+
+// NonCritical marks the check as non-critical: its failures are reported as
+// Degraded, so they show in the results but are left out of the error
+// returned by All and CheckFunc, and Handler still responds 200.
+func NonCritical() CheckOption {
+	return func(o *checkOptions) {
+		o.nonCritical = true
+	}
+}
+
+// NewCheck returns check configured with opts, for use as a value in the
+// map passed to All, Handler or CheckFunc:
+//
+//	checks := map[string]health.Check{
+//	    "postgres": health.NewCheck(pingDB, health.WithTimeout(2*time.Second)),
+//	    "search":   health.NewCheck(pingSearch, health.NonCritical()),
+//	}
+func NewCheck(check Check, opts ...CheckOption) Check {
+	var o checkOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.timeout > 0 {
+		check = withTimeout(check, o.timeout)
+	}
+	if o.nonCritical {
+		inner := check
+		check = func(ctx context.Context) error {
+			return Degraded(inner(ctx))
+		}
+	}
+	return check
+}
+
+// withTimeout runs check in its own goroutine and returns when it does or
+// when d has elapsed, whichever is first.
+func withTimeout(check Check, d time.Duration) Check {
+	return func(parent context.Context) error {
+		ctx, cancel := context.WithTimeout(parent, d)
+		defer cancel()
+
+		done := make(chan error, 1) // buffered so an abandoned check can still return
+		go func() { done <- check(ctx) }()
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			if err := parent.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("timed out after %s: %w", d, ctx.Err())
+		}
+	}
+}
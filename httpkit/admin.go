@@ -0,0 +1,133 @@
+package httpkit
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+)
+
+// AdminConfig configures AdminMux. Endpoints whose field is unset are not
+// mounted, except /buildinfo, which is always served.
+type AdminConfig struct {
+	// Health is served at GET /health, e.g. health.Handler(checks) or
+	// checker.Handler().
+	Health http.Handler
+	// Ready is served at GET /ready, e.g. health.ReadinessHandler(toggle).
+	Ready http.Handler
+	// Metrics is served at GET /metrics, e.g. otel.PrometheusHandler().
+	Metrics http.Handler
+	// Flags returns the current feature flag values, served as JSON at
+	// GET /flags.
+	Flags func() map[string]string
+	// Version is reported by /buildinfo. Empty uses the main module
+	// version from the binary's build info.
+	Version string
+	// Pprof mounts net/http/pprof under /debug/pprof/. It requires Auth.
+	Pprof bool
+	// Auth wraps every endpoint except /health and /ready, which stay open
+	// for orchestrator probes. Use guard.IPFilter, a token check, or a
+	// Chain of both.
+	Auth Middleware
+}
+
+// AdminMux returns a ServeMux with the standard admin endpoints, for
+// serving on a separate admin port:
+//
+//	admin := httpkit.AdminMux(httpkit.AdminConfig{
+//	    Health:  health.Handler(checks),
+//	    Ready:   health.ReadinessHandler(toggle),
+//	    Metrics: otel.PrometheusHandler(),
+//	    Pprof:   true,
+//	    Auth:    guard.IPFilter(guard.IPFilterConfig{Allow: []string{"10.0.0.0/8"}}),
+//	})
+//
+// The returned mux can be extended with further routes. Panics if Pprof is
+// set without Auth, since profiles expose memory contents and can be used
+// to load the process.
+func AdminMux(cfg AdminConfig) *http.ServeMux {
+	chassis.AssertVersionChecked()
+	if cfg.Pprof && cfg.Auth == nil {
+		panic("httpkit: AdminConfig.Pprof requires Auth")
+	}
+	protect := func(h http.Handler) http.Handler {
+		if cfg.Auth == nil {
+			return h
+		}
+		return cfg.Auth(h)
+	}
+
+	mux := http.NewServeMux()
+	if cfg.Health != nil {
+		mux.Handle("GET /health", cfg.Health)
+	}
+	if cfg.Ready != nil {
+		mux.Handle("GET /ready", cfg.Ready)
+	}
+	if cfg.Metrics != nil {
+		mux.Handle("GET /metrics", protect(cfg.Metrics))
+	}
+	if cfg.Flags != nil {
+		mux.Handle("GET /flags", protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			JSON(w, http.StatusOK, cfg.Flags())
+		})))
+	}
+	mux.Handle("GET /buildinfo", protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		JSON(w, http.StatusOK, loadBuildInfo(cfg.Version))
+	})))
+	if cfg.Pprof {
+		mux.Handle("/debug/pprof/", protect(http.HandlerFunc(pprof.Index)))
+		mux.Handle("/debug/pprof/cmdline", protect(http.HandlerFunc(pprof.Cmdline)))
+		mux.Handle("/debug/pprof/profile", protect(http.HandlerFunc(pprof.Profile)))
+		mux.Handle("/debug/pprof/symbol", protect(http.HandlerFunc(pprof.Symbol)))
+		mux.Handle("/debug/pprof/trace", protect(http.HandlerFunc(pprof.Trace)))
+	}
+	return mux
+}
+
+// buildInfo is the /buildinfo response.
+type buildInfo struct {
+	Version        string `json:"version,omitempty"`
+	Module         string `json:"module,omitempty"`
+	GoVersion      string `json:"go_version"`
+	ChassisVersion string `json:"chassis_version"`
+	Revision       string `json:"revision,omitempty"`
+	RevisionTime   string `json:"revision_time,omitempty"`
+	Modified       bool   `json:"modified,omitempty"`
+}
+
+var readBuildInfo = sync.OnceValue(func() buildInfo {
+	bi := buildInfo{GoVersion: runtime.Version(), ChassisVersion: chassis.Version}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return bi
+	}
+	bi.Module = info.Main.Path
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		bi.Version = v
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			bi.Revision = s.Value
+		case "vcs.time":
+			bi.RevisionTime = s.Value
+		case "vcs.modified":
+			bi.Modified = s.Value == "true"
+		}
+	}
+	return bi
+})
+
+// loadBuildInfo returns the binary's build info, with version overriding
+// the module version when set.
+func loadBuildInfo(version string) buildInfo {
+	bi := readBuildInfo()
+	if version != "" {
+		bi.Version = version
+	}
+	return bi
+}
@@ -0,0 +1,97 @@
+package httpkit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+)
+
+func denyAll(http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+}
+
+func adminGet(mux http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestAdminMux_Endpoints(t *testing.T) {
+	ok := func(body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(body)) })
+	}
+	mux := AdminMux(AdminConfig{
+		Health:  ok("health"),
+		Ready:   ok("ready"),
+		Metrics: ok("metrics"),
+		Flags:   func() map[string]string { return map[string]string{"new-ui": "true"} },
+		Version: "1.2.3",
+	})
+
+	for path, want := range map[string]string{"/health": "health", "/ready": "ready", "/metrics": "metrics"} {
+		if rec := adminGet(mux, path); rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("%s: %d %q", path, rec.Code, rec.Body.String())
+		}
+	}
+
+	var flags map[string]string
+	rec := adminGet(mux, "/flags")
+	if err := json.Unmarshal(rec.Body.Bytes(), &flags); err != nil || flags["new-ui"] != "true" {
+		t.Errorf("/flags: %d %s", rec.Code, rec.Body.String())
+	}
+
+	var bi map[string]any
+	rec = adminGet(mux, "/buildinfo")
+	if err := json.Unmarshal(rec.Body.Bytes(), &bi); err != nil {
+		t.Fatalf("/buildinfo: %v", err)
+	}
+	if bi["version"] != "1.2.3" || bi["chassis_version"] != chassis.Version || bi["go_version"] == "" {
+		t.Errorf("/buildinfo = %v", bi)
+	}
+
+	if rec := adminGet(mux, "/debug/pprof/"); rec.Code != http.StatusNotFound {
+		t.Errorf("pprof mounted without Pprof: %d", rec.Code)
+	}
+}
+
+func TestAdminMux_UnsetEndpointsNotMounted(t *testing.T) {
+	mux := AdminMux(AdminConfig{})
+	for _, path := range []string{"/health", "/ready", "/metrics", "/flags"} {
+		if rec := adminGet(mux, path); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, want 404", path, rec.Code)
+		}
+	}
+}
+
+func TestAdminMux_AuthSparesProbes(t *testing.T) {
+	mux := AdminMux(AdminConfig{
+		Health:  http.NotFoundHandler(),
+		Ready:   http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		Metrics: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		Pprof:   true,
+		Auth:    denyAll,
+	})
+
+	if rec := adminGet(mux, "/ready"); rec.Code != http.StatusOK {
+		t.Errorf("/ready: status = %d, want 200 without auth", rec.Code)
+	}
+	for _, path := range []string{"/metrics", "/buildinfo", "/debug/pprof/", "/debug/pprof/cmdline"} {
+		if rec := adminGet(mux, path); rec.Code != http.StatusForbidden {
+			t.Errorf("%s: status = %d, want 403 from Auth", path, rec.Code)
+		}
+	}
+}
+
+func TestAdminMux_PprofRequiresAuth(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	AdminMux(AdminConfig{Pprof: true})
+}
@@ -0,0 +1,118 @@
+package httpkit
+
+import (
+	stderrors "errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/ai8future/chassis-go/v11/errors"
+	"github.com/ai8future/chassis-go/v11/secval"
+)
+
+// DefaultBindLimit is the body size limit Bind applies when
+// BindOptions.MaxBytes is zero.
+const DefaultBindLimit = 1 << 20
+
+// Validatable is implemented by request types that check their own fields.
+// Bind calls Validate after decoding. A returned *errors.ServiceError, such
+// as one built with errors.Validation, is written as is; any other error is
+// written as a 400 validation error.
+type Validatable interface {
+	Validate() error
+}
+
+// BindOptions configures Bind. The zero value accepts JSON bodies of up to
+// DefaultBindLimit bytes checked with the zero secval.Policy.
+type BindOptions struct {
+	// MaxBytes caps the body size. Zero means DefaultBindLimit.
+	MaxBytes int64
+	// Validator checks the body before it is decoded. Nil uses the zero
+	// secval.Policy.
+	Validator *secval.Validator
+}
+
+// Bind decodes the JSON request body into a T. It rejects a Content-Type
+// other than application/json or application/*+json with 415, a body over
+// MaxBytes with 413, a body that fails secval or does not decode into T
+// with 400, and, if T implements Validatable, a value that fails Validate.
+// On failure Bind writes the RFC 9457 Problem Details response and returns
+// false, so a handler reads:
+//
+//	req, ok := httpkit.Bind[CreateUserRequest](w, r, httpkit.BindOptions{})
+//	if !ok {
+//	    return
+//	}
+func Bind[T any](w http.ResponseWriter, r *http.Request, opts BindOptions) (T, bool) {
+	out, err := decodeBody[T](w, r, opts)
+	if err != nil {
+		JSONProblem(w, r, err)
+		return out, false
+	}
+	return out, true
+}
+
+// decodeBody implements Bind, returning the error to write on failure.
+func decodeBody[T any](w http.ResponseWriter, r *http.Request, opts BindOptions) (T, *errors.ServiceError) {
+	var out T
+	if !isJSON(r.Header.Get("Content-Type")) {
+		return out, errors.UnsupportedMediaTypeError("request body must be application/json")
+	}
+
+	limit := opts.MaxBytes
+	if limit <= 0 {
+		limit = DefaultBindLimit
+	}
+	if r.ContentLength > limit {
+		return out, errors.PayloadTooLargeError("request body too large")
+	}
+	if r.Body == nil {
+		return out, errors.ValidationError("request body is empty")
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if stderrors.As(err, &tooLarge) {
+			return out, errors.PayloadTooLargeError("request body too large")
+		}
+		return out, errors.ValidationError("could not read request body").WithCause(err)
+	}
+
+	out, err = secval.Decode[T](body, opts.Validator)
+	if err != nil {
+		return out, secval.ServiceError(err)
+	}
+
+	if err := validate(&out); err != nil {
+		var svcErr *errors.ServiceError
+		if stderrors.As(err, &svcErr) {
+			return out, svcErr
+		}
+		return out, errors.ValidationError(err.Error())
+	}
+	return out, nil
+}
+
+// isJSON reports whether contentType is application/json or a structured
+// +json type such as application/merge-patch+json.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" ||
+		strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json")
+}
+
+// validate calls Validate on *v, or on v when T is itself a pointer or
+// interface type implementing Validatable.
+func validate[T any](v *T) error {
+	if val, ok := any(v).(Validatable); ok {
+		return val.Validate()
+	}
+	if val, ok := any(*v).(Validatable); ok {
+		return val.Validate()
+	}
+	return nil
+}
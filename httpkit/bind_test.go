@@ -0,0 +1,140 @@
+package httpkit
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ai8future/chassis-go/v11/errors"
+	"github.com/ai8future/chassis-go/v11/secval"
+)
+
+type createUser struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func (c createUser) Validate() error {
+	if c.Name == "" {
+		return errors.Validation().Field("name", "is required").Err()
+	}
+	if c.Age < 0 {
+		return stderrors.New("age must not be negative")
+	}
+	return nil
+}
+
+type plainBody struct {
+	Value string `json:"value"`
+}
+
+func bindRequest(contentType, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req
+}
+
+func problemOf(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var pd map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &pd); err != nil {
+		t.Fatalf("decode problem: %v (body %q)", err, rec.Body.String())
+	}
+	return pd
+}
+
+func TestBind_Success(t *testing.T) {
+	rec := httptest.NewRecorder()
+	got, ok := Bind[createUser](rec, bindRequest("application/json; charset=utf-8", `{"name":"ada","age":36}`), BindOptions{})
+	if !ok {
+		t.Fatalf("Bind failed: %d %s", rec.Code, rec.Body.String())
+	}
+	if got.Name != "ada" || got.Age != 36 {
+		t.Fatalf("got %+v", got)
+	}
+	if rec.Body.Len() != 0 {
+		t.Fatalf("Bind wrote a response on success: %q", rec.Body.String())
+	}
+}
+
+func TestBind_StructuredJSONType(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if _, ok := Bind[plainBody](rec, bindRequest("application/merge-patch+json", `{"value":"x"}`), BindOptions{}); !ok {
+		t.Fatalf("Bind failed: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestBind_Failures(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        BindOptions
+		status      int
+	}{
+		{"no content type", "", `{"value":"x"}`, BindOptions{}, http.StatusUnsupportedMediaType},
+		{"wrong content type", "text/plain", `{"value":"x"}`, BindOptions{}, http.StatusUnsupportedMediaType},
+		{"too large", "application/json", `{"value":"` + strings.Repeat("x", 64) + `"}`, BindOptions{MaxBytes: 16}, http.StatusRequestEntityTooLarge},
+		{"malformed", "application/json", `{"value":`, BindOptions{}, http.StatusBadRequest},
+		{"type mismatch", "application/json", `{"value":1}`, BindOptions{}, http.StatusBadRequest},
+		{"dangerous key", "application/json", `{"__proto__":{}}`, BindOptions{}, http.StatusBadRequest},
+		{"policy", "application/json", `{"value":"x","$where":"1"}`, BindOptions{Validator: secval.New(secval.Policy{DeniedKeys: []string{"$where"}})}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if _, ok := Bind[plainBody](rec, bindRequest(tt.contentType, tt.body), tt.opts); ok {
+				t.Fatal("Bind succeeded, want failure")
+			}
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Fatalf("Content-Type = %q, want application/problem+json", ct)
+			}
+		})
+	}
+}
+
+func TestBind_ViolationDetails(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Bind[plainBody](rec, bindRequest("application/json", `{"items":[{"constructor":1}]}`), BindOptions{})
+
+	pd := problemOf(t, rec)
+	if pd["rule"] != secval.RuleDangerousKey || pd["pointer"] != "/items/0/constructor" {
+		t.Fatalf("problem = %v, want rule and pointer of the violation", pd)
+	}
+}
+
+func TestBind_Validate(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if _, ok := Bind[createUser](rec, bindRequest("application/json", `{"age":3}`), BindOptions{}); ok {
+		t.Fatal("Bind succeeded, want validation failure")
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+	if _, ok := problemOf(t, rec)["errors"]; !ok {
+		t.Fatalf("expected field errors from the ServiceError, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	if _, ok := Bind[createUser](rec, bindRequest("application/json", `{"name":"ada","age":-1}`), BindOptions{}); ok {
+		t.Fatal("Bind succeeded, want validation failure")
+	}
+	if pd := problemOf(t, rec); rec.Code != http.StatusBadRequest || pd["detail"] != "age must not be negative" {
+		t.Fatalf("got %d %v, want 400 with the Validate message", rec.Code, pd)
+	}
+}
+
+func TestBind_PointerType(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if _, ok := Bind[*createUser](rec, bindRequest("application/json", `{"age":3}`), BindOptions{}); ok {
+		t.Fatal("Bind succeeded, want validation failure via *T")
+	}
+}
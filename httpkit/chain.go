@@ -0,0 +1,61 @@
+package httpkit
+
+import "net/http"
+
+// Middleware is the standard net/http middleware signature shared by
+// httpkit, guard and most third-party routers.
+type Middleware = func(http.Handler) http.Handler
+
+// Stack is an ordered list of middleware, outermost first. The zero value is
+// an empty stack ready to use.
+//
+// The recommended order for a service is
+//
+//	httpkit.Chain(
+//	    httpkit.Recovery(logger),          // outermost: catches panics from everything below
+//	    httpkit.Tracing(),                 // span covers the whole request
+//	    httpkit.RequestID,                 // ID available to guards and logs
+//	    guard.Timeout(10*time.Second),     // guards: rate limits, body size, timeouts
+//	    httpkit.Logging(logger),           // innermost: logs the status the handler wrote
+//	).Then(mux)
+type Stack struct {
+	mws []Middleware
+}
+
+// Chain returns a Stack of mws, outermost first, so that
+//
+//	httpkit.Chain(a, b, c).Then(h)
+//
+// is equivalent to a(b(c(h))).
+func Chain(mws ...Middleware) Stack {
+	return Stack{mws: append([]Middleware(nil), mws...)}
+}
+
+// Use appends mws to the inside of the stack, after any middleware already
+// added.
+func (s *Stack) Use(mws ...Middleware) {
+	s.mws = append(s.mws[:len(s.mws):len(s.mws)], mws...)
+}
+
+// Then wraps h in the stack's middleware and returns the result. A nil h is
+// treated as http.DefaultServeMux. h is wrapped with CaptureRoute, so the
+// stack's middleware see the route pattern h matched. The stack is not
+// modified, so one stack can wrap several handlers.
+func (s Stack) Then(h http.Handler) http.Handler {
+	if h == nil {
+		h = http.DefaultServeMux
+	}
+	h = CaptureRoute(h)
+	for i := len(s.mws) - 1; i >= 0; i-- {
+		h = s.mws[i](h)
+	}
+	return h
+}
+
+// ThenFunc is Then for an http.HandlerFunc.
+func (s Stack) ThenFunc(fn http.HandlerFunc) http.Handler {
+	if fn == nil {
+		return s.Then(nil)
+	}
+	return s.Then(fn)
+}
@@ -0,0 +1,70 @@
+package httpkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// tag returns middleware that records name on the way in.
+func tag(trace *[]string, name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*trace = append(*trace, name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestChain_Order(t *testing.T) {
+	var trace []string
+	h := Chain(tag(&trace, "a"), tag(&trace, "b"), tag(&trace, "c")).
+		ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+			trace = append(trace, "handler")
+		})
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := strings.Join(trace, ","); got != "a,b,c,handler" {
+		t.Fatalf("order = %s, want a,b,c,handler", got)
+	}
+}
+
+func TestStack_Use(t *testing.T) {
+	var trace []string
+	var s Stack
+	s.Use(tag(&trace, "a"))
+	s.Use(tag(&trace, "b"), tag(&trace, "c"))
+
+	s.Then(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := strings.Join(trace, ","); got != "a,b,c" {
+		t.Fatalf("order = %s, want a,b,c", got)
+	}
+}
+
+func TestStack_UseDoesNotAliasChain(t *testing.T) {
+	var trace []string
+	base := Chain(tag(&trace, "a"))
+	s1, s2 := base, base
+	s1.Use(tag(&trace, "x"))
+	s2.Use(tag(&trace, "y"))
+
+	s1.Then(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := strings.Join(trace, ","); got != "a,x" {
+		t.Fatalf("order = %s, want a,x", got)
+	}
+}
+
+func TestChain_Empty(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Chain().ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusTeapot {
+		t.Fatalf("status = %d, want 418", rec.Code)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	chassis "github.com/ai8future/chassis-go/v11"
 	"github.com/ai8future/chassis-go/v11/errors"
 	"github.com/ai8future/chassis-go/v11/registry"
+	"github.com/ai8future/chassis-go/v11/requestid"
 	otelapi "go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -171,7 +172,7 @@ func TestJSONError_Format(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/err", nil)
 
 	// Add a request ID to context so it appears in the response.
-	ctx := context.WithValue(req.Context(), requestIDKey{}, "test-req-123")
+	ctx := requestid.NewContext(req.Context(), "test-req-123")
 	req = req.WithContext(ctx)
 
 	JSONError(rec, req, http.StatusNotFound, "not found")
@@ -0,0 +1,410 @@
+package httpkit
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+	"github.com/ai8future/chassis-go/v11/errors"
+	"github.com/ai8future/chassis-go/v11/registry"
+	"golang.org/x/sync/singleflight"
+)
+
+// JWTConfig configures JWTAuth.
+type JWTConfig struct {
+	// JWKSURL is the identity provider's JSON Web Key Set endpoint, such as
+	// https://issuer.example.com/.well-known/jwks.json. Required.
+	JWKSURL string
+	// Issuer must equal the token's iss claim. Required.
+	Issuer string
+	// Audience must be the token's aud claim or one of its entries.
+	// Required.
+	Audience string
+	// Leeway tolerates clock skew when checking exp, nbf and iat.
+	Leeway time.Duration
+	// RefreshInterval is how long fetched keys are cached. Zero means one
+	// hour. A token signed with an unknown key ID triggers an early refresh,
+	// at most once every minute, so key rotation is picked up promptly.
+	RefreshInterval time.Duration
+	// Client fetches the key set. Nil uses a client with a 10 second
+	// timeout.
+	Client *http.Client
+	// Logger receives key set fetch failures. Nil uses slog.Default().
+	Logger *slog.Logger
+}
+
+// Claims is the decoded payload of a verified token.
+type Claims map[string]any
+
+// Subject returns the sub claim, or "" if it is absent.
+func (c Claims) Subject() string {
+	s, _ := c["sub"].(string)
+	return s
+}
+
+// claimsKey is the context key under which JWTAuth stores Claims.
+type claimsKey struct{}
+
+// ClaimsFrom returns the claims of the token verified by JWTAuth, and false
+// if the request was not authenticated by it.
+func ClaimsFrom(ctx context.Context) (Claims, bool) {
+	c, ok := ctx.Value(claimsKey{}).(Claims)
+	return c, ok
+}
+
+// JWTAuth returns middleware that requires a bearer token signed with RS256
+// or ES256 by a key in cfg.JWKSURL, with an unexpired exp and the configured
+// issuer and audience. Verified claims are available to handlers through
+// ClaimsFrom. Requests without a valid token are rejected with 401 Problem
+// Details and a WWW-Authenticate challenge; if the key set cannot be
+// fetched they are rejected with 503. Keys are fetched on the first request,
+// not at construction. Panics if JWKSURL, Issuer or Audience is empty.
+func JWTAuth(cfg JWTConfig) func(http.Handler) http.Handler {
+	chassis.AssertVersionChecked()
+	if cfg.JWKSURL == "" {
+		panic("httpkit: JWTConfig.JWKSURL is required")
+	}
+	if cfg.Issuer == "" {
+		panic("httpkit: JWTConfig.Issuer is required")
+	}
+	if cfg.Audience == "" {
+		panic("httpkit: JWTConfig.Audience is required")
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = time.Hour
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	keys := &jwks{cfg: &cfg, minRefetch: time.Minute}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			registry.AssertActive()
+			token, ok := bearerToken(r)
+			if !ok {
+				JSONProblem(w, r, errors.UnauthorizedError("missing bearer token").
+					WithHeader("WWW-Authenticate", `Bearer realm="api"`))
+				return
+			}
+			claims, err := verifyJWT(r.Context(), token, keys, &cfg, time.Now())
+			if err != nil {
+				var svcErr *errors.ServiceError
+				if stderrors.As(err, &svcErr) {
+					JSONProblem(w, r, svcErr)
+					return
+				}
+				JSONProblem(w, r, errors.UnauthorizedError("invalid token: "+err.Error()).
+					WithHeader("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+		})
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// jwtHeader is the JOSE header of a token.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// verifyJWT checks token's signature and registered claims at now.
+func verifyJWT(ctx context.Context, token string, keys *jwks, cfg *JWTConfig, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, stderrors.New("malformed")
+	}
+	var hdr jwtHeader
+	if err := decodeSegment(parts[0], &hdr); err != nil {
+		return nil, stderrors.New("malformed header")
+	}
+	if hdr.Alg != "RS256" && hdr.Alg != "ES256" {
+		return nil, fmt.Errorf("unsupported algorithm %q", hdr.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, stderrors.New("malformed signature")
+	}
+
+	candidates, err := keys.lookup(ctx, hdr.Kid)
+	if err != nil {
+		return nil, errors.DependencyError("authentication keys unavailable").WithCause(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	verified := false
+	for _, key := range candidates {
+		if verifySignature(hdr.Alg, key, digest[:], sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, stderrors.New("signature verification failed")
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, stderrors.New("malformed claims")
+	}
+	if err := checkClaims(claims, cfg, now); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// verifySignature reports whether sig is alg's signature of digest by key.
+func verifySignature(alg string, key crypto.PublicKey, digest, sig []byte) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return alg == "RS256" && rsa.VerifyPKCS1v15(k, crypto.SHA256, digest, sig) == nil
+	case *ecdsa.PublicKey:
+		if alg != "ES256" || len(sig) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(k, digest, r, s)
+	}
+	return false
+}
+
+// checkClaims enforces exp, nbf, iat, iss and aud.
+func checkClaims(c Claims, cfg *JWTConfig, now time.Time) error {
+	exp, ok := numericDate(c, "exp")
+	if !ok {
+		return stderrors.New("missing exp claim")
+	}
+	if now.After(exp.Add(cfg.Leeway)) {
+		return stderrors.New("expired")
+	}
+	if nbf, ok := numericDate(c, "nbf"); ok && now.Add(cfg.Leeway).Before(nbf) {
+		return stderrors.New("not yet valid")
+	}
+	if iat, ok := numericDate(c, "iat"); ok && now.Add(cfg.Leeway).Before(iat) {
+		return stderrors.New("issued in the future")
+	}
+	if iss, _ := c["iss"].(string); iss != cfg.Issuer {
+		return stderrors.New("wrong issuer")
+	}
+	switch aud := c["aud"].(type) {
+	case string:
+		if aud == cfg.Audience {
+			return nil
+		}
+	case []any:
+		for _, a := range aud {
+			if a == cfg.Audience {
+				return nil
+			}
+		}
+	}
+	return stderrors.New("wrong audience")
+}
+
+// numericDate reads a NumericDate claim.
+func numericDate(c Claims, name string) (time.Time, bool) {
+	v, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(v), 0), true
+}
+
+// jwksFetchTimeout bounds one key set fetch, which runs detached from the
+// request that triggered it.
+const jwksFetchTimeout = 10 * time.Second
+
+// jwks caches the keys of a JSON Web Key Set.
+type jwks struct {
+	cfg        *JWTConfig
+	minRefetch time.Duration
+	refresh    singleflight.Group // one fetch at a time
+
+	mu        sync.Mutex // guards the fields below; never held across a fetch
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// lookup returns the keys matching kid, or every key when kid is empty.
+// Expired keys keep being served while one background refresh runs. A
+// caller waits only when nothing is cached yet, or when kid is unknown and
+// the last fetch is older than minRefetch; it then waits on the shared fetch
+// for as long as its ctx allows, without holding up other callers. A failed
+// refresh keeps serving the keys already cached.
+func (j *jwks) lookup(ctx context.Context, kid string) ([]crypto.PublicKey, error) {
+	j.mu.Lock()
+	keys, age := j.keys, time.Since(j.fetchedAt)
+	j.mu.Unlock()
+
+	_, known := keys[kid]
+	switch {
+	case keys == nil, kid != "" && !known && age >= j.minRefetch:
+		select {
+		case res := <-j.startRefresh(ctx):
+			if res.Err != nil && keys == nil {
+				return nil, res.Err
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		j.mu.Lock()
+		keys = j.keys
+		j.mu.Unlock()
+	case age >= j.cfg.RefreshInterval:
+		j.startRefresh(ctx)
+	}
+
+	if kid != "" {
+		if k, ok := keys[kid]; ok {
+			return []crypto.PublicKey{k}, nil
+		}
+		return nil, nil
+	}
+	out := make([]crypto.PublicKey, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, k)
+	}
+	return out, nil
+}
+
+// startRefresh fetches the key set in the background, joining a fetch
+// already in flight. The fetch keeps ctx's values but not its cancellation,
+// so a caller that gives up does not fail it for the others.
+func (j *jwks) startRefresh(ctx context.Context) <-chan singleflight.Result {
+	return j.refresh.DoChan("", func() (any, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jwksFetchTimeout)
+		defer cancel()
+		keys, err := j.fetch(fetchCtx)
+
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		j.fetchedAt = time.Now()
+		if err != nil {
+			if j.keys != nil {
+				j.cfg.Logger.WarnContext(ctx, "httpkit: JWKS refresh failed, using cached keys",
+					"url", j.cfg.JWKSURL, "error", err)
+			}
+			return nil, err
+		}
+		j.keys = keys
+		return nil, nil
+	})
+}
+
+// jwk is one key of a JSON Web Key Set (RFC 7517).
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// maxJWKSSize caps the key set response body.
+const maxJWKSSize = 1 << 20
+
+func (j *jwks) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.cfg.JWKSURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("httpkit: JWKS request: %w", err)
+	}
+	resp, err := j.cfg.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("httpkit: fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("httpkit: fetch JWKS: status %d", resp.StatusCode)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("httpkit: decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			j.cfg.Logger.WarnContext(ctx, "httpkit: skipping unusable JWKS key", "kid", k.Kid, "error", err)
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		return nil, stderrors.New("httpkit: JWKS has no usable signing keys")
+	}
+	return keys, nil
+}
+
+// publicKey decodes an RSA or P-256 key.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, stderrors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return nil, stderrors.New("invalid coordinates")
+		}
+		return ecdsa.ParseUncompressedPublicKey(elliptic.P256(), slices.Concat([]byte{4}, x, y))
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
@@ -0,0 +1,369 @@
+package httpkit
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	stderrors "errors"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var b64 = base64.RawURLEncoding
+
+// testIdP serves a JWKS and signs tokens with its keys.
+type testIdP struct {
+	srv     *httptest.Server
+	fetches atomic.Int32
+
+	mu   sync.Mutex
+	keys map[string]crypto.Signer
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	t.Helper()
+	idp := &testIdP{keys: map[string]crypto.Signer{}}
+	idp.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idp.fetches.Add(1)
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+		var set struct {
+			Keys []map[string]string `json:"keys"`
+		}
+		for kid, k := range idp.keys {
+			switch pub := k.Public().(type) {
+			case *rsa.PublicKey:
+				set.Keys = append(set.Keys, map[string]string{
+					"kty": "RSA", "kid": kid, "use": "sig",
+					"n": b64.EncodeToString(pub.N.Bytes()),
+					"e": b64.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+				})
+			case *ecdsa.PublicKey:
+				raw, _ := pub.Bytes()
+				set.Keys = append(set.Keys, map[string]string{
+					"kty": "EC", "kid": kid, "crv": "P-256",
+					"x": b64.EncodeToString(raw[1:33]), "y": b64.EncodeToString(raw[33:]),
+				})
+			}
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(idp.srv.Close)
+	return idp
+}
+
+func (idp *testIdP) addRSA(t *testing.T, kid string) {
+	t.Helper()
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp.mu.Lock()
+	idp.keys[kid] = k
+	idp.mu.Unlock()
+}
+
+func (idp *testIdP) addEC(t *testing.T, kid string) {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp.mu.Lock()
+	idp.keys[kid] = k
+	idp.mu.Unlock()
+}
+
+func (idp *testIdP) sign(t *testing.T, kid string, claims map[string]any) string {
+	t.Helper()
+	idp.mu.Lock()
+	key := idp.keys[kid]
+	idp.mu.Unlock()
+
+	alg := "RS256"
+	if _, ok := key.(*ecdsa.PrivateKey); ok {
+		alg = "ES256"
+	}
+	hdr, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	body, _ := json.Marshal(claims)
+	input := b64.EncodeToString(hdr) + "." + b64.EncodeToString(body)
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sig, _ = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+	return input + "." + b64.EncodeToString(sig)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss": "https://idp.test",
+		"aud": []string{"other", "orders-api"},
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iat": time.Now().Unix(),
+	}
+}
+
+func jwtHandler(idp *testIdP) http.Handler {
+	return JWTAuth(JWTConfig{
+		JWKSURL:  idp.srv.URL,
+		Issuer:   "https://idp.test",
+		Audience: "orders-api",
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := ClaimsFrom(r.Context())
+		if !ok {
+			w.WriteHeader(http.StatusTeapot)
+			return
+		}
+		w.Write([]byte(claims.Subject()))
+	}))
+}
+
+func doAuth(h http.Handler, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestJWTAuth_ValidTokens(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.addRSA(t, "rsa-1")
+	idp.addEC(t, "ec-1")
+	h := jwtHandler(idp)
+
+	for _, kid := range []string{"rsa-1", "ec-1"} {
+		rec := doAuth(h, idp.sign(t, kid, validClaims()))
+		if rec.Code != http.StatusOK || rec.Body.String() != "user-1" {
+			t.Fatalf("%s: status = %d, body %q", kid, rec.Code, rec.Body.String())
+		}
+	}
+	if n := idp.fetches.Load(); n != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1 (cached)", n)
+	}
+}
+
+func TestJWTAuth_Rejections(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.addRSA(t, "rsa-1")
+	h := jwtHandler(idp)
+
+	with := func(k string, v any) map[string]any {
+		c := validClaims()
+		if v == nil {
+			delete(c, k)
+		} else {
+			c[k] = v
+		}
+		return c
+	}
+	valid := idp.sign(t, "rsa-1", validClaims())
+	parts := strings.Split(valid, ".")
+	noneHdr := b64.EncodeToString([]byte(`{"alg":"none","kid":"rsa-1"}`))
+	tampered, _ := json.Marshal(with("sub", "admin"))
+
+	tests := map[string]string{
+		"missing":        "",
+		"garbage":        "not-a-jwt",
+		"expired":        idp.sign(t, "rsa-1", with("exp", time.Now().Add(-time.Minute).Unix())),
+		"no exp":         idp.sign(t, "rsa-1", with("exp", nil)),
+		"not yet valid":  idp.sign(t, "rsa-1", with("nbf", time.Now().Add(time.Hour).Unix())),
+		"wrong issuer":   idp.sign(t, "rsa-1", with("iss", "https://evil.test")),
+		"wrong audience": idp.sign(t, "rsa-1", with("aud", "billing-api")),
+		"alg none":       noneHdr + "." + parts[1] + ".",
+		"tampered":       parts[0] + "." + b64.EncodeToString(tampered) + "." + parts[2],
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := doAuth(h, token)
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want 401: %s", rec.Code, rec.Body.String())
+			}
+			if !strings.HasPrefix(rec.Header().Get("WWW-Authenticate"), "Bearer") {
+				t.Fatalf("WWW-Authenticate = %q", rec.Header().Get("WWW-Authenticate"))
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Fatalf("Content-Type = %q", ct)
+			}
+		})
+	}
+}
+
+func TestJWTAuth_KeyRotation(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.addRSA(t, "old")
+	keys := &jwks{cfg: &JWTConfig{
+		JWKSURL: idp.srv.URL, Issuer: "https://idp.test", Audience: "orders-api",
+		RefreshInterval: time.Hour, Client: http.DefaultClient,
+	}, minRefetch: 0}
+
+	if _, err := verifyJWT(t.Context(), idp.sign(t, "old", validClaims()), keys, keys.cfg, time.Now()); err != nil {
+		t.Fatalf("old key: %v", err)
+	}
+	idp.addEC(t, "new")
+	if _, err := verifyJWT(t.Context(), idp.sign(t, "new", validClaims()), keys, keys.cfg, time.Now()); err != nil {
+		t.Fatalf("rotated key: %v", err)
+	}
+	if n := idp.fetches.Load(); n != 2 {
+		t.Fatalf("JWKS fetched %d times, want 2 (refresh on unknown kid)", n)
+	}
+}
+
+// gatedJWKS returns a JWKS client whose endpoint serves idp's keys only
+// once gate is closed or receives a value.
+func gatedJWKS(t *testing.T, idp *testIdP, gate <-chan struct{}) (*jwks, *atomic.Int32) {
+	t.Helper()
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-gate
+		idp.srv.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return &jwks{cfg: &JWTConfig{
+		JWKSURL: srv.URL, Issuer: "https://idp.test", Audience: "orders-api",
+		RefreshInterval: time.Hour, Client: http.DefaultClient, Logger: slog.Default(),
+	}, minRefetch: time.Minute}, &fetches
+}
+
+func TestJWKS_ServesCachedKeysDuringRefresh(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.addRSA(t, "k")
+	gate := make(chan struct{}, 1)
+	keys, fetches := gatedJWKS(t, idp, gate)
+
+	gate <- struct{}{}
+	if got, err := keys.lookup(t.Context(), "k"); err != nil || len(got) != 1 {
+		t.Fatalf("first lookup = %v, %v", got, err)
+	}
+
+	// Expire the cache; the refresh blocks until gate is released.
+	keys.mu.Lock()
+	keys.fetchedAt = time.Now().Add(-2 * time.Hour)
+	keys.mu.Unlock()
+	for range 3 {
+		start := time.Now()
+		if got, err := keys.lookup(t.Context(), "k"); err != nil || len(got) != 1 {
+			t.Fatalf("lookup during refresh = %v, %v", got, err)
+		}
+		if d := time.Since(start); d > 100*time.Millisecond {
+			t.Fatalf("lookup waited %s for the refresh", d)
+		}
+	}
+	close(gate)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		keys.mu.Lock()
+		fresh := time.Since(keys.fetchedAt) < time.Hour
+		keys.mu.Unlock()
+		if fresh {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background refresh did not complete")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Fatalf("JWKS fetched %d times, want 2 (one shared refresh)", n)
+	}
+}
+
+func TestJWKS_CancelledCallerDoesNotFailSharedFetch(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.addRSA(t, "k")
+	gate := make(chan struct{})
+	keys, fetches := gatedJWKS(t, idp, gate)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := keys.lookup(ctx, "k")
+		errCh <- err
+	}()
+	for fetches.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-errCh; !stderrors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled lookup = %v, want context.Canceled", err)
+	}
+
+	close(gate)
+	if got, err := keys.lookup(t.Context(), "k"); err != nil || len(got) != 1 {
+		t.Fatalf("lookup after cancelled caller = %v, %v", got, err)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", n)
+	}
+}
+
+func TestJWKS_UnknownKidRefetchIsRateLimited(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.addRSA(t, "k")
+	gate := make(chan struct{})
+	close(gate)
+	keys, fetches := gatedJWKS(t, idp, gate)
+
+	if _, err := keys.lookup(t.Context(), "k"); err != nil {
+		t.Fatal(err)
+	}
+	for range 5 {
+		if got, err := keys.lookup(t.Context(), "forged"); err != nil || got != nil {
+			t.Fatalf("unknown kid = %v, %v", got, err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1 within minRefetch", n)
+	}
+}
+
+func TestJWTAuth_JWKSUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	idp := newTestIdP(t)
+	idp.addRSA(t, "k")
+	h := JWTAuth(JWTConfig{JWKSURL: srv.URL, Issuer: "https://idp.test", Audience: "orders-api"})(http.NotFoundHandler())
+
+	if rec := doAuth(h, idp.sign(t, "k", validClaims())); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rec.Code)
+	}
+}
+
+func TestJWTAuth_PanicsWithoutAudience(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	JWTAuth(JWTConfig{JWKSURL: "https://idp.test/jwks", Issuer: "https://idp.test"})
+}
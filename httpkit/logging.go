@@ -0,0 +1,111 @@
+package httpkit
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+)
+
+// LoggingOption configures Logging.
+type LoggingOption func(*loggingOptions)
+
+type loggingOptions struct {
+	query     bool
+	headers   []string
+	userAgent bool
+	bytes     bool
+	route     bool
+	sample    float64
+	sampleSet bool
+}
+
+// LogQuery adds the raw query string as "query". Leave it off for endpoints
+// that take secrets or personal data in the query.
+func LogQuery() LoggingOption {
+	return func(o *loggingOptions) {
+		o.query = true
+	}
+}
+
+// LogHeaders adds the named request headers, when present, as a "headers"
+// group keyed by lower-case name. Never list Authorization or Cookie.
+func LogHeaders(names ...string) LoggingOption {
+	return func(o *loggingOptions) {
+		o.headers = append(o.headers, names...)
+	}
+}
+
+// LogUserAgent adds the User-Agent header as "user_agent".
+func LogUserAgent() LoggingOption {
+	return func(o *loggingOptions) {
+		o.userAgent = true
+	}
+}
+
+// LogBytes adds the number of response body bytes written as "bytes".
+func LogBytes() LoggingOption {
+	return func(o *loggingOptions) {
+		o.bytes = true
+	}
+}
+
+// LogRoute adds the ServeMux pattern that matched the request, such as
+// "GET /users/{id}", as "route". It is empty for unmatched requests. Logging
+// sees the pattern when it wraps the mux directly, and otherwise when the mux
+// is wrapped with CaptureRoute, as Stack.Then does.
+func LogRoute() LoggingOption {
+	return func(o *loggingOptions) {
+		o.route = true
+	}
+}
+
+// SampleSuccess logs only the given fraction, between 0 and 1, of requests
+// that complete with a 2xx status. Redirects, client errors and server
+// errors are always logged. Panics if rate is outside [0, 1].
+func SampleSuccess(rate float64) LoggingOption {
+	if rate < 0 || rate > 1 {
+		panic("httpkit: SampleSuccess rate must be between 0 and 1")
+	}
+	return func(o *loggingOptions) {
+		o.sample = rate
+		o.sampleSet = true
+	}
+}
+
+// skip reports whether sampling drops the log entry of a request that
+// completed with status.
+func (o *loggingOptions) skip(status int) bool {
+	if !o.sampleSet || status < 200 || status >= 300 {
+		return false
+	}
+	return rand.Float64() >= o.sample
+}
+
+// appendAttrs appends the optional fields to attrs.
+func (o *loggingOptions) appendAttrs(attrs []slog.Attr, r *http.Request, rw *responseWriter, st *requestState) []slog.Attr {
+	if o.route {
+		attrs = append(attrs, slog.String("route", matchedPattern(r, st)))
+	}
+	if o.query && r.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", r.URL.RawQuery))
+	}
+	if o.userAgent {
+		attrs = append(attrs, slog.String("user_agent", r.UserAgent()))
+	}
+	if o.bytes {
+		attrs = append(attrs, slog.Int64("bytes", rw.bytesWritten))
+	}
+	if len(o.headers) > 0 {
+		var hs []any
+		for _, name := range o.headers {
+			if v := r.Header.Get(name); v != "" {
+				hs = append(hs, slog.String(strings.ToLower(name), v))
+			}
+		}
+		if len(hs) > 0 {
+			attrs = append(attrs, slog.Group("headers", hs...))
+		}
+	}
+	return attrs
+}
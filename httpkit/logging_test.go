@@ -0,0 +1,121 @@
+package httpkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// logLines decodes the JSON log entries written to buf.
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestLogging_Options(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	})
+	h := Logging(logger, LogQuery(), LogHeaders("X-Tenant", "X-Missing"), LogUserAgent(), LogBytes(), LogRoute())(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42?verbose=1", nil)
+	req.Header.Set("X-Tenant", "acme")
+	req.Header.Set("User-Agent", "probe/1.0")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := logLines(t, &buf)
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1", len(lines))
+	}
+	entry := lines[0]
+	want := map[string]any{
+		"route":      "GET /users/{id}",
+		"query":      "verbose=1",
+		"user_agent": "probe/1.0",
+		"bytes":      float64(5),
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s = %v, want %v", k, entry[k], v)
+		}
+	}
+	headers, _ := entry["headers"].(map[string]any)
+	if headers["x-tenant"] != "acme" || len(headers) != 1 {
+		t.Errorf("headers = %v, want only x-tenant", entry["headers"])
+	}
+}
+
+func TestLogging_DefaultFieldsOnly(t *testing.T) {
+	var buf bytes.Buffer
+	h := Logging(slog.New(slog.NewJSONHandler(&buf, nil)))(http.NotFoundHandler())
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/x?secret=1", nil))
+
+	entry := logLines(t, &buf)[0]
+	for _, k := range []string{"route", "query", "user_agent", "bytes", "headers"} {
+		if _, ok := entry[k]; ok {
+			t.Errorf("unexpected field %q without its option", k)
+		}
+	}
+}
+
+func TestLogging_SampleSuccess(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	status := http.StatusOK
+	h := Logging(logger, SampleSuccess(0))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	for range 10 {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	if n := len(logLines(t, &buf)); n != 0 {
+		t.Fatalf("logged %d successful requests at rate 0, want 0", n)
+	}
+
+	for _, status = range []int{http.StatusFound, http.StatusNotFound, http.StatusInternalServerError} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	if n := len(logLines(t, &buf)); n != 3 {
+		t.Fatalf("logged %d non-2xx requests, want all 3", n)
+	}
+}
+
+func TestLogging_SampleSuccessAll(t *testing.T) {
+	var buf bytes.Buffer
+	h := Logging(slog.New(slog.NewJSONHandler(&buf, nil)), SampleSuccess(1))(http.NotFoundHandler())
+	for range 5 {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	if n := len(logLines(t, &buf)); n != 5 {
+		t.Fatalf("logged %d requests at rate 1, want 5", n)
+	}
+}
+
+func TestSampleSuccess_PanicsOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	SampleSuccess(1.5)
+}
@@ -2,46 +2,21 @@ package httpkit
 
 import (
 	"context"
-	"crypto/rand"
-	"fmt"
 	"log/slog"
 	"net/http"
 	"runtime/debug"
-	"sync/atomic"
 	"time"
 
 	chassis "github.com/ai8future/chassis-go/v11"
 	"github.com/ai8future/chassis-go/v11/registry"
+	"github.com/ai8future/chassis-go/v11/requestid"
 )
 
-// idCounter is a fallback counter used when crypto/rand fails.
-var idCounter uint64
-
-// requestIDKey is the unexported context key used to store request IDs.
-type requestIDKey struct{}
-
 // RequestIDFrom retrieves the request ID from the context.
-// Returns an empty string if no request ID is present.
+// Returns an empty string if no request ID is present. It is
+// requestid.From, which gRPC handlers served by grpckit read as well.
 func RequestIDFrom(ctx context.Context) string {
-	v, ok := ctx.Value(requestIDKey{}).(string)
-	if !ok {
-		return ""
-	}
-	return v
-}
-
-// generateID produces a UUID-v4-like random identifier using crypto/rand.
-// Falls back to a timestamp+counter scheme if crypto/rand is unavailable.
-func generateID() string {
-	b := make([]byte, 16)
-	if _, err := rand.Read(b); err != nil {
-		return fmt.Sprintf("%x-%d", time.Now().UnixNano(), atomic.AddUint64(&idCounter, 1))
-	}
-	// Set version (4) and variant (RFC 4122) bits.
-	b[6] = (b[6] & 0x0f) | 0x40
-	b[8] = (b[8] & 0x3f) | 0x80
-	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x",
-		b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	return requestid.From(ctx)
 }
 
 // RequestID is middleware that generates a unique request ID, stores it in the
@@ -50,8 +25,11 @@ func RequestID(next http.Handler) http.Handler {
 	chassis.AssertVersionChecked()
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		registry.AssertActive()
-		id := generateID()
-		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		id := requestid.New()
+		if st, ok := r.Context().Value(stateKey{}).(*requestState); ok {
+			st.requestID = id
+		}
+		ctx := requestid.NewContext(r.Context(), id)
 		w.Header().Set("X-Request-ID", id)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -63,6 +41,7 @@ type responseWriter struct {
 	http.ResponseWriter
 	statusCode    int
 	headerWritten bool
+	bytesWritten  int64
 }
 
 // WriteHeader captures the status code and delegates to the underlying writer.
@@ -84,7 +63,9 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	if !rw.headerWritten {
 		rw.headerWritten = true
 	}
-	return rw.ResponseWriter.Write(b)
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytesWritten += int64(n)
+	return n, err
 }
 
 // Unwrap returns the underlying http.ResponseWriter so that
@@ -96,23 +77,36 @@ func (rw *responseWriter) Unwrap() http.ResponseWriter {
 
 // Logging returns middleware that logs each request's method, path, status code,
 // and duration using the provided structured logger. If a request ID is present
-// in the context, it is included in the log entry.
-func Logging(logger *slog.Logger) func(http.Handler) http.Handler {
+// in the context, it is included in the log entry. Options add fields and
+// sample successful requests; see LoggingOption.
+func Logging(logger *slog.Logger, opts ...LoggingOption) func(http.Handler) http.Handler {
 	chassis.AssertVersionChecked()
+	var o loggingOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			registry.AssertActive()
 			start := time.Now()
 			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			var st *requestState
+			if o.route {
+				r, st = withRequestState(r)
+			}
 
 			next.ServeHTTP(rw, r)
 
+			if o.skip(rw.statusCode) {
+				return
+			}
 			attrs := []slog.Attr{
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
 				slog.Int("status", rw.statusCode),
 				slog.Duration("duration", time.Since(start)),
 			}
+			attrs = o.appendAttrs(attrs, r, rw, st)
 			if id := RequestIDFrom(r.Context()); id != "" {
 				attrs = append(attrs, slog.String("request_id", id))
 			}
@@ -126,11 +120,22 @@ func Logging(logger *slog.Logger) func(http.Handler) http.Handler {
 // logs them at Error level with stack information, and returns a 500 JSON error.
 // If the handler has already started writing the response, the error body is
 // skipped to avoid corrupting the response.
-func Recovery(logger *slog.Logger) func(http.Handler) http.Handler {
+//
+// The log entry carries the request's method, path, matched route, request
+// ID and trace ID. Each panic also increments the http.server.panics counter
+// and is recorded as an exception on the request's span, and OnPanic hooks
+// receive a PanicReport, e.g. to forward it to an error tracker.
+func Recovery(logger *slog.Logger, opts ...RecoveryOption) func(http.Handler) http.Handler {
 	chassis.AssertVersionChecked()
+	var o recoveryOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			registry.AssertActive()
+			r, st := withRequestState(r)
+			st.recovering = true
 			// Ensure we have a responseWriter to track headerWritten state,
 			// whether or not Logging/Tracing middleware has already wrapped w.
 			rw, ok := w.(*responseWriter)
@@ -140,11 +145,12 @@ func Recovery(logger *slog.Logger) func(http.Handler) http.Handler {
 			}
 			defer func() {
 				if err := recover(); err != nil {
-					stack := debug.Stack()
-					logger.Error("panic recovered",
-						"error", fmt.Sprint(err),
-						"stack", string(stack),
-					)
+					report := newPanicReport(r, st, err, debug.Stack())
+					if report.RequestID != "" && RequestIDFrom(r.Context()) == "" {
+						r = r.WithContext(requestid.NewContext(r.Context(), report.RequestID))
+					}
+					logger.ErrorContext(r.Context(), "panic recovered", report.logAttrs()...)
+					o.report(r.Context(), st, report)
 					if rw.headerWritten {
 						return // headers already sent — cannot write error response
 					}
@@ -0,0 +1,158 @@
+package httpkit
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+	"github.com/ai8future/chassis-go/v11/call"
+	"github.com/ai8future/chassis-go/v11/errors"
+	"github.com/ai8future/chassis-go/v11/registry"
+)
+
+// ProxyOptions configures Proxy. The zero value forwards every header and
+// makes one attempt per request with call's default 30 second timeout.
+type ProxyOptions struct {
+	// Timeout bounds each upstream attempt, including reading the response
+	// body, so set it above the longest response you stream. Zero means 30
+	// seconds. A deadline already on the request, such as one set by
+	// guard.Timeout, takes precedence.
+	Timeout time.Duration
+	// Retries is the number of extra attempts for requests without a body
+	// that fail with a network error or a 5xx. Requests with a body are
+	// never retried, since it cannot be replayed.
+	Retries int
+	// RetryDelay is the base exponential backoff between retries. Zero
+	// means 100ms.
+	RetryDelay time.Duration
+	// Breaker, if set, guards the upstream; while it is open requests are
+	// rejected with 503 without being sent.
+	Breaker call.Breaker
+	// RequestHeaders, if non-nil, lists the only client request headers
+	// forwarded upstream. Trace context and X-Request-ID are always sent,
+	// as are the X-Forwarded-* headers the proxy sets.
+	RequestHeaders []string
+	// ResponseHeaders, if non-nil, lists the only upstream response headers
+	// returned to the client.
+	ResponseHeaders []string
+	// HTTPClient sends the upstream requests. Nil uses a client with the
+	// default transport that returns redirects to the client rather than
+	// following them. A custom client should do the same.
+	HTTPClient *http.Client
+	// Logger receives upstream failures. Nil uses slog.Default().
+	Logger *slog.Logger
+}
+
+// Proxy returns a reverse proxy to target built on httputil.ReverseProxy.
+// Upstream requests go through a call.Client, so they carry the incoming
+// request's trace context, are traced and measured as client calls, and
+// honour opts' timeout, retries and breaker. The incoming request ID is
+// forwarded as X-Request-ID. Upstream failures are answered with Problem
+// Details: 504 on timeout and 503 when the upstream is unreachable or the
+// breaker is open.
+//
+//	mux.Handle("/billing/", http.StripPrefix("/billing",
+//	    httpkit.Proxy(billingURL, httpkit.ProxyOptions{Timeout: 5 * time.Second, Retries: 2})))
+func Proxy(target *url.URL, opts ProxyOptions) http.Handler {
+	chassis.AssertVersionChecked()
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = 100 * time.Millisecond
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	var base []call.Option
+	if opts.Timeout > 0 {
+		base = append(base, call.WithTimeout(opts.Timeout))
+	}
+	if opts.Breaker != nil {
+		base = append(base, call.WithBreaker(opts.Breaker))
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+	}
+	base = append(base, call.WithHTTPClient(opts.HTTPClient))
+	once := call.New(base...)
+	rt := &callTransport{once: once, retry: once}
+	if opts.Retries > 0 {
+		rt.retry = call.New(append(base, call.WithRetry(opts.Retries+1, opts.RetryDelay))...)
+	}
+
+	rp := &httputil.ReverseProxy{
+		Transport: rt,
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.Out.RequestURI = "" // sent through an http.Client, which rejects it
+			if opts.RequestHeaders != nil {
+				keepHeaders(pr.Out.Header, opts.RequestHeaders)
+			}
+			pr.SetXForwarded()
+			if id := RequestIDFrom(pr.In.Context()); id != "" {
+				pr.Out.Header.Set("X-Request-ID", id)
+			}
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			if opts.ResponseHeaders != nil {
+				keepHeaders(resp.Header, opts.ResponseHeaders)
+			}
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if r.Context().Err() != nil && !stderrors.Is(err, context.DeadlineExceeded) {
+				return // the client went away; there is no one to answer
+			}
+			opts.Logger.WarnContext(r.Context(), "httpkit: proxy upstream failed",
+				"upstream", target.Host, "path", r.URL.Path, "error", err)
+			JSONProblem(w, r, proxyError(err))
+		},
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		registry.AssertActive()
+		rp.ServeHTTP(w, r)
+	})
+}
+
+// callTransport sends proxied requests through a call.Client, using the
+// retrying client only for requests that have no body to replay.
+type callTransport struct {
+	once, retry *call.Client
+}
+
+func (t *callTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return t.retry.Do(req)
+	}
+	return t.once.Do(req)
+}
+
+// keepHeaders removes every header not in allow, sparing trace context.
+func keepHeaders(h http.Header, allow []string) {
+	keep := map[string]bool{"Traceparent": true, "Tracestate": true, "Baggage": true}
+	for _, name := range allow {
+		keep[http.CanonicalHeaderKey(name)] = true
+	}
+	for name := range h {
+		if !keep[name] {
+			delete(h, name)
+		}
+	}
+}
+
+// proxyError maps an upstream failure to the ServiceError returned to the
+// client.
+func proxyError(err error) *errors.ServiceError {
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return errors.TimeoutError("upstream timed out").WithCause(err)
+	case stderrors.Is(err, call.ErrCircuitOpen):
+		return errors.DependencyError("upstream unavailable").WithCause(err)
+	}
+	return errors.DependencyError("upstream request failed").WithCause(err)
+}
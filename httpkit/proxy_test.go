@@ -0,0 +1,138 @@
+package httpkit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func upstream(t *testing.T, h http.HandlerFunc) *url.URL {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	u, _ := url.Parse(srv.URL)
+	return u
+}
+
+func TestProxy_ForwardsWithTraceAndRequestID(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	otelapi.SetTracerProvider(tp)
+	otelapi.SetTextMapPropagator(propagation.TraceContext{})
+
+	var got http.Header
+	var path string
+	target := upstream(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		path = r.URL.Path
+		w.Header().Set("X-Upstream", "yes")
+		w.Write([]byte("pong"))
+	})
+
+	h := Chain(Tracing(), RequestID).Then(Proxy(target, ProxyOptions{}))
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("X-Custom", "1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "pong" || rec.Header().Get("X-Upstream") != "yes" {
+		t.Fatalf("response = %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+	if path != "/ping" {
+		t.Fatalf("upstream path = %q", path)
+	}
+	if got.Get("Traceparent") == "" {
+		t.Fatal("traceparent not forwarded")
+	}
+	if got.Get("X-Request-ID") != rec.Header().Get("X-Request-ID") {
+		t.Fatalf("X-Request-ID = %q, want %q", got.Get("X-Request-ID"), rec.Header().Get("X-Request-ID"))
+	}
+	if got.Get("X-Custom") != "1" || got.Get("X-Forwarded-For") == "" {
+		t.Fatalf("upstream headers = %v", got)
+	}
+}
+
+func TestProxy_HeaderAllowLists(t *testing.T) {
+	var got http.Header
+	target := upstream(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("X-Public", "1")
+		w.Header().Set("X-Internal-Debug", "secret")
+	})
+
+	h := Proxy(target, ProxyOptions{
+		RequestHeaders:  []string{"accept"},
+		ResponseHeaders: []string{"X-Public"},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Cookie", "session=abc")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got.Get("Accept") != "application/json" || got.Get("Cookie") != "" {
+		t.Fatalf("upstream headers = %v, want Accept only", got)
+	}
+	if rec.Header().Get("X-Public") != "1" || rec.Header().Get("X-Internal-Debug") != "" {
+		t.Fatalf("response headers = %v, want X-Public only", rec.Header())
+	}
+}
+
+func TestProxy_RetriesOnlyBodilessRequests(t *testing.T) {
+	var calls atomic.Int32
+	target := upstream(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+	h := Proxy(target, ProxyOptions{Retries: 2, RetryDelay: time.Millisecond})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("GET: status = %d after %d calls, want 200 after 3", rec.Code, calls.Load())
+	}
+
+	calls.Store(0)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload")))
+	if rec.Code != http.StatusBadGateway || calls.Load() != 1 {
+		t.Fatalf("POST: status = %d after %d calls, want the upstream 502 after 1", rec.Code, calls.Load())
+	}
+}
+
+func TestProxy_UpstreamFailures(t *testing.T) {
+	slow := upstream(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	rec := httptest.NewRecorder()
+	Proxy(slow, ProxyOptions{Timeout: 20 * time.Millisecond}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusGatewayTimeout || rec.Header().Get("Content-Type") != "application/problem+json" {
+		t.Fatalf("timeout: status = %d, Content-Type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	deadURL, _ := url.Parse(closed.URL)
+	closed.Close()
+	rec = httptest.NewRecorder()
+	Proxy(deadURL, ProxyOptions{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unreachable: status = %d, want 503", rec.Code)
+	}
+}
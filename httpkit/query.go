@@ -0,0 +1,113 @@
+package httpkit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ai8future/chassis-go/v11/errors"
+)
+
+// QueryValue is the set of types Query can parse.
+type QueryValue interface {
+	string | int | int64 | float64 | bool | time.Duration
+}
+
+// Query returns the query parameter name parsed as a T, or def when it is
+// absent or empty. A value that does not parse is reported as a validation
+// *errors.ServiceError naming the parameter, ready for JSONProblem:
+//
+//	verbose, err := httpkit.Query(r, "verbose", false)
+//	if err != nil {
+//	    httpkit.JSONProblem(w, r, errors.FromError(err))
+//	    return
+//	}
+//
+// Booleans accept the forms strconv.ParseBool does and durations the forms
+// time.ParseDuration does.
+func Query[T QueryValue](r *http.Request, name string, def T) (T, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	var out T
+	var err error
+	var want string
+	switch p := any(&out).(type) {
+	case *string:
+		*p = raw
+	case *int:
+		*p, err = strconv.Atoi(raw)
+		want = "an integer"
+	case *int64:
+		*p, err = strconv.ParseInt(raw, 10, 64)
+		want = "an integer"
+	case *float64:
+		*p, err = strconv.ParseFloat(raw, 64)
+		want = "a number"
+	case *bool:
+		*p, err = strconv.ParseBool(raw)
+		want = "true or false"
+	case *time.Duration:
+		*p, err = time.ParseDuration(raw)
+		want = "a duration such as 30s"
+	}
+	if err != nil {
+		return def, errors.Validation().Field(name, "must be "+want).Err()
+	}
+	return out, nil
+}
+
+// PageDefaults configures ParsePage.
+type PageDefaults struct {
+	// Limit is used when the request has no limit. Zero means 20.
+	Limit int
+	// MaxLimit is the largest limit accepted. Zero means 100.
+	MaxLimit int
+}
+
+// Page is a parsed pagination request. Offset and Cursor are mutually
+// exclusive; at most one is set.
+type Page struct {
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+// ParsePage reads the limit, offset and cursor query parameters. limit must
+// be between 1 and defaults.MaxLimit, offset must not be negative, and
+// offset and cursor cannot both be given. Every violation is collected into
+// one validation *errors.ServiceError with a field error per parameter.
+func ParsePage(r *http.Request, defaults PageDefaults) (Page, error) {
+	if defaults.Limit <= 0 {
+		defaults.Limit = 20
+	}
+	if defaults.MaxLimit <= 0 {
+		defaults.MaxLimit = 100
+	}
+	v := errors.Validation()
+
+	limit, err := Query(r, "limit", defaults.Limit)
+	switch {
+	case err != nil:
+		v.Field("limit", "must be an integer")
+	case limit < 1 || limit > defaults.MaxLimit:
+		v.Field("limit", "must be between 1 and "+strconv.Itoa(defaults.MaxLimit))
+	}
+	offset, err := Query(r, "offset", 0)
+	switch {
+	case err != nil:
+		v.Field("offset", "must be an integer")
+	case offset < 0:
+		v.Field("offset", "must not be negative")
+	}
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" && r.URL.Query().Has("offset") {
+		v.Field("cursor", "cannot be combined with offset")
+	}
+
+	if err := v.Err(); err != nil {
+		return Page{}, err
+	}
+	return Page{Limit: limit, Offset: offset, Cursor: cursor}, nil
+}
@@ -0,0 +1,101 @@
+package httpkit
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ai8future/chassis-go/v11/errors"
+)
+
+func queryReq(q string) *http.Request {
+	return httptest.NewRequest(http.MethodGet, "/items?"+q, nil)
+}
+
+func TestQuery_Types(t *testing.T) {
+	r := queryReq("s=hi&i=-3&n=9000000000&f=1.5&b=true&d=90s")
+
+	if v, err := Query(r, "s", ""); err != nil || v != "hi" {
+		t.Errorf("string: %v, %v", v, err)
+	}
+	if v, err := Query(r, "i", 0); err != nil || v != -3 {
+		t.Errorf("int: %v, %v", v, err)
+	}
+	if v, err := Query(r, "n", int64(0)); err != nil || v != 9000000000 {
+		t.Errorf("int64: %v, %v", v, err)
+	}
+	if v, err := Query(r, "f", 0.0); err != nil || v != 1.5 {
+		t.Errorf("float64: %v, %v", v, err)
+	}
+	if v, err := Query(r, "b", false); err != nil || !v {
+		t.Errorf("bool: %v, %v", v, err)
+	}
+	if v, err := Query(r, "d", time.Second); err != nil || v != 90*time.Second {
+		t.Errorf("duration: %v, %v", v, err)
+	}
+	if v, err := Query(r, "missing", 7); err != nil || v != 7 {
+		t.Errorf("default: %v, %v", v, err)
+	}
+}
+
+func TestQuery_Invalid(t *testing.T) {
+	v, err := Query(queryReq("limit=ten"), "limit", 5)
+	if v != 5 {
+		t.Errorf("value = %d, want the default on error", v)
+	}
+	var svcErr *errors.ServiceError
+	if !stderrors.As(err, &svcErr) || svcErr.HTTPCode != http.StatusBadRequest {
+		t.Fatalf("err = %v, want a 400 ServiceError", err)
+	}
+	fields, _ := svcErr.Details["errors"].([]errors.FieldError)
+	if len(fields) != 1 || fields[0].Field != "limit" {
+		t.Fatalf("field errors = %v, want one for limit", svcErr.Details["errors"])
+	}
+}
+
+func TestParsePage(t *testing.T) {
+	defaults := PageDefaults{Limit: 25, MaxLimit: 50}
+
+	p, err := ParsePage(queryReq(""), defaults)
+	if err != nil || p != (Page{Limit: 25}) {
+		t.Fatalf("defaults: %+v, %v", p, err)
+	}
+	p, err = ParsePage(queryReq("limit=10&offset=30"), defaults)
+	if err != nil || p != (Page{Limit: 10, Offset: 30}) {
+		t.Fatalf("offset: %+v, %v", p, err)
+	}
+	p, err = ParsePage(queryReq("limit=50&cursor=abc"), defaults)
+	if err != nil || p != (Page{Limit: 50, Cursor: "abc"}) {
+		t.Fatalf("cursor: %+v, %v", p, err)
+	}
+	p, err = ParsePage(queryReq(""), PageDefaults{})
+	if err != nil || p.Limit != 20 {
+		t.Fatalf("zero defaults: %+v, %v", p, err)
+	}
+}
+
+func TestParsePage_Invalid(t *testing.T) {
+	tests := map[string]int{
+		"limit=0":                   1,
+		"limit=51":                  1,
+		"limit=x":                   1,
+		"offset=-1":                 1,
+		"offset=1&cursor=abc":       1,
+		"limit=0&offset=-1":         2,
+		"limit=x&offset=y&cursor=z": 3,
+	}
+	for q, want := range tests {
+		t.Run(q, func(t *testing.T) {
+			_, err := ParsePage(queryReq(q), PageDefaults{MaxLimit: 50})
+			var svcErr *errors.ServiceError
+			if !stderrors.As(err, &svcErr) {
+				t.Fatalf("err = %v, want a ServiceError", err)
+			}
+			if fields, _ := svcErr.Details["errors"].([]errors.FieldError); len(fields) != want {
+				t.Fatalf("field errors = %v, want %d", fields, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package httpkit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RecoveryOption configures Recovery.
+type RecoveryOption func(*recoveryOptions)
+
+type recoveryOptions struct {
+	hooks []func(context.Context, PanicReport)
+}
+
+// OnPanic registers fn to receive a PanicReport for every recovered panic,
+// after it is logged and before the 500 response is written. Use it to
+// forward panics to an error tracker such as Sentry. fn runs on the request
+// goroutine; a panic in fn is not recovered.
+func OnPanic(fn func(ctx context.Context, report PanicReport)) RecoveryOption {
+	return func(o *recoveryOptions) {
+		o.hooks = append(o.hooks, fn)
+	}
+}
+
+// PanicReport describes a panic recovered by Recovery.
+type PanicReport struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the goroutine's stack trace at the point of recovery.
+	Stack  []byte
+	Method string
+	Path   string
+	// Route is the ServeMux pattern that matched, or empty.
+	Route string
+	// RequestID is the ID set by RequestID, or empty.
+	RequestID string
+	// TraceID is the trace of the request's span, or empty.
+	TraceID string
+}
+
+// newPanicReport snapshots the request that panicked.
+func newPanicReport(r *http.Request, st *requestState, value any, stack []byte) PanicReport {
+	report := PanicReport{
+		Value:     value,
+		Stack:     stack,
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Route:     matchedPattern(r, st),
+		RequestID: RequestIDFrom(r.Context()),
+	}
+	if report.RequestID == "" {
+		report.RequestID = st.requestID
+	}
+	if sc := panicSpan(r.Context(), st).SpanContext(); sc.HasTraceID() {
+		report.TraceID = sc.TraceID().String()
+	}
+	return report
+}
+
+// panicSpan returns the span Tracing left open for Recovery, or the span
+// already in ctx when Tracing runs outside Recovery.
+func panicSpan(ctx context.Context, st *requestState) trace.Span {
+	if st.panicSpan != nil {
+		return st.panicSpan
+	}
+	return trace.SpanFromContext(ctx)
+}
+
+func (p PanicReport) logAttrs() []any {
+	attrs := []any{
+		"error", fmt.Sprint(p.Value),
+		"stack", string(p.Stack),
+		"method", p.Method,
+		"path", p.Path,
+	}
+	if p.Route != "" {
+		attrs = append(attrs, "route", p.Route)
+	}
+	if p.RequestID != "" {
+		attrs = append(attrs, "request_id", p.RequestID)
+	}
+	if p.TraceID != "" {
+		attrs = append(attrs, "trace_id", p.TraceID)
+	}
+	return attrs
+}
+
+// report counts the panic, records it on the request's span, and calls the
+// OnPanic hooks.
+func (o *recoveryOptions) report(ctx context.Context, st *requestState, p PanicReport) {
+	attrs := []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(p.Method)}
+	if route := httpRoute(p.Route); route != "" {
+		attrs = append(attrs, semconv.HTTPRoute(route))
+	}
+	if c := getPanicCounter(); c != nil {
+		c.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+
+	span := panicSpan(ctx, st)
+	span.RecordError(fmt.Errorf("panic: %v", p.Value), trace.WithAttributes(
+		semconv.ExceptionStacktrace(string(p.Stack)),
+	))
+	span.SetStatus(codes.Error, "panic")
+	if st.panicSpan != nil {
+		st.panicSpan.SetAttributes(semconv.HTTPResponseStatusCode(http.StatusInternalServerError))
+		st.panicSpan.End()
+		st.panicSpan = nil
+	}
+
+	for _, fn := range o.hooks {
+		fn(ctx, p)
+	}
+}
@@ -0,0 +1,108 @@
+package httpkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRecovery_PanicReport(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	otelapi.SetTracerProvider(tp)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	var reports []PanicReport
+	hook := OnPanic(func(_ context.Context, p PanicReport) { reports = append(reports, p) })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	h := Chain(Recovery(logger, hook), Tracing(), RequestID).Then(mux)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/9", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("OnPanic called %d times, want 1", len(reports))
+	}
+	p := reports[0]
+	if p.Value != "boom" || p.Method != http.MethodGet || p.Path != "/orders/9" || p.Route != "GET /orders/{id}" {
+		t.Fatalf("report = %+v", p)
+	}
+	if p.RequestID == "" || p.RequestID != rec.Header().Get("X-Request-ID") {
+		t.Fatalf("RequestID = %q, want %q", p.RequestID, rec.Header().Get("X-Request-ID"))
+	}
+	if p.TraceID == "" || len(p.Stack) == 0 {
+		t.Fatalf("TraceID = %q, stack %d bytes", p.TraceID, len(p.Stack))
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body["request_id"] != p.RequestID {
+		t.Fatalf("problem request_id = %v, want %q", body["request_id"], p.RequestID)
+	}
+
+	line := logLines(t, &buf)[0]
+	if line["route"] != p.Route || line["request_id"] != p.RequestID || line["trace_id"] != p.TraceID {
+		t.Fatalf("log entry = %v", line)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 ended span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Status.Code != codes.Error {
+		t.Fatalf("span status = %v, want Error", span.Status.Code)
+	}
+	if span.SpanContext.TraceID().String() != p.TraceID {
+		t.Fatalf("span trace = %s, want %s", span.SpanContext.TraceID(), p.TraceID)
+	}
+	var exception bool
+	for _, e := range span.Events {
+		if e.Name == "exception" {
+			exception = true
+		}
+	}
+	if !exception {
+		t.Fatalf("span events = %v, want an exception", span.Events)
+	}
+}
+
+func TestRecovery_InsideTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	otelapi.SetTracerProvider(tp)
+
+	var got PanicReport
+	logger := slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil))
+	h := Chain(Tracing(), Recovery(logger, OnPanic(func(_ context.Context, p PanicReport) { got = p }))).
+		ThenFunc(func(w http.ResponseWriter, r *http.Request) { panic("inner") })
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Status.Code != codes.Error {
+		t.Fatalf("spans = %d, want 1 with Error status", len(spans))
+	}
+	if got.TraceID != spans[0].SpanContext.TraceID().String() {
+		t.Fatalf("TraceID = %q, want the Tracing span's", got.TraceID)
+	}
+}
@@ -2,7 +2,16 @@
 package httpkit
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/ai8future/chassis-go/v11/errors"
 )
@@ -25,6 +34,130 @@ func JSONProblem(w http.ResponseWriter, r *http.Request, err *errors.ServiceErro
 	errors.WriteProblem(w, r, err, requestID)
 }
 
+// ResponseOption configures JSON.
+type ResponseOption func(*responseOptions)
+
+type responseOptions struct {
+	etagRequest *http.Request
+}
+
+// WithETag sets a strong ETag derived from the encoded body and, when r is
+// a GET or HEAD whose If-None-Match matches it, answers 304 Not Modified
+// without a body. It only applies to 200 responses.
+func WithETag(r *http.Request) ResponseOption {
+	return func(o *responseOptions) {
+		o.etagRequest = r
+	}
+}
+
+// JSON writes v as a JSON response with the given status code. The body is
+// encoded before anything is written, so an encoding failure is logged and
+// answered with a 500 Problem Details response instead of a truncated body.
+// Content-Type and Content-Length are always set.
+func JSON(w http.ResponseWriter, status int, v any, opts ...ResponseOption) {
+	var o responseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		r := o.etagRequest
+		if r == nil {
+			r = &http.Request{}
+		}
+		slog.ErrorContext(r.Context(), "httpkit: failed to encode JSON response", "error", err)
+		JSONProblem(w, r, errors.InternalError("failed to encode response").WithCause(err))
+		return
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "application/json")
+	if r := o.etagRequest; r != nil && status == http.StatusOK {
+		sum := sha256.Sum256(buf.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		h.Set("ETag", etag)
+		if (r.Method == http.MethodGet || r.Method == http.MethodHead) && etagMatch(r.Header.Get("If-None-Match"), etag) {
+			h.Del("Content-Type")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	h.Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(status)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		slog.Debug("httpkit: failed to write JSON response", "error", err)
+	}
+}
+
+// etagMatch reports whether an If-None-Match header matches etag, using
+// the weak comparison RFC 9110 specifies for If-None-Match.
+func etagMatch(header, etag string) bool {
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// NoContent writes a 204 No Content response.
+func NoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Stream writes a 200 response of contentType whose body fn produces,
+// flushing after every write so clients see data as it is produced. The
+// headers, including Cache-Control: no-cache, are sent on fn's first write.
+// If fn fails before writing anything, its error is written as Problem
+// Details with JSONProblem; after that the status has been sent, so the
+// error is logged and the response ends.
+func Stream(w http.ResponseWriter, r *http.Request, contentType string, fn func(w io.Writer) error) {
+	sw := &streamWriter{w: w, rc: http.NewResponseController(w), contentType: contentType}
+	err := fn(sw)
+	switch {
+	case err == nil && !sw.started:
+		sw.start()
+	case err != nil && !sw.started:
+		JSONProblem(w, r, errors.FromError(err))
+	case err != nil:
+		slog.ErrorContext(r.Context(), "httpkit: stream failed", "error", err, "path", r.URL.Path)
+	}
+}
+
+// streamWriter sends the stream's headers on the first write and flushes
+// after each one.
+type streamWriter struct {
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	contentType string
+	started     bool
+}
+
+func (s *streamWriter) start() {
+	s.started = true
+	h := s.w.Header()
+	h.Set("Content-Type", s.contentType)
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Content-Type-Options", "nosniff")
+	s.w.WriteHeader(http.StatusOK)
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if !s.started {
+		s.start()
+	}
+	n, err := s.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	if err := s.rc.Flush(); err != nil && !stderrors.Is(err, http.ErrNotSupported) {
+		return n, err
+	}
+	return n, nil
+}
+
 // errorForStatus maps an HTTP status code to an appropriate ServiceError factory.
 // For unmapped status codes, returns an InternalError but preserves the
 // caller's original HTTP status code.
@@ -44,6 +177,8 @@ func errorForStatus(code int, message string) *errors.ServiceError {
 		return errors.PayloadTooLargeError(message)
 	case http.StatusTooManyRequests:
 		return errors.RateLimitError(message)
+	case http.StatusUnsupportedMediaType:
+		return errors.UnsupportedMediaTypeError(message)
 	case http.StatusServiceUnavailable:
 		return errors.DependencyError(message)
 	case http.StatusInternalServerError:
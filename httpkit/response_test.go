@@ -0,0 +1,145 @@
+package httpkit
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ai8future/chassis-go/v11/errors"
+)
+
+func TestJSON_WritesBody(t *testing.T) {
+	rec := httptest.NewRecorder()
+	JSON(rec, http.StatusCreated, map[string]string{"id": "42"})
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"id":"42"}` {
+		t.Fatalf("body = %s", got)
+	}
+	if cl := rec.Header().Get("Content-Length"); cl != fmt.Sprint(rec.Body.Len()) {
+		t.Fatalf("Content-Length = %q, body is %d bytes", cl, rec.Body.Len())
+	}
+	if rec.Header().Get("ETag") != "" {
+		t.Fatal("ETag set without WithETag")
+	}
+}
+
+func TestJSON_EncodeError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	JSON(rec, http.StatusOK, math.Inf(1))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("Content-Type = %q, want application/problem+json", ct)
+	}
+}
+
+func TestJSON_ETag(t *testing.T) {
+	body := map[string]int{"n": 1}
+	req := httptest.NewRequest(http.MethodGet, "/things/1", nil)
+	rec := httptest.NewRecorder()
+	JSON(rec, http.StatusOK, body, WithETag(req))
+
+	etag := rec.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `"`) || rec.Code != http.StatusOK {
+		t.Fatalf("ETag = %q, status %d", etag, rec.Code)
+	}
+
+	for _, inm := range []string{etag, `"other", W/` + etag, "*"} {
+		req := httptest.NewRequest(http.MethodGet, "/things/1", nil)
+		req.Header.Set("If-None-Match", inm)
+		rec := httptest.NewRecorder()
+		JSON(rec, http.StatusOK, body, WithETag(req))
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Fatalf("If-None-Match %s: status = %d, body %q; want 304 and no body", inm, rec.Code, rec.Body.String())
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/things/1", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	rec = httptest.NewRecorder()
+	JSON(rec, http.StatusOK, map[string]int{"n": 2}, WithETag(req))
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("changed body: status = %d, ETag %q; want 200 and a new ETag", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestNoContent(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NoContent(rec)
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Fatalf("status = %d, body %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestStream_WritesAndFlushes(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	Stream(rec, req, "text/event-stream", func(w io.Writer) error {
+		for i := range 3 {
+			fmt.Fprintf(w, "data: %d\n\n", i)
+		}
+		return nil
+	})
+
+	if rec.Code != http.StatusOK || !rec.Flushed {
+		t.Fatalf("status = %d, flushed = %v", rec.Code, rec.Flushed)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	if rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Fatal("expected Cache-Control: no-cache")
+	}
+	if strings.Count(rec.Body.String(), "data:") != 3 {
+		t.Fatalf("body = %q", rec.Body.String())
+	}
+}
+
+func TestStream_ErrorBeforeWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/export", nil)
+	Stream(rec, req, "text/csv", func(io.Writer) error {
+		return errors.NotFoundError("no such export")
+	})
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("Content-Type = %q, want application/problem+json", ct)
+	}
+}
+
+func TestStream_ErrorAfterWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/export", nil)
+	Stream(rec, req, "text/csv", func(w io.Writer) error {
+		io.WriteString(w, "a,b\n")
+		return stderrors.New("database went away")
+	})
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "a,b\n" {
+		t.Fatalf("status = %d, body %q; want the partial stream untouched", rec.Code, rec.Body.String())
+	}
+}
+
+func TestStream_Empty(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Stream(rec, httptest.NewRequest(http.MethodGet, "/", nil), "text/plain", func(io.Writer) error { return nil })
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/plain" {
+		t.Fatalf("status = %d, Content-Type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+}
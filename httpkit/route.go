@@ -0,0 +1,79 @@
+package httpkit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// stateKey is the context key of the *requestState shared by the middleware
+// of one request.
+type stateKey struct{}
+
+// requestState carries what inner handlers learn about a request back out
+// to the middleware that wraps them. Middleware that replaces the request
+// with WithContext hides changes made to the replacement, such as the
+// Request.Pattern a ServeMux sets, from middleware further out.
+type requestState struct {
+	// pattern is the ServeMux pattern that matched.
+	pattern string
+	// requestID is the ID generated by RequestID.
+	requestID string
+	// recovering is set by Recovery, so that Tracing leaves the span of a
+	// panicking request open for Recovery to record the panic on.
+	recovering bool
+	// panicSpan is the span Tracing left open.
+	panicSpan trace.Span
+}
+
+// withRequestState returns r with a *requestState in its context, reusing
+// one installed by outer middleware.
+func withRequestState(r *http.Request) (*http.Request, *requestState) {
+	if st, ok := r.Context().Value(stateKey{}).(*requestState); ok {
+		return r, st
+	}
+	st := &requestState{}
+	return r.WithContext(context.WithValue(r.Context(), stateKey{}, st)), st
+}
+
+// matchedPattern returns the pattern recorded in st, or r.Pattern when r is
+// the request the mux itself served.
+func matchedPattern(r *http.Request, st *requestState) string {
+	if st != nil && st.pattern != "" {
+		return st.pattern
+	}
+	return r.Pattern
+}
+
+// httpRoute returns the path part of a ServeMux pattern, dropping the
+// method and host: "GET example.com/users/{id}" becomes "/users/{id}".
+func httpRoute(pattern string) string {
+	if _, rest, ok := strings.Cut(pattern, " "); ok {
+		pattern = strings.TrimLeft(rest, " \t")
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
+
+// CaptureRoute wraps a ServeMux, or any handler that sets Request.Pattern,
+// so that Tracing, Logging and Recovery further out can report the matched
+// route pattern, such as "/users/{id}", instead of the raw path. Stack.Then
+// applies it automatically; call it yourself only when nesting middleware
+// by hand:
+//
+//	handler := httpkit.Tracing()(httpkit.RequestID(httpkit.CaptureRoute(mux)))
+func CaptureRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Deferred so that the route of a panicking request is still recorded.
+		defer func() {
+			if st, ok := r.Context().Value(stateKey{}).(*requestState); ok && r.Pattern != "" {
+				st.pattern = r.Pattern
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
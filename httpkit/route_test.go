@@ -0,0 +1,90 @@
+package httpkit
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	otelapi "go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+func routedMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	return mux
+}
+
+func TestHTTPRoute(t *testing.T) {
+	tests := map[string]string{
+		"GET /users/{id}":          "/users/{id}",
+		"/users/{id}":              "/users/{id}",
+		"POST example.com/orders/": "/orders/",
+		"example.com/":             "/",
+		"":                         "",
+	}
+	for pattern, want := range tests {
+		if got := httpRoute(pattern); got != want {
+			t.Errorf("httpRoute(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+func TestTracing_RouteThroughChain(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	otelapi.SetTracerProvider(tp)
+
+	// RequestID replaces the request between Tracing and the mux.
+	h := Chain(Tracing(), RequestID).Then(routedMux())
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nowhere", nil))
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "GET /users/{id}" {
+		t.Fatalf("span name = %q, want %q", spans[0].Name, "GET /users/{id}")
+	}
+	var route string
+	for _, a := range spans[0].Attributes {
+		if a.Key == semconv.HTTPRouteKey {
+			route = a.Value.AsString()
+		}
+	}
+	if route != "/users/{id}" {
+		t.Fatalf("http.route = %q, want /users/{id}", route)
+	}
+	if spans[1].Name != "GET" {
+		t.Fatalf("unmatched span name = %q, want GET", spans[1].Name)
+	}
+}
+
+func TestLogging_RouteThroughChain(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	h := Chain(Logging(logger, LogRoute()), RequestID).Then(routedMux())
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if got := logLines(t, &buf)[0]["route"]; got != "GET /users/{id}" {
+		t.Fatalf("route = %v, want GET /users/{id}", got)
+	}
+}
+
+func TestCaptureRoute_ManualNesting(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	h := Logging(logger, LogRoute())(RequestID(CaptureRoute(routedMux())))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/7", nil))
+
+	if got := logLines(t, &buf)[0]["route"]; got != "GET /users/{id}" {
+		t.Fatalf("route = %v, want GET /users/{id}", got)
+	}
+}
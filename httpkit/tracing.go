@@ -8,6 +8,7 @@ import (
 	"github.com/ai8future/chassis-go/v11/internal/otelutil"
 	"github.com/ai8future/chassis-go/v11/registry"
 	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
@@ -24,17 +25,29 @@ var getHTTPDurationHistogram = otelutil.LazyHistogram(
 	metric.WithDescription("Duration of HTTP server requests"),
 )
 
+var getPanicCounter = otelutil.LazyCounter(
+	tracerName,
+	"http.server.panics",
+	metric.WithDescription("HTTP handler panics caught by Recovery"),
+)
+
 // Tracing returns middleware that creates OpenTelemetry server spans for each
 // HTTP request. It extracts incoming trace context from request headers using
 // the globally configured propagator and records HTTP semantic convention
 // attributes (method, path, status code). Responses with 5xx status codes
 // cause the span status to be set to Error. It also records the
 // http.server.request.duration metric as an OTel histogram.
+//
+// When the request was routed by a ServeMux (see CaptureRoute), the span is
+// named after the matched route, e.g. "GET /users/{id}", and the span and
+// metric carry it as http.route, so metrics aggregate per route rather than
+// per raw path.
 func Tracing() func(http.Handler) http.Handler {
 	chassis.AssertVersionChecked()
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			registry.AssertActive()
+			r, st := withRequestState(r)
 			propagator := otelapi.GetTextMapPropagator()
 			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
 
@@ -48,25 +61,41 @@ func Tracing() func(http.Handler) http.Handler {
 					semconv.URLPath(r.URL.Path),
 				),
 			)
-			defer span.End()
+			completed := false
+			defer func() {
+				if !completed {
+					span.SetStatus(codes.Error, "panic")
+					if st.recovering {
+						// Recovery records the panic on the span and ends it.
+						st.panicSpan = span
+						return
+					}
+				}
+				span.End()
+			}()
 
 			start := time.Now()
 			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 			next.ServeHTTP(rw, r.WithContext(ctx))
+			completed = true
 			duration := time.Since(start).Seconds()
 
+			attrs := []attribute.KeyValue{
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.HTTPResponseStatusCode(rw.statusCode),
+			}
+			if route := httpRoute(matchedPattern(r, st)); route != "" {
+				span.SetName(r.Method + " " + route)
+				attrs = append(attrs, semconv.HTTPRoute(route))
+				span.SetAttributes(semconv.HTTPRoute(route))
+			}
 			span.SetAttributes(semconv.HTTPResponseStatusCode(rw.statusCode))
 			if rw.statusCode >= 500 {
 				span.SetStatus(codes.Error, http.StatusText(rw.statusCode))
 			}
 
 			if h := getHTTPDurationHistogram(); h != nil {
-				h.Record(ctx, duration,
-					metric.WithAttributes(
-						semconv.HTTPRequestMethodKey.String(r.Method),
-						semconv.HTTPResponseStatusCode(rw.statusCode),
-					),
-				)
+				h.Record(ctx, duration, metric.WithAttributes(attrs...))
 			}
 		})
 	}
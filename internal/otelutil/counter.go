@@ -0,0 +1,31 @@
+package otelutil
+
+import (
+	"sync"
+
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// LazyCounter returns a function that lazily initializes and returns an
+// Int64Counter from the global MeterProvider, mirroring LazyHistogram.
+func LazyCounter(meterName, counterName string, opts ...metric.Int64CounterOption) func() metric.Int64Counter {
+	var (
+		once    sync.Once
+		counter metric.Int64Counter
+	)
+	return func() metric.Int64Counter {
+		once.Do(func() {
+			meter := otelapi.GetMeterProvider().Meter(meterName)
+			var err error
+			counter, err = meter.Int64Counter(counterName, opts...)
+			if err != nil {
+				otelapi.Handle(err)
+				// Return a safe noop counter so callers never get nil.
+				counter, _ = noop.NewMeterProvider().Meter("noop").Int64Counter("noop")
+			}
+		})
+		return counter
+	}
+}
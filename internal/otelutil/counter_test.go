@@ -0,0 +1,46 @@
+package otelutil
+
+import (
+	"context"
+	"testing"
+
+	otelapi "go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestLazyCounterRecordsValues(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	prev := otelapi.GetMeterProvider()
+	otelapi.SetMeterProvider(mp)
+	defer func() {
+		otelapi.SetMeterProvider(prev)
+		mp.Shutdown(context.Background())
+	}()
+
+	getter := LazyCounter("test-meter", "my_total")
+	if getter() != getter() {
+		t.Fatal("expected same counter instance on second call")
+	}
+	getter().Add(context.Background(), 2)
+	getter().Add(context.Background(), 3)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "my_total" {
+				continue
+			}
+			sum := m.Data.(metricdata.Sum[int64])
+			if got := sum.DataPoints[0].Value; got != 5 {
+				t.Fatalf("counter value = %d, want 5", got)
+			}
+			return
+		}
+	}
+	t.Fatal("expected to find my_total metric")
+}
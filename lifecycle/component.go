@@ -0,0 +1,100 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Spec is a Component together with the metadata Run uses to supervise it.
+// Build one with Named and pass it to Run alongside plain Components.
+type Spec struct {
+	name  string
+	named bool // name was set explicitly rather than defaulted by Run
+	phase int
+	run   Component
+
+	restart     *Policy // nil means failures are not restarted
+	bestEffort  bool
+	waitStarted bool
+}
+
+// Named returns a Spec that runs c under name. Run logs start, stop,
+// duration and error for every component; naming one makes those logs
+// readable and prefixes its error with the name, so a failure identifies
+// the component instead of an anonymous closure. c may be a Component, a
+// bare func(ctx context.Context) error, or a *Spec.
+func Named(name string, c any) *Spec {
+	s := toSpec(c)
+	s.name = name
+	s.named = true
+	return s
+}
+
+// Phase returns a Spec that runs c in startup phase n. Run launches phases
+// in ascending order: a phase starts once every WaitStarted component of the
+// phase before it has called Started, and other components count as started
+// as soon as they are launched. If a critical component fails or ctx is
+// cancelled while a phase starts, later phases are never launched. On
+// shutdown phases are cancelled in reverse: every component in a phase has
+// returned before the next lower phase is cancelled, so servers drain before
+// the databases they use close. Components without a phase run in phase 0.
+// c may be a Component, a bare func(ctx context.Context) error, or a *Spec.
+func Phase(n int, c any) *Spec {
+	s := toSpec(c)
+	s.phase = n
+	return s
+}
+
+// BestEffort returns a Spec whose failure is logged but neither cancels the
+// other components nor is returned by Run — for optional work such as a
+// background cache refresher. Combine with Restart to keep retrying it.
+// Critical (non-best-effort) components still trigger a full shutdown when
+// they fail, and once every critical component has returned, best-effort
+// components are cancelled too. c may be a Component, a bare
+// func(ctx context.Context) error, or a *Spec.
+func BestEffort(c any) *Spec {
+	s := toSpec(c)
+	s.bestEffort = true
+	return s
+}
+
+// toSpec returns a copy of c as a Spec, panicking on unsupported types in the
+// same way Run does.
+func toSpec(c any) *Spec {
+	switch v := c.(type) {
+	case *Spec:
+		cp := *v
+		return &cp
+	case Component:
+		return &Spec{run: v}
+	case func(ctx context.Context) error:
+		return &Spec{run: v}
+	default:
+		panic(fmt.Sprintf("lifecycle: unsupported component type %T", c))
+	}
+}
+
+// runSpec runs s, logging its start and stop and emitting the matching
+// events. Errors from named components are prefixed with the component name.
+func runSpec(ctx context.Context, o *options, s *Spec) error {
+	start := time.Now()
+	o.logger.InfoContext(ctx, "lifecycle: component started", "component", s.name)
+	o.emit(Event{Type: ComponentStarted, Component: s.name})
+
+	err := runWithRestarts(ctx, o, s)
+	elapsed := time.Since(start)
+	if err == nil || (ctx.Err() != nil && errors.Is(err, ctx.Err())) {
+		o.logger.InfoContext(ctx, "lifecycle: component stopped", "component", s.name, "duration", elapsed)
+		o.emit(Event{Type: ComponentStopped, Component: s.name, Err: err})
+		return err
+	}
+
+	o.logger.ErrorContext(ctx, "lifecycle: component failed", "component", s.name, "duration", elapsed, "error", err)
+	o.emit(Event{Type: ComponentStopped, Component: s.name, Err: err})
+	if s.named {
+		return fmt.Errorf("lifecycle: component %q: %w", s.name, err)
+	}
+	return err
+}
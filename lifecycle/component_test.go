@@ -0,0 +1,117 @@
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent log writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// entries parses the buffered JSON log lines.
+func (b *syncBuffer) entries(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("parse log line %q: %v", line, err)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func newTestLogger(buf *syncBuffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, nil))
+}
+
+func TestNamedComponentErrorIdentifiesComponent(t *testing.T) {
+	resetKafkaTest(t)
+	var buf syncBuffer
+	boom := errors.New("bind: address already in use")
+
+	err := Run(context.Background(),
+		WithLogger(newTestLogger(&buf)),
+		Named("http-server", func(ctx context.Context) error { return boom }),
+	)
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected wrapped %v, got %v", boom, err)
+	}
+	if !strings.Contains(err.Error(), `component "http-server"`) {
+		t.Errorf("error %q should name the component", err)
+	}
+
+	var failed map[string]any
+	for _, e := range buf.entries(t) {
+		if e["msg"] == "lifecycle: component failed" {
+			failed = e
+		}
+	}
+	if failed == nil {
+		t.Fatal("expected a component failed log entry")
+	}
+	if failed["component"] != "http-server" || failed["error"] != boom.Error() || failed["duration"] == nil {
+		t.Errorf("failed entry = %v", failed)
+	}
+}
+
+func TestComponentStartStopLogged(t *testing.T) {
+	resetKafkaTest(t)
+	var buf syncBuffer
+
+	err := Run(context.Background(),
+		WithLogger(newTestLogger(&buf)),
+		Named("worker", func(ctx context.Context) error { return nil }),
+		func(ctx context.Context) error { return nil },
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, e := range buf.entries(t) {
+		if c, ok := e["component"].(string); ok {
+			seen[e["msg"].(string)+" "+c] = true
+		}
+	}
+	for _, want := range []string{
+		"lifecycle: component started worker",
+		"lifecycle: component stopped worker",
+		"lifecycle: component started component-1",
+		"lifecycle: component stopped component-1",
+	} {
+		if !seen[want] {
+			t.Errorf("missing log %q (got %v)", want, seen)
+		}
+	}
+}
+
+func TestNamedPanicsOnUnsupportedType(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected panic on unsupported component type")
+		}
+	}()
+	Named("bad", 42)
+}
@@ -0,0 +1,41 @@
+package lifecycle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AggregateError is returned by Run with WithAllErrors. It records every
+// critical component failure, not just the first, so postmortems see the
+// full picture. errors.Is and errors.As match against any of them.
+type AggregateError struct {
+	// Trigger is the failure that initiated shutdown, or nil if shutdown
+	// was initiated by a signal or the caller's context.
+	Trigger error
+	// Errors holds every component failure in the order they occurred,
+	// including Trigger. Cancellation errors returned while draining are
+	// not failures and are omitted.
+	Errors []error
+}
+
+func (e *AggregateError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("lifecycle: %d components failed:\n%s", len(e.Errors), strings.Join(msgs, "\n"))
+}
+
+// Unwrap exposes the individual failures to errors.Is and errors.As.
+func (e *AggregateError) Unwrap() []error { return e.Errors }
+
+// WithAllErrors makes Run wait for every component and return an
+// *AggregateError holding all failures, instead of only the first one.
+func WithAllErrors() Option {
+	return func(o *options) {
+		o.allErrors = true
+	}
+}
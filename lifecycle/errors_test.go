@@ -0,0 +1,78 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithAllErrorsAggregatesFailures(t *testing.T) {
+	resetKafkaTest(t)
+	trigger := errors.New("db connection lost")
+	drainErr := errors.New("flush failed during drain")
+
+	err := Run(context.Background(),
+		WithAllErrors(),
+		Named("db", func(ctx context.Context) error { return trigger }),
+		Named("writer", func(ctx context.Context) error {
+			<-ctx.Done()
+			return drainErr
+		}),
+		Named("server", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err() // normal stop, not a failure
+		}),
+	)
+
+	var agg *AggregateError
+	if !errors.As(err, &agg) {
+		t.Fatalf("expected *AggregateError, got %T: %v", err, err)
+	}
+	if !errors.Is(agg.Trigger, trigger) {
+		t.Errorf("Trigger = %v, want %v", agg.Trigger, trigger)
+	}
+	if len(agg.Errors) != 2 {
+		t.Fatalf("Errors = %v, want trigger and drain failure", agg.Errors)
+	}
+	if !errors.Is(err, trigger) || !errors.Is(err, drainErr) {
+		t.Errorf("errors.Is should match every failure: %v", err)
+	}
+	if !strings.Contains(err.Error(), "2 components failed") {
+		t.Errorf("message = %q", err.Error())
+	}
+}
+
+func TestWithAllErrorsSignalTriggerIsNil(t *testing.T) {
+	resetKafkaTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	failure := errors.New("close failed")
+
+	err := Run(ctx, WithAllErrors(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return failure
+	})
+	var agg *AggregateError
+	if !errors.As(err, &agg) {
+		t.Fatalf("expected *AggregateError, got %v", err)
+	}
+	if agg.Trigger != nil {
+		t.Errorf("Trigger = %v, want nil for context-initiated shutdown", agg.Trigger)
+	}
+	if err.Error() != failure.Error() {
+		t.Errorf("single failure message = %q, want %q", err.Error(), failure.Error())
+	}
+}
+
+func TestWithoutAllErrorsReturnsFirst(t *testing.T) {
+	resetKafkaTest(t)
+	first := errors.New("first")
+	err := Run(context.Background(),
+		func(ctx context.Context) error { return first },
+		func(ctx context.Context) error { <-ctx.Done(); return errors.New("second") },
+	)
+	if err != first {
+		t.Fatalf("expected the first error unchanged, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package lifecycle
+
+import "time"
+
+// EventType identifies a lifecycle transition.
+type EventType int
+
+const (
+	// ComponentStarted is emitted when a component begins running.
+	ComponentStarted EventType = iota + 1
+	// ComponentStopped is emitted when a component returns; Err is set if
+	// it failed.
+	ComponentStopped
+	// ComponentRestarting is emitted when a Restart policy is about to
+	// re-run a failed component; Err is the failure.
+	ComponentRestarting
+	// ShutdownInitiated is emitted once when Run starts stopping
+	// components; Reason says why.
+	ShutdownInitiated
+	// ShutdownComplete is emitted once when Run is about to return; Err is
+	// Run's result.
+	ShutdownComplete
+)
+
+func (t EventType) String() string {
+	switch t {
+	case ComponentStarted:
+		return "component_started"
+	case ComponentStopped:
+		return "component_stopped"
+	case ComponentRestarting:
+		return "component_restarting"
+	case ShutdownInitiated:
+		return "shutdown_initiated"
+	case ShutdownComplete:
+		return "shutdown_complete"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a lifecycle transition reported to a WithEvents callback.
+type Event struct {
+	Type      EventType
+	Time      time.Time
+	Component string // component name for component events
+	Reason    string // why shutdown began, for ShutdownInitiated
+	Err       error
+}
+
+// Shutdown reasons reported in ShutdownInitiated events. When shutdown is
+// triggered by the context (a signal or the caller), Reason is the
+// context's cancellation cause instead.
+const (
+	ReasonComponentFailed   = "component failed"
+	ReasonComponentsStopped = "components stopped"
+)
+
+// WithEvents registers fn to receive every lifecycle Event, so services can
+// turn their own transitions into metrics, logs or spans. fn is called
+// synchronously from the goroutine making the transition, possibly
+// concurrently; it must be fast and safe for concurrent use.
+func WithEvents(fn func(Event)) Option {
+	return func(o *options) {
+		o.onEvent = fn
+	}
+}
+
+// emit stamps e and passes it to the WithEvents callback, if any.
+func (o *options) emit(e Event) {
+	if o.onEvent == nil {
+		return
+	}
+	e.Time = time.Now()
+	o.onEvent(e)
+}
@@ -0,0 +1,126 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// eventRecorder collects events from a WithEvents callback.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *eventRecorder) record(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *eventRecorder) ofType(t EventType) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Event
+	for _, e := range r.events {
+		if e.Type == t {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func TestEventsForComponentFailure(t *testing.T) {
+	resetKafkaTest(t)
+	var rec eventRecorder
+	boom := errors.New("boom")
+
+	err := Run(context.Background(),
+		WithEvents(rec.record),
+		Named("worker", func(ctx context.Context) error { return boom }),
+		Named("server", func(ctx context.Context) error { <-ctx.Done(); return nil }),
+	)
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected %v, got %v", boom, err)
+	}
+
+	if n := len(rec.ofType(ComponentStarted)); n != 2 {
+		t.Errorf("ComponentStarted events = %d, want 2", n)
+	}
+	var workerStopped bool
+	for _, e := range rec.ofType(ComponentStopped) {
+		if e.Component == "worker" && errors.Is(e.Err, boom) {
+			workerStopped = true
+		}
+	}
+	if !workerStopped {
+		t.Error("expected ComponentStopped for worker carrying its error")
+	}
+
+	initiated := rec.ofType(ShutdownInitiated)
+	if len(initiated) != 1 {
+		t.Fatalf("ShutdownInitiated events = %d, want 1", len(initiated))
+	}
+	if initiated[0].Reason != ReasonComponentFailed || initiated[0].Component != "worker" {
+		t.Errorf("ShutdownInitiated = %+v, want component failed by worker", initiated[0])
+	}
+
+	complete := rec.ofType(ShutdownComplete)
+	if len(complete) != 1 || !errors.Is(complete[0].Err, boom) {
+		t.Errorf("ShutdownComplete = %+v, want one event carrying Run's error", complete)
+	}
+	if complete[0].Time.IsZero() {
+		t.Error("events should be timestamped")
+	}
+}
+
+func TestEventsShutdownReasons(t *testing.T) {
+	resetKafkaTest(t)
+
+	var rec eventRecorder
+	if err := Run(context.Background(), WithEvents(rec.record), func(context.Context) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.ofType(ShutdownInitiated); len(got) != 1 || got[0].Reason != ReasonComponentsStopped {
+		t.Errorf("clean exit: ShutdownInitiated = %+v", got)
+	}
+
+	cause := errors.New("deploy rollout")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(cause)
+	var rec2 eventRecorder
+	Run(ctx, WithEvents(rec2.record), func(ctx context.Context) error { <-ctx.Done(); return nil })
+	if got := rec2.ofType(ShutdownInitiated); len(got) != 1 || got[0].Reason != cause.Error() {
+		t.Errorf("cancelled context: ShutdownInitiated = %+v, want reason %q", got, cause)
+	}
+}
+
+func TestEventsRestarting(t *testing.T) {
+	resetKafkaTest(t)
+	var rec eventRecorder
+	calls := 0
+	flaky := func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return errors.New("transient")
+		}
+		return nil
+	}
+	if err := Run(context.Background(), WithEvents(rec.record), Restart(flaky, Policy{Backoff: time.Millisecond})); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(rec.ofType(ComponentRestarting)); n != 1 {
+		t.Errorf("ComponentRestarting events = %d, want 1", n)
+	}
+}
+
+func TestEventTypeString(t *testing.T) {
+	if got := ShutdownInitiated.String(); got != "shutdown_initiated" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := EventType(0).String(); got != "unknown" {
+		t.Errorf("zero String() = %q", got)
+	}
+}
@@ -0,0 +1,55 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultHookTimeout is the budget shared by OnShutdown hooks when
+// WithHookTimeout is not set.
+const DefaultHookTimeout = 10 * time.Second
+
+// OnShutdown registers a hook that Run calls after every component has
+// stopped — the place to flush OTel exporters, close database pools or
+// write a final audit log. Hooks run in reverse registration order, like
+// defers, and share a single timeout budget (see WithHookTimeout) separate
+// from the component shutdown timeout. Hook errors are logged and joined
+// into Run's returned error.
+func OnShutdown(fn func(ctx context.Context) error) Option {
+	return func(o *options) {
+		o.shutdownHooks = append(o.shutdownHooks, fn)
+	}
+}
+
+// WithHookTimeout sets the total time OnShutdown hooks may take. Defaults to
+// DefaultHookTimeout.
+func WithHookTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.hookTimeout = d
+	}
+}
+
+// runShutdownHooks runs o's hooks in reverse order and joins their errors.
+// Hooks run even if an earlier one failed or the budget is spent; each sees
+// the shared context and can give up when it is done.
+func runShutdownHooks(ctx context.Context, o *options) error {
+	if len(o.shutdownHooks) == 0 {
+		return nil
+	}
+	timeout := o.hookTimeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	hookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(o.shutdownHooks) - 1; i >= 0; i-- {
+		if err := o.shutdownHooks[i](hookCtx); err != nil {
+			o.logger.ErrorContext(hookCtx, "lifecycle: shutdown hook failed", "error", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOnShutdownHooksRunAfterComponentsInReverse(t *testing.T) {
+	resetKafkaTest(t)
+	var rec orderRecorder
+
+	comp := func(ctx context.Context) error {
+		rec.add("component")
+		return nil
+	}
+	hook := func(name string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Errorf("hook %s: expected a deadline on the hook context", name)
+			}
+			rec.add(name)
+			return nil
+		}
+	}
+
+	err := Run(context.Background(), OnShutdown(hook("close-db")), OnShutdown(hook("flush-otel")), comp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"component", "flush-otel", "close-db"}
+	if got := rec.get(); !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestOnShutdownHookErrorsJoined(t *testing.T) {
+	resetKafkaTest(t)
+	compErr := errors.New("component failed")
+	hookErr := errors.New("flush failed")
+	var laterRan bool
+
+	err := Run(context.Background(),
+		OnShutdown(func(context.Context) error { laterRan = true; return nil }),
+		OnShutdown(func(context.Context) error { return hookErr }),
+		func(context.Context) error { return compErr },
+	)
+	if !errors.Is(err, compErr) || !errors.Is(err, hookErr) {
+		t.Fatalf("expected both component and hook errors, got %v", err)
+	}
+	if !laterRan {
+		t.Error("a failing hook should not prevent the remaining hooks from running")
+	}
+}
+
+func TestHookTimeoutBudget(t *testing.T) {
+	o := &options{hookTimeout: 20 * time.Millisecond, logger: newTestLogger(&syncBuffer{})}
+	o.shutdownHooks = append(o.shutdownHooks, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err := runShutdownHooks(context.Background(), o); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
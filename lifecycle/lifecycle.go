@@ -5,7 +5,9 @@ package lifecycle
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -39,6 +41,17 @@ type Option func(*options)
 type options struct {
 	kafkaCfg    *kafkakit.Config
 	serviceName string // resolved lazily if not set
+	logger      *slog.Logger
+
+	shutdownTimeout time.Duration
+	shutdownHooks   []func(ctx context.Context) error
+	hookTimeout     time.Duration
+	readiness       Drainer
+	drainDelay      time.Duration
+	reloadHooks     []func(ctx context.Context) error
+	onEvent         func(Event)
+	allErrors       bool
+	startTimeout    time.Duration
 }
 
 // WithKafkaConfig enables kafkakit integration. When the config has
@@ -64,6 +77,26 @@ func WithServiceName(name string) Option {
 	}
 }
 
+// WithLogger sets the logger Run uses for per-component start/stop logs.
+// Defaults to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithShutdownTimeout bounds how long Run waits for components to return
+// once shutdown begins, including any WithDrainDelay. If any are still
+// running after d, Run stops waiting and returns an error wrapping
+// ErrShutdownTimeout that names them, so a stuck component cannot hang the
+// process past its termination grace period. Zero (the default) waits
+// indefinitely.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.shutdownTimeout = d
+	}
+}
+
 // RunComponents is the type-safe variant of Run that accepts only Component
 // values and optional Option values. Prefer this over Run when all components
 // are known at compile time.
@@ -76,12 +109,16 @@ func RunComponents(ctx context.Context, components ...Component) error {
 }
 
 // Run orchestrates one or more components. It accepts Component values
-// (or bare func(ctx context.Context) error) and Option values. It creates a
-// context cancelled on SIGTERM or SIGINT, launches every component as a
-// goroutine in an errgroup, and waits for all of them to finish. If any
-// component returns an error the shared context is cancelled, signalling the
-// remaining components to shut down. The first non-nil error (if any) is
-// returned.
+// (or bare func(ctx context.Context) error), *Spec values built with Named
+// or Phase, and Option values. It creates a context cancelled on SIGTERM or
+// SIGINT, launches every component as a goroutine, and waits for all of them
+// to finish. If any component returns an error, or the context is cancelled,
+// the components are signalled to shut down, phase by phase in reverse
+// startup order. The first non-nil error (if any) is returned, or every
+// failure with WithAllErrors. Each component's start, stop, duration and
+// error are logged.
+//
+// OnShutdown hooks run once every component has returned.
 //
 // When WithKafkaConfig is provided and the config is enabled, Run automatically
 // starts heartbeatkit and announcekit, and shuts them down on exit.
@@ -89,20 +126,30 @@ func Run(ctx context.Context, args ...any) error {
 	chassis.AssertVersionChecked()
 
 	var o options
-	var components []Component
+	var components []*Spec
 
 	for _, a := range args {
 		switch v := a.(type) {
 		case Component:
-			components = append(components, v)
+			components = append(components, &Spec{run: v})
 		case func(ctx context.Context) error:
-			components = append(components, v)
+			components = append(components, &Spec{run: v})
+		case *Spec:
+			components = append(components, toSpec(v))
 		case Option:
 			v(&o)
 		default:
 			panic(fmt.Sprintf("lifecycle: Run received unsupported argument type %T", a))
 		}
 	}
+	for i, c := range components {
+		if c.name == "" {
+			c.name = fmt.Sprintf("component-%d", i)
+		}
+	}
+	if o.logger == nil {
+		o.logger = slog.Default()
+	}
 
 	signalCtx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
 	defer stop()
@@ -160,21 +207,23 @@ func Run(ctx context.Context, args ...any) error {
 
 	g.Go(func() error { return registry.RunHeartbeat(infraCtx) })
 	g.Go(func() error { return registry.RunCommandPoll(infraCtx) })
+	g.Go(func() error {
+		runReloads(infraCtx, &o)
+		return nil
+	})
 
-	// Run user components in a nested errgroup so we can detect when they
-	// all finish and stop infrastructure goroutines.
-	userG, userCtx := errgroup.WithContext(gCtx)
-	for _, c := range components {
-		userG.Go(func() error { return c(userCtx) })
-	}
-
+	// Run user components in their own goroutine so we can detect when
+	// they all finish and stop infrastructure goroutines.
 	g.Go(func() error {
-		err := userG.Wait()
+		err := runComponents(gCtx, &o, components)
 		infraCancel()
 		return err
 	})
 
 	err := g.Wait()
+	if hookErr := runShutdownHooks(ctx, &o); hookErr != nil {
+		err = errors.Join(err, hookErr)
+	}
 
 	// Kafkakit shutdown sequence.
 	if pub != nil {
@@ -194,6 +243,7 @@ func Run(ctx context.Context, args ...any) error {
 	}
 	registry.Shutdown(reason)
 	registryInitialized = false
+	o.emit(Event{Type: ShutdownComplete, Err: err})
 
 	if registry.RestartRequested() {
 		exePath, exeErr := os.Executable()
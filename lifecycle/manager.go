@@ -0,0 +1,244 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+)
+
+// ErrManagerStopped is returned by Manager.Add once shutdown has begun.
+var ErrManagerStopped = errors.New("lifecycle: manager is shutting down")
+
+// Manager supervises components that are added and retired at runtime, such
+// as per-tenant consumers, with the same signal handling, logging, events
+// and draining as Run. Create one with NewManager, Add and Stop components
+// as needed, and call Wait to block until shutdown.
+//
+// Unlike Run, a Manager does not initialise the registry or kafkakit. To
+// have both, create the Manager inside a Run component from the component's
+// context and return its Wait.
+type Manager struct {
+	o      options
+	ctx    context.Context // cancelled on SIGTERM, SIGINT or the parent ctx
+	stop   context.CancelFunc
+	failed chan struct{} // closed on the first critical failure
+
+	mu          sync.Mutex
+	running     map[string]*managed
+	closing     bool
+	firstErr    error
+	firstFailed string
+	failures    []error
+
+	waitOnce sync.Once
+	waitErr  error
+}
+
+// managed is one running component.
+type managed struct {
+	spec    *Spec
+	cancel  context.CancelFunc
+	done    chan struct{}
+	err     error
+	retired bool // removed with Stop; its error is not a failure
+}
+
+// NewManager returns a Manager that shuts down when ctx is cancelled, on
+// SIGTERM or SIGINT, or when a critical component fails. It accepts the same
+// Options as Run; WithKafkaConfig and WithServiceName are ignored.
+func NewManager(ctx context.Context, opts ...Option) *Manager {
+	chassis.AssertVersionChecked()
+
+	m := &Manager{
+		failed:  make(chan struct{}),
+		running: make(map[string]*managed),
+	}
+	for _, opt := range opts {
+		opt(&m.o)
+	}
+	if m.o.logger == nil {
+		m.o.logger = slog.Default()
+	}
+	m.ctx, m.stop = signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	go runReloads(m.ctx, &m.o)
+	return m
+}
+
+// Add starts c under name. c may be a Component, a bare
+// func(ctx context.Context) error, or a *Spec; its phase, restart policy and
+// best-effort flag are honoured. Add returns an error if a component with
+// the same name is running or shutdown has begun.
+func (m *Manager) Add(name string, c any) error {
+	s := Named(name, c)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closing {
+		return ErrManagerStopped
+	}
+	if _, ok := m.running[name]; ok {
+		return fmt.Errorf("lifecycle: component %q is already running", name)
+	}
+
+	// Component contexts are detached from m.ctx so that shutdown can
+	// cancel them phase by phase.
+	cctx, cancel := context.WithCancel(context.WithoutCancel(m.ctx))
+	mc := &managed{spec: s, cancel: cancel, done: make(chan struct{})}
+	m.running[name] = mc
+	go func() {
+		err := runSpec(cctx, &m.o, s)
+		cancel()
+		m.finish(mc, err)
+	}()
+	return nil
+}
+
+// finish records mc's result and removes it from the running set.
+func (m *Manager) finish(mc *managed, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mc.err = err
+	if m.running[mc.spec.name] == mc {
+		delete(m.running, mc.spec.name)
+	}
+	close(mc.done)
+
+	if err == nil || mc.retired || mc.spec.bestEffort {
+		return
+	}
+	if m.closing && errors.Is(err, context.Canceled) {
+		return
+	}
+	m.failures = append(m.failures, err)
+	if m.firstErr == nil {
+		m.firstErr = err
+		m.firstFailed = mc.spec.name
+		if !m.closing {
+			close(m.failed)
+		}
+	}
+}
+
+// Stop cancels the named component and waits for it to return. Its error is
+// returned to the caller rather than shutting the Manager down; a return
+// caused by the cancellation itself is reported as nil.
+func (m *Manager) Stop(name string) error {
+	m.mu.Lock()
+	mc, ok := m.running[name]
+	if ok {
+		mc.retired = true
+		delete(m.running, name)
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("lifecycle: no running component %q", name)
+	}
+
+	mc.cancel()
+	<-mc.done
+	if errors.Is(mc.err, context.Canceled) {
+		return nil
+	}
+	return mc.err
+}
+
+// Wait blocks until the Manager's context is cancelled, a signal arrives, or
+// a critical component fails. It then drains every running component, phase
+// by phase in reverse order and bounded by WithShutdownTimeout, runs the
+// OnShutdown hooks, and returns the first critical failure, or an
+// *AggregateError with WithAllErrors. Wait may be called more than once; later
+// calls return the same result.
+func (m *Manager) Wait() error {
+	m.waitOnce.Do(func() {
+		m.waitErr = m.shutdown()
+	})
+	return m.waitErr
+}
+
+func (m *Manager) shutdown() error {
+	defer m.stop()
+
+	select {
+	case <-m.ctx.Done():
+	case <-m.failed:
+	}
+
+	m.mu.Lock()
+	m.closing = true
+	trigger := m.firstErr
+	specs := make([]*Spec, 0, len(m.running))
+	bySpec := make(map[*Spec]*managed, len(m.running))
+	for _, mc := range m.running {
+		specs = append(specs, mc.spec)
+		bySpec[mc.spec] = mc
+	}
+	m.mu.Unlock()
+	sort.Slice(specs, func(i, j int) bool { return specs[i].name < specs[j].name })
+
+	if trigger != nil {
+		m.o.emit(Event{Type: ShutdownInitiated, Reason: ReasonComponentFailed, Component: m.firstFailed, Err: trigger})
+	} else {
+		m.o.emit(Event{Type: ShutdownInitiated, Reason: context.Cause(m.ctx).Error()})
+	}
+	deadline, stopDeadline := shutdownDeadline(&m.o)
+	defer stopDeadline()
+	if m.o.readiness != nil {
+		m.o.readiness.Drain()
+	}
+	if len(specs) > 0 {
+		waitDrainDelay(&m.o, deadline)
+	}
+
+	var timeoutErr error
+	phases := groupByPhase(specs)
+drain:
+	for i := len(phases) - 1; i >= 0; i-- {
+		for _, s := range phases[i] {
+			bySpec[s].cancel()
+		}
+		for _, s := range phases[i] {
+			select {
+			case <-bySpec[s].done:
+			case <-deadline:
+				var stuck []string
+				for _, group := range phases[:i+1] {
+					for _, s := range group {
+						select {
+						case <-bySpec[s].done:
+						default:
+							stuck = append(stuck, s.name)
+						}
+					}
+				}
+				sort.Strings(stuck)
+				m.o.logger.Error("lifecycle: shutdown timed out", "timeout", m.o.shutdownTimeout, "still_running", stuck)
+				timeoutErr = fmt.Errorf("%w after %s; still running: %s",
+					ErrShutdownTimeout, m.o.shutdownTimeout, strings.Join(stuck, ", "))
+				break drain
+			}
+		}
+	}
+
+	m.mu.Lock()
+	err := m.firstErr
+	if m.o.allErrors && len(m.failures) > 0 {
+		err = &AggregateError{Trigger: trigger, Errors: m.failures}
+	}
+	m.mu.Unlock()
+	if timeoutErr != nil {
+		err = errors.Join(err, timeoutErr)
+	}
+	if hookErr := runShutdownHooks(m.ctx, &m.o); hookErr != nil {
+		err = errors.Join(err, hookErr)
+	}
+	m.o.emit(Event{Type: ShutdownComplete, Err: err})
+	return err
+}
@@ -0,0 +1,140 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stopped returns the names of the components rec saw stop, in order.
+func stopped(rec *orderRecorder) []string {
+	var names []string
+	for _, ev := range rec.get() {
+		if name, ok := strings.CutPrefix(ev, "stop "); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func TestManagerAddStopWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rec := &orderRecorder{}
+	m := NewManager(ctx)
+
+	for _, name := range []string{"tenant-a", "tenant-b"} {
+		if err := m.Add(name, rec.blocking(name)); err != nil {
+			t.Fatalf("Add(%s): %v", name, err)
+		}
+	}
+	if err := m.Add("tenant-a", rec.blocking("dup")); err == nil {
+		t.Fatal("expected error adding a duplicate name")
+	}
+
+	if err := m.Stop("tenant-a"); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if got := stopped(rec); !slices.Equal(got, []string{"tenant-a"}) {
+		t.Fatalf("after Stop, stopped = %v", got)
+	}
+	if err := m.Stop("tenant-a"); err == nil {
+		t.Fatal("expected error stopping a component that is not running")
+	}
+	// A retired name can be reused.
+	if err := m.Add("tenant-a", rec.blocking("tenant-a2")); err != nil {
+		t.Fatalf("re-Add: %v", err)
+	}
+
+	cancel()
+	if err := m.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	got := stopped(rec)
+	slices.Sort(got[1:])
+	if !slices.Equal(got, []string{"tenant-a", "tenant-a2", "tenant-b"}) {
+		t.Fatalf("stopped = %v", got)
+	}
+	if err := m.Add("late", rec.blocking("late")); !errors.Is(err, ErrManagerStopped) {
+		t.Fatalf("Add after Wait = %v, want ErrManagerStopped", err)
+	}
+}
+
+func TestManagerStopReturnsComponentError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewManager(ctx)
+	flushErr := errors.New("flush failed")
+	m.Add("consumer", func(ctx context.Context) error {
+		<-ctx.Done()
+		return flushErr
+	})
+
+	if err := m.Stop("consumer"); !errors.Is(err, flushErr) {
+		t.Fatalf("Stop = %v, want %v", err, flushErr)
+	}
+	// A retired component's error does not fail the Manager.
+	cancel()
+	if err := m.Wait(); err != nil {
+		t.Fatalf("Wait = %v, want nil", err)
+	}
+}
+
+func TestManagerCriticalFailureDrainsAll(t *testing.T) {
+	rec := &orderRecorder{}
+	events := &eventRecorder{}
+	boom := errors.New("boom")
+	m := NewManager(context.Background(), WithEvents(events.record))
+
+	m.Add("server", Phase(2, rec.blocking("server")))
+	m.Add("db", Phase(1, rec.blocking("db")))
+	m.Add("worker", func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return boom
+	})
+
+	err := m.Wait()
+	if !errors.Is(err, boom) {
+		t.Fatalf("Wait = %v, want %v", err, boom)
+	}
+	if got := stopped(rec); !slices.Equal(got, []string{"server", "db"}) {
+		t.Errorf("drain order = %v, want server before db", got)
+	}
+	if err2 := m.Wait(); err2 != err {
+		t.Errorf("second Wait = %v, want same result", err2)
+	}
+	initiated := events.ofType(ShutdownInitiated)
+	if len(initiated) != 1 || initiated[0].Component != "worker" || initiated[0].Reason != ReasonComponentFailed {
+		t.Errorf("ShutdownInitiated = %+v", initiated)
+	}
+}
+
+func TestManagerBestEffortFailureIgnored(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	m := NewManager(ctx)
+	m.Add("refresher", BestEffort(func(ctx context.Context) error {
+		return errors.New("cache unavailable")
+	}))
+	if err := m.Wait(); err != nil {
+		t.Fatalf("Wait = %v, want nil", err)
+	}
+}
+
+func TestManagerShutdownTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewManager(ctx, WithShutdownTimeout(30*time.Millisecond))
+	release := make(chan struct{})
+	defer close(release)
+	m.Add("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	cancel()
+	if err := m.Wait(); !errors.Is(err, ErrShutdownTimeout) {
+		t.Fatalf("Wait = %v, want ErrShutdownTimeout", err)
+	}
+}
@@ -0,0 +1,129 @@
+package lifecycle
+
+import (
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Drainer is a readiness signal that Run flips to not-ready when shutdown
+// begins. *Gate and *health.ReadinessToggle implement it.
+type Drainer interface {
+	Drain()
+}
+
+// Gate tracks whether the service should receive traffic. Components mark
+// themselves ready or not ready; the gate is ready when every marked or
+// declared component is ready and shutdown has not begun. Pass it to Run
+// with WithReadiness so it flips to not-ready as soon as shutdown starts,
+// and serve it with health.ReadinessHandler so load balancers stop sending
+// traffic while components drain.
+type Gate struct {
+	mu       sync.Mutex
+	ready    map[string]bool
+	draining bool
+}
+
+// Readiness returns a Gate that is not ready until each of the named
+// components calls SetReady(name, true). With no names the gate starts
+// ready and only components that mark themselves not ready hold it back.
+func Readiness(names ...string) *Gate {
+	g := &Gate{ready: make(map[string]bool, len(names))}
+	for _, n := range names {
+		g.ready[n] = false
+	}
+	return g
+}
+
+// SetReady records whether the named component can serve traffic.
+func (g *Gate) SetReady(name string, ready bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ready[name] = ready
+}
+
+// Ready reports whether shutdown has not begun and every component tracked
+// by the gate is ready.
+func (g *Gate) Ready() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.draining {
+		return false
+	}
+	for _, ok := range g.ready {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// NotReady returns the sorted names of components that are not ready.
+func (g *Gate) NotReady() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var names []string
+	for _, n := range slices.Sorted(maps.Keys(g.ready)) {
+		if !g.ready[n] {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// Drain permanently marks the gate not ready. Run calls it when shutdown
+// begins if the gate was passed with WithReadiness.
+func (g *Gate) Drain() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.draining = true
+}
+
+// WithReadiness makes Run flip gate to not-ready as soon as shutdown begins,
+// before the drain delay and before any component is cancelled. gate is
+// usually a *Gate or a *health.ReadinessToggle.
+func WithReadiness(gate Drainer) Option {
+	return func(o *options) {
+		o.readiness = gate
+	}
+}
+
+// WithDrainDelay keeps every component running for d after shutdown begins
+// and the readiness gate has flipped to not-ready, before any is cancelled.
+// This is the Kubernetes pre-stop drain: endpoints and load balancers take
+// a few seconds to notice a pod is not ready, and servers that stop
+// listening before then refuse requests still being routed to them. The
+// delay counts towards the termination grace period, so keep d well below
+// it. WithShutdownTimeout counts from the start of the delay and cuts it
+// short, as does a second SIGTERM or SIGINT. Zero (the default) cancels
+// immediately.
+func WithDrainDelay(d time.Duration) Option {
+	return func(o *options) {
+		o.drainDelay = d
+	}
+}
+
+// waitDrainDelay blocks for o.drainDelay, or until another SIGTERM or
+// SIGINT arrives or deadline is closed.
+func waitDrainDelay(o *options, deadline <-chan struct{}) {
+	if o.drainDelay <= 0 {
+		return
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(sigs)
+	o.logger.Info("lifecycle: waiting before stopping components", "drain_delay", o.drainDelay)
+	timer := time.NewTimer(o.drainDelay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case sig := <-sigs:
+		o.logger.Warn("lifecycle: drain delay cut short", "signal", sig.String())
+	case <-deadline:
+		o.logger.Warn("lifecycle: drain delay cut short by the shutdown timeout", "timeout", o.shutdownTimeout)
+	}
+}
@@ -0,0 +1,170 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ai8future/chassis-go/v11/health"
+)
+
+func TestGateDeclaredComponents(t *testing.T) {
+	g := Readiness("db", "cache")
+	if g.Ready() {
+		t.Fatal("gate should not be ready before declared components mark ready")
+	}
+	g.SetReady("db", true)
+	if got := g.NotReady(); !slices.Equal(got, []string{"cache"}) {
+		t.Errorf("NotReady = %v, want [cache]", got)
+	}
+	g.SetReady("cache", true)
+	if !g.Ready() {
+		t.Fatal("gate should be ready once every component is ready")
+	}
+	g.SetReady("cache", false)
+	if g.Ready() {
+		t.Fatal("gate should follow a component going not ready")
+	}
+}
+
+func TestGateWithoutDeclaredComponentsStartsReady(t *testing.T) {
+	if !Readiness().Ready() {
+		t.Fatal("empty gate should be ready")
+	}
+}
+
+func TestGateFlipsNotReadyWhenShutdownBegins(t *testing.T) {
+	resetKafkaTest(t)
+	gate := Readiness()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var readyDuringDrain bool
+	server := func(ctx context.Context) error {
+		gate.SetReady("server", true)
+		cancel()
+		<-ctx.Done()
+		readyDuringDrain = gate.Ready()
+		return nil
+	}
+
+	if err := Run(ctx, WithReadiness(gate), server); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if readyDuringDrain {
+		t.Error("gate should be not ready by the time components are cancelled")
+	}
+	gate.SetReady("server", true)
+	if gate.Ready() {
+		t.Error("gate should stay not ready after shutdown")
+	}
+}
+
+func TestDrainDelayKeepsComponentsRunningWhileNotReady(t *testing.T) {
+	resetKafkaTest(t)
+	gate := Readiness()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	const delay = 50 * time.Millisecond
+	var shutdownAt time.Time
+	var stoppedAfter time.Duration
+	var readyDuringDelay bool
+	server := func(ctx context.Context) error {
+		cancel()
+		time.Sleep(delay / 2)
+		readyDuringDelay = gate.Ready()
+		<-ctx.Done()
+		stoppedAfter = time.Since(shutdownAt)
+		return nil
+	}
+	events := WithEvents(func(e Event) {
+		if e.Type == ShutdownInitiated {
+			shutdownAt = e.Time
+		}
+	})
+
+	if err := Run(ctx, WithReadiness(gate), WithDrainDelay(delay), events, server); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if readyDuringDelay {
+		t.Error("gate should be not ready during the drain delay")
+	}
+	if stoppedAfter < delay {
+		t.Errorf("component cancelled %v after shutdown began, want at least %v", stoppedAfter, delay)
+	}
+}
+
+func TestWithReadinessDrainsToggle(t *testing.T) {
+	resetKafkaTest(t)
+	toggle := health.NewReadinessToggle(true)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var readyDuringDelay bool
+	server := func(ctx context.Context) error {
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+		readyDuringDelay = toggle.Ready()
+		<-ctx.Done()
+		return nil
+	}
+	if err := Run(ctx, WithReadiness(toggle), WithDrainDelay(30*time.Millisecond), server); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if readyDuringDelay || toggle.Ready() {
+		t.Error("toggle should be not ready once shutdown begins")
+	}
+}
+
+func TestShutdownTimeoutCutsDrainDelayShort(t *testing.T) {
+	resetKafkaTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+	stuck := func(ctx context.Context) error {
+		cancel()
+		<-ctx.Done()
+		<-release
+		return nil
+	}
+
+	start := time.Now()
+	err := Run(ctx, WithDrainDelay(10*time.Second), WithShutdownTimeout(50*time.Millisecond), stuck)
+	if !errors.Is(err, ErrShutdownTimeout) {
+		t.Fatalf("err = %v, want ErrShutdownTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("Run took %v; the shutdown timeout should cut the drain delay short", elapsed)
+	}
+}
+
+func TestSecondSignalCutsDrainDelayShort(t *testing.T) {
+	resetKafkaTest(t)
+	server := func(ctx context.Context) error {
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return nil
+	}
+	events := WithEvents(func(e Event) {
+		if e.Type == ShutdownInitiated {
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+			}()
+		}
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- Run(context.Background(), WithDrainDelay(10*time.Second), events, server) }()
+	select {
+	case err := <-done:
+		if err != nil && !errors.Is(err, context.Canceled) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("a second SIGINT should cut the drain delay short")
+	}
+}
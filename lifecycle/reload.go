@@ -0,0 +1,43 @@
+package lifecycle
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// OnReload registers a hook that Run calls each time the process receives
+// SIGHUP, without shutting down — for config hot-reload, log level changes
+// or re-reading TLS certificates. Hooks run in registration order; an error
+// is logged and the remaining hooks still run. SIGHUP is only intercepted
+// when at least one hook is registered.
+func OnReload(fn func(ctx context.Context) error) Option {
+	return func(o *options) {
+		o.reloadHooks = append(o.reloadHooks, fn)
+	}
+}
+
+// runReloads calls o's reload hooks on every SIGHUP until ctx is done.
+func runReloads(ctx context.Context, o *options) {
+	if len(o.reloadHooks) == 0 {
+		return
+	}
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			o.logger.InfoContext(ctx, "lifecycle: reloading on SIGHUP")
+			for _, fn := range o.reloadHooks {
+				if err := fn(ctx); err != nil {
+					o.logger.ErrorContext(ctx, "lifecycle: reload hook failed", "error", err)
+				}
+			}
+		}
+	}
+}
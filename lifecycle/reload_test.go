@@ -0,0 +1,46 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestOnReloadRunsHooksOnSIGHUP(t *testing.T) {
+	resetKafkaTest(t)
+	var calls atomic.Int32
+	reloaded := make(chan struct{})
+
+	failing := OnReload(func(context.Context) error {
+		calls.Add(1)
+		return errors.New("bad config")
+	})
+	ok := OnReload(func(context.Context) error {
+		calls.Add(1)
+		close(reloaded)
+		return nil
+	})
+	comp := func(ctx context.Context) error {
+		// Give Run a moment to register the SIGHUP handler.
+		time.Sleep(50 * time.Millisecond)
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP); err != nil {
+			return err
+		}
+		select {
+		case <-reloaded:
+			return nil
+		case <-time.After(3 * time.Second):
+			return errors.New("reload hooks were not called")
+		}
+	}
+
+	if err := Run(context.Background(), failing, ok, comp); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("hook calls = %d, want 2", n)
+	}
+}
@@ -0,0 +1,85 @@
+package lifecycle
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy controls how Restart re-runs a failed component.
+type Policy struct {
+	// MaxRestarts is how many times the component is restarted over the
+	// life of Run before its error is returned. Zero or negative means no
+	// limit.
+	MaxRestarts int
+	// Backoff is the delay before the first restart; it doubles on each
+	// subsequent restart, plus up to 50% jitter. Defaults to one second.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between restarts. Defaults to 30 seconds.
+	MaxBackoff time.Duration
+}
+
+// Restart returns a Spec that re-runs c with exponential backoff when it
+// returns an error while Run is still running, instead of tearing the whole
+// service down — for transient crashes such as a consumer losing its broker
+// connection. A component that returns nil, or returns after its context is
+// cancelled, is not restarted. Once p.MaxRestarts is exhausted the error is
+// returned to Run as usual. c may be a Component, a bare
+// func(ctx context.Context) error, or a *Spec.
+func Restart(c any, p Policy) *Spec {
+	s := toSpec(c)
+	s.restart = &p
+	return s
+}
+
+// delay returns the wait before restart number n (zero-based).
+func (p *Policy) delay(n int) time.Duration {
+	base := p.Backoff
+	if base <= 0 {
+		base = time.Second
+	}
+	maxDelay := p.MaxBackoff
+	if maxDelay <= 0 {
+		maxDelay = 30 * time.Second
+	}
+	d := base
+	for range n {
+		d *= 2
+		if d >= maxDelay {
+			d = maxDelay
+			break
+		}
+	}
+	// Add jitter: random duration in [0, d/2).
+	if half := int64(d / 2); half > 0 {
+		d += time.Duration(rand.Int64N(half))
+	}
+	return min(d, maxDelay)
+}
+
+// runWithRestarts runs s.run, restarting it according to s.restart.
+func runWithRestarts(ctx context.Context, o *options, s *Spec) error {
+	err := runAttempt(ctx, o, s)
+	if s.restart == nil {
+		return err
+	}
+	for n := 0; err != nil && ctx.Err() == nil; n++ {
+		if s.restart.MaxRestarts > 0 && n >= s.restart.MaxRestarts {
+			return err
+		}
+		delay := s.restart.delay(n)
+		o.logger.WarnContext(ctx, "lifecycle: restarting component",
+			"component", s.name, "restart", n+1, "delay", delay, "error", err)
+		o.emit(Event{Type: ComponentRestarting, Component: s.name, Err: err})
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil
+		case <-t.C:
+		}
+		err = runAttempt(ctx, o, s)
+	}
+	return err
+}
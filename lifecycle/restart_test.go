@@ -0,0 +1,87 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRestartRecoversTransientFailure(t *testing.T) {
+	resetKafkaTest(t)
+	var runs atomic.Int32
+
+	consumer := func(ctx context.Context) error {
+		if runs.Add(1) < 3 {
+			return errors.New("broker connection lost")
+		}
+		return nil
+	}
+
+	err := Run(context.Background(), Restart(Named("consumer", consumer), Policy{Backoff: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("expected recovery after restarts, got %v", err)
+	}
+	if n := runs.Load(); n != 3 {
+		t.Errorf("runs = %d, want 3", n)
+	}
+}
+
+func TestRestartGivesUpAfterMaxRestarts(t *testing.T) {
+	resetKafkaTest(t)
+	var runs atomic.Int32
+	boom := errors.New("boom")
+
+	err := Run(context.Background(), Restart(func(ctx context.Context) error {
+		runs.Add(1)
+		return boom
+	}, Policy{MaxRestarts: 2, Backoff: time.Millisecond}))
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected %v, got %v", boom, err)
+	}
+	if n := runs.Load(); n != 3 {
+		t.Errorf("runs = %d, want 3 (initial + 2 restarts)", n)
+	}
+}
+
+func TestRestartStopsOnShutdownDuringBackoff(t *testing.T) {
+	resetKafkaTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	failed := make(chan struct{}, 1)
+
+	flaky := func(ctx context.Context) error {
+		select {
+		case failed <- struct{}{}:
+		default:
+		}
+		return errors.New("crash")
+	}
+	go func() {
+		<-failed
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() { done <- Run(ctx, Restart(flaky, Policy{Backoff: time.Hour})) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected clean shutdown during backoff, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Run did not return while the component was waiting to restart")
+	}
+}
+
+func TestPolicyDelay(t *testing.T) {
+	p := Policy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for n, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		if d := p.delay(n); d < want || d >= want+want/2 {
+			t.Errorf("delay(%d) = %s, want in [%s, %s)", n, d, want, want+want/2)
+		}
+	}
+	if d := p.delay(10); d != time.Second {
+		t.Errorf("delay(10) = %s, want capped at %s", d, time.Second)
+	}
+}
@@ -0,0 +1,132 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// DefaultDrainTimeout is how long HTTPServer and GRPCServer wait for
+// in-flight requests to finish before forcing connections closed.
+const DefaultDrainTimeout = 30 * time.Second
+
+// ServerOption configures HTTPServer and GRPCServer.
+type ServerOption func(*serverOptions)
+
+type serverOptions struct {
+	drainTimeout time.Duration
+}
+
+// WithDrainTimeout sets how long a server adapter waits for in-flight
+// requests once its context is cancelled. Defaults to DefaultDrainTimeout.
+func WithDrainTimeout(d time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		o.drainTimeout = d
+	}
+}
+
+func buildServerOptions(opts []ServerOption) serverOptions {
+	o := serverOptions{drainTimeout: DefaultDrainTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// HTTPServer returns a Component that serves srv on ln until its context is
+// cancelled, then calls Shutdown so in-flight requests can finish. If they
+// have not finished within the drain timeout the server is closed and an
+// error is returned. If ln is nil the component listens on srv.Addr itself,
+// so bind failures are reported as component errors. When srv.TLSConfig is
+// set the server serves TLS using its configured certificates.
+func HTTPServer(srv *http.Server, ln net.Listener, opts ...ServerOption) Component {
+	o := buildServerOptions(opts)
+	return func(ctx context.Context) error {
+		l := ln
+		if l == nil {
+			addr := srv.Addr
+			if addr == "" {
+				addr = ":http"
+			}
+			var err error
+			l, err = net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("lifecycle: http server listen: %w", err)
+			}
+		}
+		Started(ctx)
+
+		errCh := make(chan error, 1)
+		go func() {
+			if srv.TLSConfig != nil {
+				errCh <- srv.ServeTLS(l, "", "")
+			} else {
+				errCh <- srv.Serve(l)
+			}
+		}()
+
+		select {
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), o.drainTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			srv.Close()
+			return fmt.Errorf("lifecycle: http server drain: %w", err)
+		}
+		if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// GRPCServer returns a Component that serves srv on ln until its context is
+// cancelled, then calls GracefulStop. If in-flight RPCs have not finished
+// within the drain timeout the server is stopped forcibly and an error is
+// returned.
+func GRPCServer(srv *grpc.Server, ln net.Listener, opts ...ServerOption) Component {
+	o := buildServerOptions(opts)
+	return func(ctx context.Context) error {
+		if ln == nil {
+			return errors.New("lifecycle: grpc server requires a listener")
+		}
+		Started(ctx)
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- srv.Serve(ln) }()
+
+		select {
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+		}
+
+		stopped := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+		t := time.NewTimer(o.drainTimeout)
+		defer t.Stop()
+		select {
+		case <-stopped:
+		case <-t.C:
+			srv.Stop()
+			<-stopped
+			return fmt.Errorf("lifecycle: grpc server drain: %w", context.DeadlineExceeded)
+		}
+		if err := <-errCh; err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func listenLocal(t *testing.T) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ln
+}
+
+func TestHTTPServerServesAndDrains(t *testing.T) {
+	ln := listenLocal(t)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	})}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- HTTPServer(srv, ln)(ctx) }()
+
+	resp, err := http.Get("http://" + ln.Addr().String())
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want ok", body)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected clean shutdown, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("HTTPServer did not return after cancellation")
+	}
+}
+
+func TestHTTPServerDrainTimeout(t *testing.T) {
+	ln := listenLocal(t)
+	inHandler := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(inHandler)
+		<-release
+	})}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- HTTPServer(srv, ln, WithDrainTimeout(50*time.Millisecond))(ctx) }()
+
+	go http.Get("http://" + ln.Addr().String())
+	<-inHandler
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected drain deadline error, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("HTTPServer did not give up after the drain timeout")
+	}
+}
+
+func TestHTTPServerListensOnAddr(t *testing.T) {
+	// Occupy a port so the adapter's own listen fails.
+	ln := listenLocal(t)
+	defer ln.Close()
+	srv := &http.Server{Addr: ln.Addr().String()}
+	if err := HTTPServer(srv, nil)(context.Background()); err == nil {
+		t.Fatal("expected a listen error for an address in use")
+	}
+}
+
+func TestGRPCServerGracefulStop(t *testing.T) {
+	ln := listenLocal(t)
+	srv := grpc.NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- GRPCServer(srv, ln)(ctx) }()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected clean shutdown, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("GRPCServer did not return after cancellation")
+	}
+}
+
+func TestGRPCServerRequiresListener(t *testing.T) {
+	if err := GRPCServer(grpc.NewServer(), nil)(context.Background()); err == nil {
+		t.Fatal("expected error for nil listener")
+	}
+}
@@ -0,0 +1,100 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrStartTimeout is returned for a WaitStarted component that does not call
+// Started within the WithStartTimeout deadline.
+var ErrStartTimeout = errors.New("lifecycle: component did not start in time")
+
+type startedKey struct{}
+
+// startSignal is closed once by Started. A signal for one attempt has the
+// component's signal as parent, which Run waits on before starting the next
+// phase.
+type startSignal struct {
+	once   sync.Once
+	done   chan struct{}
+	parent *startSignal
+}
+
+func newStartSignal(parent *startSignal) *startSignal {
+	return &startSignal{done: make(chan struct{}), parent: parent}
+}
+
+// fire closes s and its ancestors.
+func (s *startSignal) fire() {
+	for ; s != nil; s = s.parent {
+		s.once.Do(func() { close(s.done) })
+	}
+}
+
+// Started reports that the calling component has finished starting — its
+// listener is bound or its broker connection is up. Call it with the context
+// Run passed to the component. It is a no-op when the component is not
+// waited on, so it is always safe to call. HTTPServer and GRPCServer call it
+// once their listener is bound.
+func Started(ctx context.Context) {
+	if sig, ok := ctx.Value(startedKey{}).(*startSignal); ok {
+		sig.fire()
+	}
+}
+
+// WithStartTimeout bounds how long WaitStarted components may take to call
+// Started. A component that has not started after d is cancelled and fails
+// with an error wrapping ErrStartTimeout, so a server that never binds its
+// port fails fast instead of hanging. Zero (the default) disables the check.
+func WithStartTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.startTimeout = d
+	}
+}
+
+// WaitStarted returns a Spec whose component signals readiness by calling
+// Started, making it subject to WithStartTimeout. Run starts the next phase
+// only once it has called Started. Each restart attempt gets a fresh
+// deadline. c may be a Component, a bare
+// func(ctx context.Context) error, or a *Spec.
+func WaitStarted(c any) *Spec {
+	s := toSpec(c)
+	s.waitStarted = true
+	return s
+}
+
+// runAttempt runs s.run once, enforcing the start deadline if s is waited on.
+func runAttempt(ctx context.Context, o *options, s *Spec) error {
+	if !s.waitStarted || o.startTimeout <= 0 {
+		return s.run(ctx)
+	}
+
+	timeoutErr := fmt.Errorf("%w (%s)", ErrStartTimeout, o.startTimeout)
+	parent, _ := ctx.Value(startedKey{}).(*startSignal)
+	sig := newStartSignal(parent)
+	attemptCtx, cancel := context.WithCancelCause(context.WithValue(ctx, startedKey{}, sig))
+	defer cancel(nil)
+
+	begin := time.Now()
+	go func() {
+		t := time.NewTimer(o.startTimeout)
+		defer t.Stop()
+		select {
+		case <-sig.done:
+			o.logger.InfoContext(ctx, "lifecycle: component ready", "component", s.name, "startup", time.Since(begin))
+		case <-t.C:
+			o.logger.ErrorContext(ctx, "lifecycle: component did not start", "component", s.name, "timeout", o.startTimeout)
+			cancel(timeoutErr)
+		case <-attemptCtx.Done():
+		}
+	}()
+
+	err := s.run(attemptCtx)
+	if ctx.Err() == nil && context.Cause(attemptCtx) == timeoutErr {
+		return timeoutErr
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitStartedTimesOut(t *testing.T) {
+	resetKafkaTest(t)
+	start := time.Now()
+	err := Run(context.Background(),
+		WithStartTimeout(50*time.Millisecond),
+		Named("broker", WaitStarted(func(ctx context.Context) error {
+			<-ctx.Done() // never calls Started
+			return nil
+		})),
+	)
+	if !errors.Is(err, ErrStartTimeout) {
+		t.Fatalf("expected ErrStartTimeout, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `component "broker"`) {
+		t.Errorf("error should name the component: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Run took %s, expected to fail fast", elapsed)
+	}
+}
+
+func TestWaitStartedSignalled(t *testing.T) {
+	resetKafkaTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := Run(ctx,
+		WithStartTimeout(50*time.Millisecond),
+		WaitStarted(func(ctx context.Context) error {
+			Started(ctx)
+			Started(ctx) // repeated calls are harmless
+			time.Sleep(150 * time.Millisecond)
+			cancel()
+			<-ctx.Done()
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("expected clean exit, got %v", err)
+	}
+}
+
+func TestStartTimeoutIgnoresUnwaitedComponents(t *testing.T) {
+	resetKafkaTest(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	err := Run(ctx, WithStartTimeout(20*time.Millisecond), func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("component without WaitStarted should not time out, got %v", err)
+	}
+}
+
+func TestStartedOutsideRunIsNoop(t *testing.T) {
+	Started(context.Background())
+}
+
+func TestWaitStartedRestartGetsFreshDeadline(t *testing.T) {
+	resetKafkaTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	attempts := 0
+	err := Run(ctx,
+		WithStartTimeout(30*time.Millisecond),
+		Restart(WaitStarted(func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				<-ctx.Done() // stalls on first attempt
+				return ctx.Err()
+			}
+			Started(ctx)
+			cancel()
+			<-ctx.Done()
+			return nil
+		}), Policy{MaxRestarts: 1, Backoff: time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatalf("expected restart to recover, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestHTTPServerSignalsStarted(t *testing.T) {
+	resetKafkaTest(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	srv := &http.Server{Handler: http.NotFoundHandler()}
+	err = Run(ctx, WithStartTimeout(50*time.Millisecond), WaitStarted(HTTPServer(srv, ln)))
+	if err != nil {
+		t.Fatalf("expected clean exit, got %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrShutdownTimeout is returned by Run when components are still running
+// after the WithShutdownTimeout deadline.
+var ErrShutdownTimeout = errors.New("lifecycle: shutdown timed out")
+
+type specResult struct {
+	spec  *Spec
+	phase int // index into the sorted phase list
+	err   error
+}
+
+// runComponents launches specs phase by phase and waits for them. Each phase
+// is launched once the WaitStarted components of the one before have called
+// Started; a critical failure or cancellation of ctx meanwhile stops further
+// phases from launching. When ctx is cancelled, a critical component fails,
+// or every critical component has returned, the launched phases are
+// cancelled from the highest down, each one fully drained before the next.
+// It returns the first non-nil error from a critical component, or an
+// *AggregateError of all of them with WithAllErrors, joined with
+// ErrShutdownTimeout if draining exceeds o.shutdownTimeout.
+func runComponents(ctx context.Context, o *options, specs []*Spec) error {
+	phases := groupByPhase(specs)
+
+	// Phase contexts are detached from ctx so that cancellation can be
+	// applied in reverse phase order rather than all at once.
+	base := context.WithoutCancel(ctx)
+	cancels := make([]context.CancelFunc, len(phases))
+	running := make([]map[*Spec]bool, len(phases))
+	results := make(chan specResult, len(specs))
+	started := make(chan *Spec, len(specs))
+	defer func() {
+		for _, cancel := range cancels {
+			if cancel != nil {
+				cancel()
+			}
+		}
+	}()
+
+	var firstErr error
+	var firstFailed string
+	var failures []error // critical failures, excluding cancellation during drain
+	draining := false
+	remaining := 0
+	critical := 0
+	for _, s := range specs {
+		if !s.bestEffort {
+			critical++
+		}
+	}
+	// With only best-effort components, wait for all of them instead.
+	waitForAll := critical == 0
+	critical = 0
+	var starting map[*Spec]bool // WaitStarted components of the phase being started
+	record := func(r specResult) {
+		delete(running[r.phase], r.spec)
+		delete(starting, r.spec)
+		remaining--
+		if r.spec.bestEffort {
+			return
+		}
+		critical--
+		if r.err == nil {
+			return
+		}
+		if firstErr == nil {
+			firstErr = r.err
+			firstFailed = r.spec.name
+		}
+		if !draining || !errors.Is(r.err, context.Canceled) {
+			failures = append(failures, r.err)
+		}
+	}
+	result := func(trigger error) error {
+		if o.allErrors && len(failures) > 0 {
+			return &AggregateError{Trigger: trigger, Errors: failures}
+		}
+		return firstErr
+	}
+
+launch:
+	for i, group := range phases {
+		phaseCtx, cancel := context.WithCancel(base)
+		cancels[i] = cancel
+		running[i] = make(map[*Spec]bool, len(group))
+		starting = make(map[*Spec]bool)
+		for _, s := range group {
+			running[i][s] = true
+			remaining++
+			if !s.bestEffort {
+				critical++
+			}
+			specCtx := phaseCtx
+			if s.waitStarted {
+				sig := newStartSignal(nil)
+				specCtx = context.WithValue(phaseCtx, startedKey{}, sig)
+				starting[s] = true
+				go func() {
+					select {
+					case <-sig.done:
+						started <- s
+					case <-phaseCtx.Done():
+					}
+				}()
+			}
+			go func() {
+				results <- specResult{spec: s, phase: i, err: runSpec(specCtx, o, s)}
+			}()
+		}
+		if i == len(phases)-1 {
+			break
+		}
+		for len(starting) > 0 && firstErr == nil {
+			select {
+			case <-ctx.Done():
+				break launch
+			case s := <-started:
+				delete(starting, s)
+			case r := <-results:
+				record(r)
+			}
+		}
+		if firstErr != nil || ctx.Err() != nil {
+			break
+		}
+	}
+	starting = nil
+
+wait:
+	for remaining > 0 && firstErr == nil && (waitForAll || critical > 0) {
+		select {
+		case <-ctx.Done():
+			break wait
+		case r := <-results:
+			record(r)
+		}
+	}
+
+	draining = true
+	trigger := firstErr
+	switch {
+	case firstErr != nil:
+		o.emit(Event{Type: ShutdownInitiated, Reason: ReasonComponentFailed, Component: firstFailed, Err: firstErr})
+	case ctx.Err() != nil:
+		o.emit(Event{Type: ShutdownInitiated, Reason: context.Cause(ctx).Error()})
+	default:
+		o.emit(Event{Type: ShutdownInitiated, Reason: ReasonComponentsStopped})
+	}
+	deadline, stopDeadline := shutdownDeadline(o)
+	defer stopDeadline()
+	if o.readiness != nil {
+		o.readiness.Drain()
+	}
+	if remaining > 0 {
+		waitDrainDelay(o, deadline)
+	}
+	for i := len(phases) - 1; i >= 0; i-- {
+		if cancels[i] == nil {
+			continue // never launched
+		}
+		cancels[i]()
+		for len(running[i]) > 0 {
+			select {
+			case r := <-results:
+				record(r)
+			case <-deadline:
+				stuck := stillRunning(running)
+				o.logger.Error("lifecycle: shutdown timed out", "timeout", o.shutdownTimeout, "still_running", stuck)
+				return errors.Join(result(trigger), fmt.Errorf("%w after %s; still running: %s",
+					ErrShutdownTimeout, o.shutdownTimeout, strings.Join(stuck, ", ")))
+			}
+		}
+	}
+	return result(trigger)
+}
+
+// shutdownDeadline returns a channel closed once o.shutdownTimeout has
+// passed, or nil without a timeout, and a func that releases its timer.
+func shutdownDeadline(o *options) (<-chan struct{}, context.CancelFunc) {
+	if o.shutdownTimeout <= 0 {
+		return nil, func() {}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), o.shutdownTimeout)
+	return ctx.Done(), cancel
+}
+
+// stillRunning returns the sorted names of components that have not returned.
+func stillRunning(running []map[*Spec]bool) []string {
+	var names []string
+	for _, phase := range running {
+		for s := range phase {
+			names = append(names, s.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// groupByPhase groups specs by phase in ascending phase order, preserving
+// argument order within a phase.
+func groupByPhase(specs []*Spec) [][]*Spec {
+	byPhase := make(map[int][]*Spec)
+	var keys []int
+	for _, s := range specs {
+		if _, ok := byPhase[s.phase]; !ok {
+			keys = append(keys, s.phase)
+		}
+		byPhase[s.phase] = append(byPhase[s.phase], s)
+	}
+	sort.Ints(keys)
+	phases := make([][]*Spec, len(keys))
+	for i, k := range keys {
+		phases[i] = byPhase[k]
+	}
+	return phases
+}
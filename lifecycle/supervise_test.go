@@ -0,0 +1,278 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// orderRecorder records component events in the order they happen.
+type orderRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *orderRecorder) add(ev string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+}
+
+func (r *orderRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+// blocking returns a component that records its start, calls Started,
+// waits for cancellation, then records its stop.
+func (r *orderRecorder) blocking(name string) Component {
+	return func(ctx context.Context) error {
+		r.add("start " + name)
+		Started(ctx)
+		<-ctx.Done()
+		r.add("stop " + name)
+		return nil
+	}
+}
+
+func TestPhasesStartInOrderAndStopInReverse(t *testing.T) {
+	resetKafkaTest(t)
+	var rec orderRecorder
+	ctx, cancel := context.WithCancel(context.Background())
+
+	started := make(chan struct{})
+	server := func(ctx context.Context) error {
+		close(started)
+		return rec.blocking("server")(ctx)
+	}
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	err := Run(ctx,
+		Phase(2, Named("server", server)),
+		Phase(1, Named("db", WaitStarted(rec.blocking("db")))),
+		Named("metrics", WaitStarted(rec.blocking("metrics"))),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := rec.get()
+	startIdx := func(name string) int { return slices.Index(events, "start "+name) }
+	if !(startIdx("metrics") < startIdx("db") && startIdx("db") < startIdx("server")) {
+		t.Errorf("expected start order metrics, db, server; got %v", events)
+	}
+	stopIdx := func(name string) int { return slices.Index(events, "stop "+name) }
+	if !(stopIdx("server") < stopIdx("db") && stopIdx("db") < stopIdx("metrics")) {
+		t.Errorf("expected stop order server, db, metrics; got %v", events)
+	}
+}
+
+func TestNextPhaseWaitsForStarted(t *testing.T) {
+	resetKafkaTest(t)
+	var serverRan atomic.Bool
+	release := make(chan struct{})
+	dbStarted := make(chan struct{})
+	db := func(ctx context.Context) error {
+		<-release // still connecting
+		Started(ctx)
+		close(dbStarted)
+		<-ctx.Done()
+		return nil
+	}
+	server := func(ctx context.Context) error {
+		serverRan.Store(true)
+		<-ctx.Done()
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Run(ctx, Phase(1, WaitStarted(db)), Phase(2, server)) }()
+
+	time.Sleep(50 * time.Millisecond)
+	if serverRan.Load() {
+		t.Fatal("phase 2 ran before phase 1 called Started")
+	}
+	close(release)
+	<-dbStarted
+	deadline := time.Now().Add(2 * time.Second)
+	for !serverRan.Load() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !serverRan.Load() {
+		t.Fatal("phase 2 did not run after phase 1 started")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFailedPhaseSkipsLaterPhases(t *testing.T) {
+	resetKafkaTest(t)
+	var serverRan atomic.Bool
+	err := Run(context.Background(),
+		WithStartTimeout(30*time.Millisecond),
+		Phase(1, Named("db", WaitStarted(func(ctx context.Context) error {
+			<-ctx.Done() // never calls Started
+			return nil
+		}))),
+		Phase(2, func(ctx context.Context) error {
+			serverRan.Store(true)
+			<-ctx.Done()
+			return nil
+		}),
+	)
+	if !errors.Is(err, ErrStartTimeout) {
+		t.Fatalf("expected ErrStartTimeout, got %v", err)
+	}
+	if serverRan.Load() {
+		t.Error("phase 2 ran although phase 1 failed to start")
+	}
+}
+
+func TestPhaseFailureDrainsHigherPhasesFirst(t *testing.T) {
+	resetKafkaTest(t)
+	var rec orderRecorder
+	boom := errors.New("db lost")
+
+	dbCtxDone := make(chan struct{})
+	db := func(ctx context.Context) error {
+		rec.add("start db")
+		<-dbCtxDone
+		return boom
+	}
+	server := func(ctx context.Context) error {
+		rec.add("start server")
+		close(dbCtxDone)
+		<-ctx.Done()
+		rec.add("stop server")
+		return nil
+	}
+	cache := func(ctx context.Context) error {
+		<-ctx.Done()
+		rec.add("stop cache")
+		return nil
+	}
+
+	err := Run(context.Background(), Phase(0, cache), Phase(1, db), Phase(2, server))
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected %v, got %v", boom, err)
+	}
+	events := rec.get()
+	if slices.Index(events, "stop server") > slices.Index(events, "stop cache") {
+		t.Errorf("server should stop before cache; got %v", events)
+	}
+}
+
+func TestGroupByPhase(t *testing.T) {
+	a, b, c, d := &Spec{name: "a", phase: 2}, &Spec{name: "b"}, &Spec{name: "c", phase: -1}, &Spec{name: "d", phase: 2}
+	got := groupByPhase([]*Spec{a, b, c, d})
+	want := [][]*Spec{{c}, {b}, {a, d}}
+	if len(got) != len(want) {
+		t.Fatalf("got %d phases, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !slices.Equal(got[i], want[i]) {
+			t.Errorf("phase %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestShutdownTimeoutReportsStuckComponents(t *testing.T) {
+	resetKafkaTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+	stuck := func(ctx context.Context) error {
+		<-release // ignores ctx
+		return nil
+	}
+	polite := func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	start := time.Now()
+	err := Run(ctx, WithShutdownTimeout(50*time.Millisecond), Named("consumer", stuck), Named("http", polite))
+	if !errors.Is(err, ErrShutdownTimeout) {
+		t.Fatalf("expected ErrShutdownTimeout, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "still running: consumer") {
+		t.Errorf("error %q should name the stuck component", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Run took %s, expected it to give up after the timeout", elapsed)
+	}
+}
+
+func TestBestEffortFailureDoesNotStopService(t *testing.T) {
+	resetKafkaTest(t)
+	refresherFailed := make(chan struct{})
+	var serverSawCancel bool
+
+	refresher := func(ctx context.Context) error {
+		close(refresherFailed)
+		return errors.New("refresh failed")
+	}
+	server := func(ctx context.Context) error {
+		<-refresherFailed
+		select {
+		case <-ctx.Done():
+			serverSawCancel = true
+		case <-time.After(50 * time.Millisecond):
+		}
+		return nil
+	}
+
+	if err := Run(context.Background(), BestEffort(Named("refresher", refresher)), server); err != nil {
+		t.Fatalf("best-effort failure should not be returned, got %v", err)
+	}
+	if serverSawCancel {
+		t.Error("best-effort failure should not cancel critical components")
+	}
+}
+
+func TestBestEffortCancelledWhenCriticalComponentsFinish(t *testing.T) {
+	resetKafkaTest(t)
+	var rec orderRecorder
+
+	err := Run(context.Background(),
+		BestEffort(rec.blocking("refresher")),
+		func(ctx context.Context) error { return nil },
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Contains(rec.get(), "stop refresher") {
+		t.Errorf("best-effort component should be cancelled; events %v", rec.get())
+	}
+}
+
+func TestCriticalFailureStillStopsBestEffort(t *testing.T) {
+	resetKafkaTest(t)
+	var rec orderRecorder
+	boom := errors.New("boom")
+
+	err := Run(context.Background(),
+		BestEffort(rec.blocking("refresher")),
+		func(ctx context.Context) error { return boom },
+	)
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected %v, got %v", boom, err)
+	}
+	if !slices.Contains(rec.get(), "stop refresher") {
+		t.Errorf("best-effort component should be cancelled; events %v", rec.get())
+	}
+}
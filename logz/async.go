@@ -0,0 +1,193 @@
+package logz
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// BackpressurePolicy selects what an async logger does when its buffer is full.
+type BackpressurePolicy int
+
+const (
+	// DropOldest discards the oldest buffered record to make room. Logging
+	// never blocks; the next written record carries "dropped_count".
+	DropOldest BackpressurePolicy = iota
+	// Block makes the logging goroutine wait for buffer space. No records
+	// are lost, but a stalled writer stalls callers.
+	Block
+)
+
+// AsyncConfig controls the buffer used by WithAsync.
+type AsyncConfig struct {
+	// BufferSize is the maximum number of buffered records. Defaults to 4096.
+	BufferSize int
+	// Policy applies when the buffer is full. Defaults to DropOldest.
+	Policy BackpressurePolicy
+}
+
+// WithAsync moves encoding and writing off the calling goroutine: records are
+// queued in a bounded buffer and written by a single background goroutine.
+// Call Shutdown before exiting so buffered records are not lost.
+func WithAsync(cfg AsyncConfig) Option {
+	return func(o *options) { o.async = &cfg }
+}
+
+// Shutdown writes any records buffered by a WithAsync logger and stops its
+// background goroutine, waiting until the buffer is drained or ctx is done.
+// Records logged afterwards are written synchronously. Shutdown is a no-op
+// for loggers without WithAsync.
+func Shutdown(ctx context.Context, logger *slog.Logger) error {
+	s, ok := logger.Handler().(shutdowner)
+	if !ok {
+		return nil
+	}
+	return s.shutdown(ctx)
+}
+
+// shutdowner is implemented by handlers that own background work.
+type shutdowner interface {
+	shutdown(ctx context.Context) error
+}
+
+type asyncEntry struct {
+	ctx context.Context
+	h   slog.Handler
+	r   slog.Record
+}
+
+// asyncQueue is the ring buffer and worker shared by an async handler and
+// all handlers derived from it via WithAttrs and WithGroup.
+type asyncQueue struct {
+	policy BackpressurePolicy
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	buf      []asyncEntry
+	head     int // index of the oldest entry
+	n        int // number of buffered entries
+	dropped  int64
+	closed   bool
+	done     chan struct{}
+}
+
+func newAsyncQueue(cfg AsyncConfig) *asyncQueue {
+	size := cfg.BufferSize
+	if size <= 0 {
+		size = 4096
+	}
+	q := &asyncQueue{
+		policy: cfg.Policy,
+		buf:    make([]asyncEntry, size),
+		done:   make(chan struct{}),
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	go q.run()
+	return q
+}
+
+// enqueue buffers e. It reports false once the queue is closed, in which
+// case the caller writes the record itself.
+func (q *asyncQueue) enqueue(e asyncEntry) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.n == len(q.buf) && !q.closed {
+		if q.policy != Block {
+			q.buf[q.head] = asyncEntry{}
+			q.head = (q.head + 1) % len(q.buf)
+			q.n--
+			q.dropped++
+			break
+		}
+		q.notFull.Wait()
+	}
+	if q.closed {
+		return false
+	}
+	q.buf[(q.head+q.n)%len(q.buf)] = e
+	q.n++
+	q.notEmpty.Signal()
+	return true
+}
+
+// run writes buffered records until the queue is closed and drained.
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	batch := make([]asyncEntry, 0, len(q.buf))
+	for {
+		q.mu.Lock()
+		for q.n == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if q.n == 0 {
+			q.mu.Unlock()
+			return
+		}
+		for ; q.n > 0; q.n-- {
+			batch = append(batch, q.buf[q.head])
+			q.buf[q.head] = asyncEntry{}
+			q.head = (q.head + 1) % len(q.buf)
+		}
+		dropped := q.dropped
+		q.dropped = 0
+		q.notFull.Broadcast()
+		q.mu.Unlock()
+
+		for i, e := range batch {
+			if i == 0 && dropped > 0 {
+				e.r.AddAttrs(slog.Int64("dropped_count", dropped))
+			}
+			// Nowhere to report write errors from the background goroutine.
+			_ = e.h.Handle(e.ctx, e.r)
+		}
+		clear(batch)
+		batch = batch[:0]
+	}
+}
+
+func (q *asyncQueue) shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+	q.mu.Unlock()
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return errors.Join(errors.New("logz: async buffer not drained"), ctx.Err())
+	}
+}
+
+// asyncHandler queues records for inner on a shared asyncQueue.
+type asyncHandler struct {
+	inner slog.Handler
+	queue *asyncQueue
+}
+
+func (h *asyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *asyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	e := asyncEntry{ctx: context.WithoutCancel(ctx), h: h.inner, r: r.Clone()}
+	if h.queue.enqueue(e) {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *asyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &asyncHandler{inner: h.inner.WithAttrs(attrs), queue: h.queue}
+}
+
+func (h *asyncHandler) WithGroup(name string) slog.Handler {
+	return &asyncHandler{inner: h.inner.WithGroup(name), queue: h.queue}
+}
+
+func (h *asyncHandler) shutdown(ctx context.Context) error {
+	return h.queue.shutdown(ctx)
+}
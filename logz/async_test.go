@@ -0,0 +1,155 @@
+package logz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedWriter blocks every Write until release is closed.
+type gatedWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) lines() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return strings.Split(strings.TrimSpace(w.buf.String()), "\n")
+}
+
+func newAsyncTestLogger(w *gatedWriter, cfg AsyncConfig) *slog.Logger {
+	inner := slog.NewJSONHandler(w, nil)
+	return slog.New(&asyncHandler{inner: inner, queue: newAsyncQueue(cfg)})
+}
+
+func TestAsync_WritesInOrderAfterShutdown(t *testing.T) {
+	w := &gatedWriter{release: make(chan struct{})}
+	close(w.release)
+	logger := newAsyncTestLogger(w, AsyncConfig{BufferSize: 16})
+
+	child := logger.With("svc", "api").WithGroup("req")
+	for i := range 5 {
+		child.Info("msg", "i", i)
+	}
+	if err := Shutdown(context.Background(), logger); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	lines := w.lines()
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want 5: %v", len(lines), lines)
+	}
+	for i, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+		req := entry["req"].(map[string]any)
+		if entry["svc"] != "api" || req["i"] != float64(i) {
+			t.Errorf("line %d = %s", i, line)
+		}
+	}
+
+	// After shutdown records are written synchronously.
+	logger.Info("late")
+	if got := w.lines(); len(got) != 6 || !strings.Contains(got[5], "late") {
+		t.Errorf("late record not written: %v", got)
+	}
+}
+
+func TestAsync_DropOldest(t *testing.T) {
+	w := &gatedWriter{release: make(chan struct{})}
+	logger := newAsyncTestLogger(w, AsyncConfig{BufferSize: 2, Policy: DropOldest})
+
+	// The first record is picked up by the worker, which then blocks in Write.
+	logger.Info("first")
+	time.Sleep(20 * time.Millisecond)
+	for _, msg := range []string{"a", "b", "c", "d"} {
+		logger.Info(msg) // must not block
+	}
+	close(w.release)
+	if err := Shutdown(context.Background(), logger); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	lines := w.lines()
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3: %v", len(lines), lines)
+	}
+	if !strings.Contains(lines[1], `"msg":"c"`) || !strings.Contains(lines[1], `"dropped_count":2`) {
+		t.Errorf("line 1 = %s, want c with dropped_count 2", lines[1])
+	}
+	if !strings.Contains(lines[2], `"msg":"d"`) {
+		t.Errorf("line 2 = %s, want d", lines[2])
+	}
+}
+
+func TestAsync_BlockPolicy(t *testing.T) {
+	w := &gatedWriter{release: make(chan struct{})}
+	logger := newAsyncTestLogger(w, AsyncConfig{BufferSize: 1, Policy: Block})
+
+	logger.Info("first")
+	time.Sleep(20 * time.Millisecond)
+	logger.Info("buffered")
+
+	blocked := make(chan struct{})
+	go func() {
+		logger.Info("blocked")
+		close(blocked)
+	}()
+	select {
+	case <-blocked:
+		t.Fatal("Block policy should wait for buffer space")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(w.release)
+	<-blocked
+	if err := Shutdown(context.Background(), logger); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if lines := w.lines(); len(lines) != 3 {
+		t.Errorf("got %d lines, want 3: %v", len(lines), lines)
+	}
+}
+
+func TestAsync_ShutdownTimeout(t *testing.T) {
+	w := &gatedWriter{release: make(chan struct{})}
+	defer close(w.release)
+	logger := newAsyncTestLogger(w, AsyncConfig{})
+	logger.Info("stuck")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := Shutdown(ctx, logger); err == nil {
+		t.Fatal("expected error when the buffer cannot drain")
+	}
+}
+
+func TestShutdown_NonAsyncLogger(t *testing.T) {
+	if err := Shutdown(context.Background(), New("info")); err != nil {
+		t.Fatalf("Shutdown on sync logger: %v", err)
+	}
+}
+
+func TestNew_WithAsyncAndMetrics(t *testing.T) {
+	logger := New("info", WithAsync(AsyncConfig{}), WithMetrics())
+	logger.Info("through the chain")
+	if err := Shutdown(context.Background(), logger); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
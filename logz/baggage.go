@@ -0,0 +1,34 @@
+package logz
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// WithBaggage copies the named OTel baggage members (e.g. "customer_id",
+// "deployment") from the record's context into each record as top-level
+// attributes, next to trace_id and span_id. Members absent from the context
+// are skipped. Only listed keys are copied, since baggage is set by callers
+// and may carry data that should not be logged.
+func WithBaggage(keys ...string) Option {
+	return func(o *options) { o.baggageKeys = append(o.baggageKeys, keys...) }
+}
+
+// appendBaggage appends an attribute for each of keys present in ctx's baggage.
+func appendBaggage(ctx context.Context, attrs []slog.Attr, keys []string) []slog.Attr {
+	if len(keys) == 0 {
+		return attrs
+	}
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 {
+		return attrs
+	}
+	for _, k := range keys {
+		if m := bag.Member(k); m.Key() != "" {
+			attrs = append(attrs, slog.String(k, m.Value()))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,93 @@
+package logz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newBaggageTestLogger(buf *bytes.Buffer, keys ...string) *slog.Logger {
+	inner := slog.NewJSONHandler(buf, nil)
+	return slog.New(&traceHandler{inner: inner, base: inner, baggageKeys: keys})
+}
+
+func baggageContext(t *testing.T, members ...string) context.Context {
+	t.Helper()
+	var ms []baggage.Member
+	for i := 0; i < len(members); i += 2 {
+		m, err := baggage.NewMember(members[i], members[i+1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		ms = append(ms, m)
+	}
+	bag, err := baggage.New(ms...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return baggage.ContextWithBaggage(context.Background(), bag)
+}
+
+func TestWithBaggage_SelectedKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newBaggageTestLogger(&buf, "customer_id", "deployment")
+	ctx := baggageContext(t, "customer_id", "c-42", "secret", "hunter2")
+
+	logger.InfoContext(ctx, "hello")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["customer_id"] != "c-42" {
+		t.Errorf("customer_id = %v, want c-42", entry["customer_id"])
+	}
+	if _, ok := entry["secret"]; ok {
+		t.Error("unselected baggage member should not be logged")
+	}
+	if _, ok := entry["deployment"]; ok {
+		t.Error("absent baggage member should be skipped")
+	}
+}
+
+func TestWithBaggage_TopLevelInsideGroups(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newBaggageTestLogger(&buf, "customer_id").WithGroup("req").With("path", "/x")
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(baggageContext(t, "customer_id", "c-42"),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	logger.InfoContext(ctx, "hello", "status", 200)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["customer_id"] != "c-42" || entry["trace_id"] == nil {
+		t.Errorf("expected top-level customer_id and trace_id, got %v", entry)
+	}
+	req, _ := entry["req"].(map[string]any)
+	if req["path"] != "/x" || req["status"] != float64(200) {
+		t.Errorf("req group = %v", req)
+	}
+}
+
+func TestWithBaggage_NoBaggage(t *testing.T) {
+	var buf bytes.Buffer
+	newBaggageTestLogger(&buf, "customer_id").Info("hello")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := entry["customer_id"]; ok {
+		t.Errorf("unexpected customer_id: %v", entry)
+	}
+}
@@ -0,0 +1,202 @@
+package logz
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Output formats accepted by WithFormat.
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// WithFormat selects the output encoding: FormatJSON (the default) or
+// FormatConsole for colorized single-line output during local development.
+// The value is case-insensitive so it can come straight from config, e.g. a
+// LOG_FORMAT env var; unrecognized values fall back to JSON.
+func WithFormat(format string) Option {
+	return func(o *options) { o.format = strings.ToLower(strings.TrimSpace(format)) }
+}
+
+// NewPretty creates a logger that writes human-readable console output. It is
+// shorthand for New(level, append(opts, WithFormat(FormatConsole))...).
+func NewPretty(level string, opts ...Option) *slog.Logger {
+	return New(level, append(opts, WithFormat(FormatConsole))...)
+}
+
+// ANSI escape sequences used by the console format.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// consoleMsgWidth pads messages so attributes line up across records.
+const consoleMsgWidth = 40
+
+// consoleHandler writes records as single aligned lines:
+//
+//	15:04:05.000 INF handling request                         path=/api/users trace_id=4bf9…
+type consoleHandler struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	level  slog.Leveler
+	color  bool
+	prefix string // dotted group prefix applied to record attrs
+	attrs  string // attrs pre-rendered by WithAttrs
+}
+
+func newConsoleHandler(w io.Writer, level slog.Leveler) *consoleHandler {
+	return &consoleHandler{mu: &sync.Mutex{}, w: w, level: level, color: colorEnabled(w)}
+}
+
+// colorEnabled reports whether w is a terminal and NO_COLOR is unset.
+func colorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	ts := r.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	h.paint(&b, ansiDim, ts.Format("15:04:05.000"))
+	b.WriteByte(' ')
+	label, color := levelLabel(r.Level)
+	h.paint(&b, color, label)
+	b.WriteByte(' ')
+	b.WriteString(r.Message)
+	if pad := consoleMsgWidth - len(r.Message); pad > 0 && (h.attrs != "" || r.NumAttrs() > 0) {
+		b.WriteString(strings.Repeat(" ", pad))
+	}
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		h.appendAttr(&b, h.prefix, a)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	for _, a := range attrs {
+		h.appendAttr(&b, h.prefix, a)
+	}
+	h2 := *h
+	h2.attrs += b.String()
+	return &h2
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix += name + "."
+	return &h2
+}
+
+// appendAttr renders a as " key=value", flattening groups into dotted keys.
+func (h *consoleHandler) appendAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			h.appendAttr(b, prefix, ga)
+		}
+		return
+	}
+	b.WriteByte(' ')
+	h.paint(b, ansiCyan, prefix+a.Key+"=")
+	b.WriteString(formatConsoleValue(a.Value))
+}
+
+func (h *consoleHandler) paint(b *strings.Builder, color, s string) {
+	if h.color {
+		b.WriteString(color)
+		b.WriteString(s)
+		b.WriteString(ansiReset)
+		return
+	}
+	b.WriteString(s)
+}
+
+func levelLabel(l slog.Level) (label, color string) {
+	switch {
+	case l < slog.LevelInfo:
+		return "DBG", ansiBlue
+	case l < slog.LevelWarn:
+		return "INF", ansiReset
+	case l < slog.LevelError:
+		return "WRN", ansiYellow
+	default:
+		return "ERR", ansiRed
+	}
+}
+
+func formatConsoleValue(v slog.Value) string {
+	var s string
+	switch v.Kind() {
+	case slog.KindTime:
+		s = v.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			s = err.Error()
+		} else {
+			s = fmt.Sprint(v.Any())
+		}
+	default:
+		s = v.String()
+	}
+	if needsQuoting(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package logz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newConsoleTestLogger(buf *bytes.Buffer, level slog.Level) *slog.Logger {
+	h := newConsoleHandler(buf, level)
+	return slog.New(&traceHandler{inner: h, base: h})
+}
+
+func TestConsole_Format(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newConsoleTestLogger(&buf, slog.LevelDebug)
+
+	logger.With("svc", "api").WithGroup("req").Warn("slow request",
+		"path", "/users", "err", errors.New("deadline exceeded"))
+
+	line := buf.String()
+	if !strings.HasSuffix(line, "\n") || strings.Count(line, "\n") != 1 {
+		t.Fatalf("expected a single line, got %q", line)
+	}
+	for _, want := range []string{
+		" WRN slow request",
+		" svc=api",
+		" req.path=/users",
+		` req.err="deadline exceeded"`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("output %q missing %q", line, want)
+		}
+	}
+	if strings.Contains(line, "\x1b[") {
+		t.Errorf("non-terminal output should not be colorized: %q", line)
+	}
+}
+
+func TestConsole_LevelFiltering(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newConsoleTestLogger(&buf, slog.LevelInfo)
+	logger.Debug("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("debug output at info level: %q", buf.String())
+	}
+}
+
+func TestConsole_TraceIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newConsoleTestLogger(&buf, slog.LevelInfo)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+	}))
+	logger.WithGroup("g").InfoContext(ctx, "hello", "k", "v")
+
+	out := buf.String()
+	if !strings.Contains(out, "trace_id=4bf92f3577b34da6a3ce929d0e0e4736") || !strings.Contains(out, "g.k=v") {
+		t.Errorf("output %q missing trace_id or grouped attr", out)
+	}
+}
+
+func TestConsole_Colorized(t *testing.T) {
+	var buf bytes.Buffer
+	h := newConsoleHandler(&buf, slog.LevelInfo)
+	h.color = true
+	slog.New(h).Error("boom")
+	if !strings.Contains(buf.String(), ansiRed+"ERR"+ansiReset) {
+		t.Errorf("expected red ERR label, got %q", buf.String())
+	}
+}
+
+func TestNewPretty(t *testing.T) {
+	logger := NewPretty("debug")
+	th, ok := logger.Handler().(*traceHandler)
+	if !ok {
+		t.Fatalf("handler = %T, want *traceHandler", logger.Handler())
+	}
+	if _, ok := th.inner.(*consoleHandler); !ok {
+		t.Errorf("inner handler = %T, want *consoleHandler", th.inner)
+	}
+}
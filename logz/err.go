@@ -0,0 +1,68 @@
+package logz
+
+import (
+	stderrors "errors"
+	"log/slog"
+	"runtime"
+	"strconv"
+
+	chassiserrors "github.com/ai8future/chassis-go/v11/errors"
+)
+
+// maxStackDepth bounds the number of frames captured by ErrWithStack.
+const maxStackDepth = 32
+
+// Err returns an "error" group attribute describing err, replacing the
+// hand-rolled "error", err.Error() pair:
+//
+//	logger.Error("charge failed", logz.Err(err))
+//
+// The group always carries "msg". When err is or wraps a ServiceError it
+// also carries the problem "type" URI, the gRPC "code" and the HTTP "status".
+// A nil err yields an empty attribute, which handlers ignore.
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.Attr{Key: "error", Value: slog.GroupValue(errAttrs(err)...)}
+}
+
+// ErrWithStack is like Err but also records the caller's stack as a "stack"
+// list of "function file:line" frames. Capturing a stack is comparatively
+// expensive; reserve it for unexpected errors.
+func ErrWithStack(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	attrs := append(errAttrs(err), slog.Any("stack", callerStack(3)))
+	return slog.Attr{Key: "error", Value: slog.GroupValue(attrs...)}
+}
+
+func errAttrs(err error) []slog.Attr {
+	attrs := []slog.Attr{slog.String("msg", err.Error())}
+	var se *chassiserrors.ServiceError
+	if stderrors.As(err, &se) {
+		attrs = append(attrs,
+			slog.String("type", se.ProblemDetail(nil).Type),
+			slog.String("code", se.GRPCCode.String()),
+			slog.Int("status", se.HTTPCode),
+		)
+	}
+	return attrs
+}
+
+// callerStack formats the stack starting skip frames above runtime.Callers.
+func callerStack(skip int) []string {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	stack := make([]string, 0, n)
+	for {
+		f, more := frames.Next()
+		stack = append(stack, f.Function+" "+f.File+":"+strconv.Itoa(f.Line))
+		if !more {
+			break
+		}
+	}
+	return stack
+}
@@ -0,0 +1,72 @@
+package logz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	chassiserrors "github.com/ai8future/chassis-go/v11/errors"
+)
+
+func logErrAttr(t *testing.T, attr any) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	newTestLogger(&buf, "info").Error("failed", attr)
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	return entry
+}
+
+func TestErr_PlainError(t *testing.T) {
+	entry := logErrAttr(t, Err(fmt.Errorf("dial tcp: connection refused")))
+	group, ok := entry["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("error = %v, want group", entry["error"])
+	}
+	if group["msg"] != "dial tcp: connection refused" {
+		t.Errorf("msg = %v", group["msg"])
+	}
+	if _, ok := group["code"]; ok {
+		t.Errorf("plain errors should not carry a code: %v", group)
+	}
+}
+
+func TestErr_ServiceError(t *testing.T) {
+	err := fmt.Errorf("loading order: %w", chassiserrors.NotFoundError("order 42 not found"))
+	group := logErrAttr(t, Err(err))["error"].(map[string]any)
+
+	if group["msg"] != "loading order: order 42 not found" {
+		t.Errorf("msg = %v", group["msg"])
+	}
+	if group["type"] != "https://chassis.ai8future.com/errors/not-found" {
+		t.Errorf("type = %v", group["type"])
+	}
+	if group["code"] != "NotFound" {
+		t.Errorf("code = %v", group["code"])
+	}
+	if group["status"] != float64(404) {
+		t.Errorf("status = %v", group["status"])
+	}
+}
+
+func TestErr_Nil(t *testing.T) {
+	entry := logErrAttr(t, Err(nil))
+	if _, ok := entry["error"]; ok {
+		t.Errorf("nil error should not be logged: %v", entry)
+	}
+}
+
+func TestErrWithStack(t *testing.T) {
+	group := logErrAttr(t, ErrWithStack(fmt.Errorf("boom")))["error"].(map[string]any)
+	stack, ok := group["stack"].([]any)
+	if !ok || len(stack) == 0 {
+		t.Fatalf("stack = %v, want non-empty list", group["stack"])
+	}
+	if top := stack[0].(string); !strings.Contains(top, "TestErrWithStack") {
+		t.Errorf("top frame = %q, want the caller", top)
+	}
+}
@@ -0,0 +1,270 @@
+package logz
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+)
+
+// Output is a log destination for NewWithOutput. FileOutput is the only
+// implementation.
+type Output interface {
+	open() (io.Writer, error)
+}
+
+// FileOutput writes logs to a file and rotates it by size. Rotated files are
+// renamed to <name>-<UTC timestamp><ext> next to Path, optionally gzipped,
+// and pruned by count and age. Use it on VMs without a log shipper.
+type FileOutput struct {
+	// Path is the active log file. Its directory is created if missing.
+	Path string
+	// MaxSizeMB is the size at which the file is rotated. Defaults to 100.
+	MaxSizeMB int
+	// MaxBackups is how many rotated files to keep. Zero keeps all of them
+	// (subject to MaxAgeDays).
+	MaxBackups int
+	// MaxAgeDays removes rotated files older than this many days. Zero
+	// disables age-based removal.
+	MaxAgeDays int
+	// Compress gzips rotated files.
+	Compress bool
+}
+
+func (f FileOutput) open() (io.Writer, error) {
+	if f.Path == "" {
+		return nil, fmt.Errorf("logz: file output requires a path")
+	}
+	rf := newRotatingFile(f)
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if err := rf.openExisting(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// NewWithOutput is like New but writes to out instead of stderr. It returns
+// an error if the output cannot be opened.
+func NewWithOutput(level string, out Output, opts ...Option) (*slog.Logger, error) {
+	chassis.AssertVersionChecked()
+	w, err := out.open()
+	if err != nil {
+		return nil, err
+	}
+	return newLogger(w, level, opts), nil
+}
+
+// backupTimeFormat is filesystem-safe on every platform (no colons).
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// rotatingFile is an io.Writer that rotates the underlying file once it
+// exceeds maxSize. Pruning and compression of old files run in the
+// background so rotation never blocks logging for long.
+type rotatingFile struct {
+	cfg     FileOutput
+	maxSize int64
+	now     func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	millMu sync.Mutex     // serializes pruning/compression passes
+	millWg sync.WaitGroup // tracks in-flight passes, for close and tests
+}
+
+func newRotatingFile(cfg FileOutput) *rotatingFile {
+	size := cfg.MaxSizeMB
+	if size <= 0 {
+		size = 100
+	}
+	return &rotatingFile{cfg: cfg, maxSize: int64(size) * 1024 * 1024, now: time.Now}
+}
+
+// Write appends p to the current file, rotating first if p would push it
+// past the size limit. A single write larger than the limit goes to a fresh
+// file rather than being split or rejected.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		if err := r.openExisting(); err != nil {
+			return 0, err
+		}
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// close closes the current file and waits for background pruning.
+func (r *rotatingFile) close() error {
+	r.mu.Lock()
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	r.mu.Unlock()
+	r.millWg.Wait()
+	return err
+}
+
+// openExisting opens Path for appending, creating it and its directory if
+// needed. Caller must hold r.mu.
+func (r *rotatingFile) openExisting() error {
+	if err := os.MkdirAll(filepath.Dir(r.cfg.Path), 0o755); err != nil {
+		return fmt.Errorf("logz: create log directory: %w", err)
+	}
+	f, err := os.OpenFile(r.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("logz: open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("logz: stat log file: %w", err)
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file to a timestamped backup, opens a new one,
+// and schedules a pruning pass. Caller must hold r.mu.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("logz: close log file: %w", err)
+	}
+	r.file = nil
+	if err := os.Rename(r.cfg.Path, r.backupName(r.now())); err != nil {
+		return fmt.Errorf("logz: rotate log file: %w", err)
+	}
+	if err := r.openExisting(); err != nil {
+		return err
+	}
+	r.millWg.Add(1)
+	go func() {
+		defer r.millWg.Done()
+		r.mill()
+	}()
+	return nil
+}
+
+func (r *rotatingFile) backupName(t time.Time) string {
+	dir, base := filepath.Split(r.cfg.Path)
+	ext := filepath.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+	return filepath.Join(dir, name+"-"+t.UTC().Format(backupTimeFormat)+ext)
+}
+
+type backupFile struct {
+	path string
+	ts   time.Time
+}
+
+// backups lists rotated files for this output, newest first.
+func (r *rotatingFile) backups() ([]backupFile, error) {
+	dir, base := filepath.Split(r.cfg.Path)
+	if dir == "" {
+		dir = "."
+	}
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []backupFile
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		stamp, ok := strings.CutPrefix(e.Name(), prefix)
+		if !ok {
+			continue
+		}
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		stamp, ok = strings.CutSuffix(stamp, ext)
+		if !ok {
+			continue
+		}
+		ts, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		out = append(out, backupFile{path: filepath.Join(dir, e.Name()), ts: ts})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ts.After(out[j].ts) })
+	return out, nil
+}
+
+// mill removes backups beyond MaxBackups or older than MaxAgeDays and
+// compresses the rest when Compress is set. Failures are ignored: the worst
+// case is an extra file on disk, which the next pass retries.
+func (r *rotatingFile) mill() {
+	r.millMu.Lock()
+	defer r.millMu.Unlock()
+
+	files, err := r.backups()
+	if err != nil {
+		return
+	}
+	var cutoff time.Time
+	if r.cfg.MaxAgeDays > 0 {
+		cutoff = r.now().Add(-time.Duration(r.cfg.MaxAgeDays) * 24 * time.Hour)
+	}
+	for i, f := range files {
+		if (r.cfg.MaxBackups > 0 && i >= r.cfg.MaxBackups) || (!cutoff.IsZero() && f.ts.Before(cutoff)) {
+			os.Remove(f.path)
+			continue
+		}
+		if r.cfg.Compress && !strings.HasSuffix(f.path, ".gz") {
+			compressFile(f.path)
+		}
+	}
+}
+
+// compressFile gzips src to src+".gz" and removes src on success.
+func compressFile(src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(src+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		os.Remove(src + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		os.Remove(src + ".gz")
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(src + ".gz")
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
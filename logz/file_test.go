@@ -0,0 +1,174 @@
+package logz
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestRotatingFile returns a rotatingFile with a tiny size limit and a
+// clock that advances one second per call so backup names never collide.
+func newTestRotatingFile(t *testing.T, cfg FileOutput, maxSize int64) *rotatingFile {
+	t.Helper()
+	rf := newRotatingFile(cfg)
+	rf.maxSize = maxSize
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	rf.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	t.Cleanup(func() { rf.close() })
+	return rf
+}
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestNewWithOutput_WritesJSONToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	logger, err := NewWithOutput("info", FileOutput{Path: path})
+	if err != nil {
+		t.Fatalf("NewWithOutput: %v", err)
+	}
+	logger.Info("hello", "k", "v")
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(b, &entry); err != nil {
+		t.Fatalf("invalid JSON %q: %v", b, err)
+	}
+	if entry["msg"] != "hello" || entry["k"] != "v" {
+		t.Errorf("entry = %v", entry)
+	}
+}
+
+func TestNewWithOutput_RequiresPath(t *testing.T) {
+	if _, err := NewWithOutput("info", FileOutput{}); err == nil {
+		t.Fatal("expected error for empty path")
+	}
+}
+
+func TestRotatingFile_RotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	rf := newTestRotatingFile(t, FileOutput{Path: path}, 10)
+
+	for _, s := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n"} {
+		if _, err := rf.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rf.millWg.Wait()
+
+	names := listDir(t, dir)
+	want := []string{"app-2026-01-02T03-04-06.000.log", "app-2026-01-02T03-04-07.000.log", "app.log"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("files = %v, want %v", names, want)
+	}
+	b, _ := os.ReadFile(path)
+	if string(b) != "cccccc\n" {
+		t.Errorf("active file = %q, want last write", b)
+	}
+}
+
+func TestRotatingFile_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("12345678\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rf := newTestRotatingFile(t, FileOutput{Path: path}, 10)
+	if _, err := rf.Write([]byte("x\n")); err != nil {
+		t.Fatal(err)
+	}
+	rf.millWg.Wait()
+	if names := listDir(t, filepath.Dir(path)); len(names) != 2 {
+		t.Errorf("existing size should count toward the limit; files = %v", names)
+	}
+}
+
+func TestRotatingFile_MaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	rf := newTestRotatingFile(t, FileOutput{Path: filepath.Join(dir, "app.log"), MaxBackups: 2}, 5)
+	for range 5 {
+		rf.Write([]byte("12345\n"))
+		rf.millWg.Wait()
+	}
+
+	names := listDir(t, dir)
+	want := []string{"app-2026-01-02T03-04-08.000.log", "app-2026-01-02T03-04-09.000.log", "app.log"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", names, want)
+	}
+}
+
+func TestRotatingFile_MaxAge(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "app-2025-01-01T00-00-00.000.log")
+	if err := os.WriteFile(stale, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unrelated := filepath.Join(dir, "other.log")
+	if err := os.WriteFile(unrelated, []byte("keep\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rf := newTestRotatingFile(t, FileOutput{Path: filepath.Join(dir, "app.log"), MaxAgeDays: 7}, 5)
+	rf.Write([]byte("12345\n"))
+	rf.Write([]byte("12345\n"))
+	rf.millWg.Wait()
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale backup should be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Errorf("unrelated file should be kept: %v", err)
+	}
+}
+
+func TestRotatingFile_Compress(t *testing.T) {
+	dir := t.TempDir()
+	rf := newTestRotatingFile(t, FileOutput{Path: filepath.Join(dir, "app.log"), Compress: true}, 5)
+	rf.Write([]byte("first\n"))
+	rf.Write([]byte("second\n"))
+	rf.millWg.Wait()
+
+	gz := filepath.Join(dir, "app-2026-01-02T03-04-06.000.log.gz")
+	f, err := os.Open(gz)
+	if err != nil {
+		t.Fatalf("expected compressed backup: %v (files %v)", err, listDir(t, dir))
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "first\n" {
+		t.Errorf("decompressed = %q, want %q", b, "first\n")
+	}
+	if _, err := os.Stat(strings.TrimSuffix(gz, ".gz")); !os.IsNotExist(err) {
+		t.Errorf("uncompressed backup should be removed, stat err = %v", err)
+	}
+}
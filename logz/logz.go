@@ -3,24 +3,89 @@ package logz
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"os"
+	"regexp"
 	"strings"
 
 	chassis "github.com/ai8future/chassis-go/v11"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/trace"
 )
 
-// New creates a structured JSON logger at the given level.
+// New creates a structured JSON logger at the given level. Use
+// WithFormat(FormatConsole) or NewPretty for human-readable output.
 // Accepted levels are "debug", "info", "warn", "error" (case-insensitive).
 // Unrecognized levels default to "info".
-func New(level string) *slog.Logger {
+func New(level string, opts ...Option) *slog.Logger {
 	chassis.AssertVersionChecked()
+	return newLogger(os.Stderr, level, opts)
+}
+
+// newLogger builds the handler chain shared by New and NewWithOutput.
+func newLogger(w io.Writer, level string, opts []Option) *slog.Logger {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
 	lvl := parseLevel(level)
-	jsonHandler := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
-		Level: lvl,
-	})
-	return slog.New(&traceHandler{inner: jsonHandler, base: jsonHandler})
+	var out slog.Handler
+	if o.format == FormatConsole {
+		out = newConsoleHandler(w, lvl)
+	} else {
+		out = slog.NewJSONHandler(w, &slog.HandlerOptions{
+			Level: lvl,
+		})
+	}
+	var h slog.Handler = &traceHandler{inner: out, base: out, baggageKeys: o.baggageKeys}
+	if o.loggerProvider != nil {
+		h = newFanoutHandler(h, newOTelHandler(o.loggerProvider, lvl))
+	}
+	if len(o.redactKeys) > 0 || len(o.redactPatterns) > 0 {
+		h = newRedactHandler(h, o.redactKeys, o.redactPatterns)
+	}
+	if o.sampling != nil {
+		h = &samplingHandler{inner: h, sampler: newSampler(*o.sampling)}
+	}
+	if o.async != nil {
+		h = &asyncHandler{inner: h, queue: newAsyncQueue(*o.async)}
+	}
+	if o.metrics {
+		h = &metricsHandler{inner: h}
+	}
+	return slog.New(h)
+}
+
+// Option configures a logger created by New.
+type Option func(*options)
+
+type options struct {
+	format         string
+	loggerProvider otellog.LoggerProvider
+	redactKeys     []string
+	redactPatterns []*regexp.Regexp
+	sampling       *SamplingConfig
+	metrics        bool
+	async          *AsyncConfig
+	baggageKeys    []string
+}
+
+// WithLoggerProvider additionally ships every record through the OTel logs
+// API using lp, so logs reach the same collector as traces and metrics. Trace
+// and span IDs are correlated by the provider from the record's context. The
+// stderr JSON output is unchanged.
+func WithLoggerProvider(lp otellog.LoggerProvider) Option {
+	return func(o *options) { o.loggerProvider = lp }
+}
+
+// WithOTelLogs is WithLoggerProvider with the global OTel LoggerProvider,
+// which otel.Init sets up to export over OTLP when otel.Config.Logs is set.
+// The logger may be built before otel.Init runs: records are dropped until
+// then and shipped afterwards.
+func WithOTelLogs() Option {
+	return WithLoggerProvider(global.GetLoggerProvider())
 }
 
 // parseLevel converts a level string to a slog.Level.
@@ -47,10 +112,12 @@ func parseLevel(level string) slog.Level {
 // and the base handler (without groups) so that trace_id is always emitted at
 // the top level of the JSON output.
 type traceHandler struct {
-	inner      slog.Handler // current handler with groups and attrs applied
-	base       slog.Handler // base handler without groups, for top-level trace_id
-	groups     []string     // accumulated group names for record reconstruction
+	inner      slog.Handler  // current handler with groups and attrs applied
+	base       slog.Handler  // base handler without groups, for top-level trace_id
+	groups     []string      // accumulated group names for record reconstruction
 	groupAttrs [][]slog.Attr // attrs added via WithAttrs while inside groups, per group depth
+
+	baggageKeys []string // baggage members to copy into each record, from WithBaggage
 }
 
 // Enabled delegates to the inner handler.
@@ -60,25 +127,28 @@ func (h *traceHandler) Enabled(ctx context.Context, level slog.Level) bool {
 
 // Handle extracts trace information from the OTel span context and, if present,
 // adds "trace_id" and "span_id" attributes to the record before delegating.
+// Baggage members selected with WithBaggage are added alongside them.
 //
 // When groups are active, the record is reconstructed so that trace_id and
 // span_id appear at the top level while other attributes remain nested.
 func (h *traceHandler) Handle(ctx context.Context, r slog.Record) error {
-	var traceID, spanID string
+	var top []slog.Attr
 
 	sc := trace.SpanContextFromContext(ctx)
 	if sc.IsValid() {
-		traceID = sc.TraceID().String()
-		spanID = sc.SpanID().String()
+		top = append(top,
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
 	}
+	top = appendBaggage(ctx, top, h.baggageKeys)
 
-	if traceID == "" {
+	if len(top) == 0 {
 		return h.inner.Handle(ctx, r)
 	}
 
 	if len(h.groups) == 0 {
-		r.AddAttrs(slog.String("trace_id", traceID))
-		r.AddAttrs(slog.String("span_id", spanID))
+		r.AddAttrs(top...)
 		return h.inner.Handle(ctx, r)
 	}
 
@@ -109,8 +179,7 @@ func (h *traceHandler) Handle(ctx context.Context, r slog.Record) error {
 	}
 
 	newRecord := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
-	newRecord.AddAttrs(slog.String("trace_id", traceID))
-	newRecord.AddAttrs(slog.String("span_id", spanID))
+	newRecord.AddAttrs(top...)
 	newRecord.AddAttrs(grouped)
 
 	return h.base.Handle(ctx, newRecord)
@@ -139,10 +208,11 @@ func (h *traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 		groupAttrs[idx] = merged
 	}
 	return &traceHandler{
-		inner:      h.inner.WithAttrs(attrs),
-		base:       base,
-		groups:     h.groups,
-		groupAttrs: groupAttrs,
+		inner:       h.inner.WithAttrs(attrs),
+		base:        base,
+		groups:      h.groups,
+		groupAttrs:  groupAttrs,
+		baggageKeys: h.baggageKeys,
 	}
 }
 
@@ -155,10 +225,11 @@ func (h *traceHandler) WithGroup(name string) slog.Handler {
 	newGroupAttrs := make([][]slog.Attr, len(newGroups))
 	copy(newGroupAttrs, h.groupAttrs)
 	return &traceHandler{
-		inner:      h.inner.WithGroup(name),
-		base:       h.base,
-		groups:     newGroups,
-		groupAttrs: newGroupAttrs,
+		inner:       h.inner.WithGroup(name),
+		base:        h.base,
+		groups:      newGroups,
+		groupAttrs:  newGroupAttrs,
+		baggageKeys: h.baggageKeys,
 	}
 }
 
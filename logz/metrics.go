@@ -0,0 +1,87 @@
+package logz
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/ai8future/chassis-go/v11/internal/otelutil"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ModuleKey is the attribute key WithMetrics uses to label records by module,
+// e.g. logger.With(logz.ModuleKey, "billing").
+const ModuleKey = "module"
+
+var getLogRecordsCounter = otelutil.LazyCounter(
+	scopeName,
+	"log.records",
+	metric.WithUnit("{record}"),
+	metric.WithDescription("Number of log records emitted, by level and module"),
+)
+
+// WithMetrics counts every enabled record on the global MeterProvider as the
+// log.records counter, labelled with "level" and, when the logger or record
+// carries a top-level ModuleKey attribute, "module". Records are counted
+// before sampling so dashboards see the true volume of error logs.
+func WithMetrics() Option {
+	return func(o *options) { o.metrics = true }
+}
+
+// metricsHandler counts records and delegates to inner.
+type metricsHandler struct {
+	inner   slog.Handler
+	module  string
+	grouped bool // attrs added after WithGroup are not top-level
+}
+
+func (h *metricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *metricsHandler) Handle(ctx context.Context, r slog.Record) error {
+	module := h.module
+	if !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == ModuleKey {
+				module = a.Value.String()
+				return false
+			}
+			return true
+		})
+	}
+	attrs := []attribute.KeyValue{attribute.String("level", strings.ToLower(r.Level.String()))}
+	if module != "" {
+		attrs = append(attrs, attribute.String("module", module))
+	}
+	getLogRecordsCounter().Add(ctx, 1, metric.WithAttributes(attrs...))
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *metricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	module := h.module
+	if !h.grouped {
+		for _, a := range attrs {
+			if a.Key == ModuleKey {
+				module = a.Value.String()
+			}
+		}
+	}
+	return &metricsHandler{inner: h.inner.WithAttrs(attrs), module: module, grouped: h.grouped}
+}
+
+func (h *metricsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &metricsHandler{inner: h.inner.WithGroup(name), module: h.module, grouped: true}
+}
+
+// shutdown lets Shutdown reach an async handler wrapped by metricsHandler.
+func (h *metricsHandler) shutdown(ctx context.Context) error {
+	if s, ok := h.inner.(shutdowner); ok {
+		return s.shutdown(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package logz
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collectLogRecords returns log.records counts keyed by "level/module".
+func collectLogRecords(t *testing.T, reader sdkmetric.Reader) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	counts := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "log.records" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				level, _ := dp.Attributes.Value(attribute.Key("level"))
+				module, _ := dp.Attributes.Value(attribute.Key("module"))
+				counts[level.AsString()+"/"+module.AsString()] += dp.Value
+			}
+		}
+	}
+	return counts
+}
+
+func TestWithMetrics_CountsByLevelAndModule(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	prev := otelapi.GetMeterProvider()
+	otelapi.SetMeterProvider(mp)
+	defer func() {
+		otelapi.SetMeterProvider(prev)
+		mp.Shutdown(context.Background())
+	}()
+
+	var buf bytes.Buffer
+	inner := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := slog.New(&metricsHandler{inner: inner})
+
+	logger.Debug("filtered out")
+	logger.Info("plain")
+	logger.Error("plain failure")
+	billing := logger.With(ModuleKey, "billing")
+	billing.Error("charge failed")
+	billing.WithGroup("req").Error("nested", ModuleKey, "ignored")
+	logger.Warn("per record", ModuleKey, "auth")
+
+	got := collectLogRecords(t, reader)
+	want := map[string]int64{
+		"info/":         1,
+		"error/":        1,
+		"error/billing": 2,
+		"warn/auth":     1,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("counts = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("count[%s] = %d, want %d (all: %v)", k, got[k], v, got)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package logz
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+// scopeName is the instrumentation scope reported to the OTel logs pipeline.
+const scopeName = "github.com/ai8future/chassis-go/v11/logz"
+
+// fanoutHandler sends every record to each of its handlers.
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+func newFanoutHandler(handlers ...slog.Handler) *fanoutHandler {
+	return &fanoutHandler{handlers: handlers}
+}
+
+// Enabled reports true if any handler is enabled for level.
+func (h *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, hh := range h.handlers {
+		if hh.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes a clone of r to every enabled handler and returns the first
+// error encountered.
+func (h *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, hh := range h.handlers {
+		if !hh.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := hh.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make([]slog.Handler, len(h.handlers))
+	for i, hh := range h.handlers {
+		out[i] = hh.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers: out}
+}
+
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	out := make([]slog.Handler, len(h.handlers))
+	for i, hh := range h.handlers {
+		out[i] = hh.WithGroup(name)
+	}
+	return &fanoutHandler{handlers: out}
+}
+
+// otelHandler bridges slog records to an OTel log.Logger.
+type otelHandler struct {
+	logger otellog.Logger
+	level  slog.Leveler
+	goas   []groupOrAttrs // WithGroup/WithAttrs calls, outermost first
+}
+
+// groupOrAttrs records either a WithGroup name or a WithAttrs batch.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+func newOTelHandler(lp otellog.LoggerProvider, level slog.Leveler) *otelHandler {
+	return &otelHandler{logger: lp.Logger(scopeName), level: level}
+}
+
+func (h *otelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < h.level.Level() {
+		return false
+	}
+	return h.logger.Enabled(ctx, otellog.EnabledParameters{Severity: severity(level)})
+}
+
+func (h *otelHandler) Handle(ctx context.Context, r slog.Record) error {
+	var rec otellog.Record
+	rec.SetTimestamp(r.Time)
+	rec.SetObservedTimestamp(time.Now())
+	rec.SetBody(otellog.StringValue(r.Message))
+	rec.SetSeverity(severity(r.Level))
+	rec.SetSeverityText(r.Level.String())
+
+	kvs := make([]otellog.KeyValue, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		kvs = appendKeyValue(kvs, a)
+		return true
+	})
+	// Wrap record attrs in the active groups, innermost first. Empty groups
+	// are omitted, matching slog's built-in handlers.
+	for i := len(h.goas) - 1; i >= 0; i-- {
+		g := h.goas[i]
+		if g.group == "" {
+			pre := make([]otellog.KeyValue, 0, len(g.attrs)+len(kvs))
+			for _, a := range g.attrs {
+				pre = appendKeyValue(pre, a)
+			}
+			kvs = append(pre, kvs...)
+			continue
+		}
+		if len(kvs) > 0 {
+			kvs = []otellog.KeyValue{otellog.Map(g.group, kvs...)}
+		}
+	}
+	rec.AddAttributes(kvs...)
+
+	h.logger.Emit(ctx, rec)
+	return nil
+}
+
+func (h *otelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(groupOrAttrs{attrs: attrs})
+}
+
+func (h *otelHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(groupOrAttrs{group: name})
+}
+
+func (h *otelHandler) with(g groupOrAttrs) *otelHandler {
+	goas := make([]groupOrAttrs, len(h.goas)+1)
+	copy(goas, h.goas)
+	goas[len(h.goas)] = g
+	return &otelHandler{logger: h.logger, level: h.level, goas: goas}
+}
+
+// severity maps slog levels onto the OTel severity scale: Debug → DEBUG,
+// Info → INFO, Warn → WARN, Error → ERROR, with intermediate levels mapping
+// to the numbered variants in between.
+func severity(level slog.Level) otellog.Severity {
+	return otellog.Severity(level + 9)
+}
+
+// appendKeyValue converts a slog.Attr and appends it to kvs. Empty attrs are
+// dropped and inline groups (empty key) are flattened, as slog does.
+func appendKeyValue(kvs []otellog.KeyValue, a slog.Attr) []otellog.KeyValue {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return kvs
+	}
+	if a.Value.Kind() == slog.KindGroup && a.Key == "" {
+		for _, ga := range a.Value.Group() {
+			kvs = appendKeyValue(kvs, ga)
+		}
+		return kvs
+	}
+	return append(kvs, otellog.KeyValue{Key: a.Key, Value: convertValue(a.Value)})
+}
+
+func convertValue(v slog.Value) otellog.Value {
+	switch v.Kind() {
+	case slog.KindString:
+		return otellog.StringValue(v.String())
+	case slog.KindInt64:
+		return otellog.Int64Value(v.Int64())
+	case slog.KindUint64:
+		u := v.Uint64()
+		if u > math.MaxInt64 {
+			return otellog.StringValue(fmt.Sprint(u))
+		}
+		return otellog.Int64Value(int64(u))
+	case slog.KindFloat64:
+		return otellog.Float64Value(v.Float64())
+	case slog.KindBool:
+		return otellog.BoolValue(v.Bool())
+	case slog.KindDuration:
+		return otellog.Int64Value(v.Duration().Nanoseconds())
+	case slog.KindTime:
+		return otellog.Int64Value(v.Time().UnixNano())
+	case slog.KindGroup:
+		var kvs []otellog.KeyValue
+		for _, ga := range v.Group() {
+			kvs = appendKeyValue(kvs, ga)
+		}
+		return otellog.MapValue(kvs...)
+	default:
+		switch x := v.Any().(type) {
+		case error:
+			return otellog.StringValue(x.Error())
+		case []byte:
+			return otellog.BytesValue(x)
+		default:
+			return otellog.StringValue(fmt.Sprint(x))
+		}
+	}
+}
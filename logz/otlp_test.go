@@ -0,0 +1,127 @@
+package logz
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// recordingProvider is an in-memory otellog.LoggerProvider for tests.
+type recordingProvider struct {
+	embedded.LoggerProvider
+	mu      sync.Mutex
+	scope   string
+	records []otellog.Record
+}
+
+func (p *recordingProvider) Logger(name string, _ ...otellog.LoggerOption) otellog.Logger {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.scope = name
+	return &recordingLogger{p: p}
+}
+
+type recordingLogger struct {
+	embedded.Logger
+	p *recordingProvider
+}
+
+func (l *recordingLogger) Emit(_ context.Context, r otellog.Record) {
+	l.p.mu.Lock()
+	defer l.p.mu.Unlock()
+	l.p.records = append(l.p.records, r.Clone())
+}
+
+func (l *recordingLogger) Enabled(context.Context, otellog.EnabledParameters) bool { return true }
+
+func attrMap(r otellog.Record) map[string]otellog.Value {
+	m := map[string]otellog.Value{}
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		m[kv.Key] = kv.Value
+		return true
+	})
+	return m
+}
+
+func TestWithLoggerProvider_EmitsRecords(t *testing.T) {
+	lp := &recordingProvider{}
+	logger := New("info", WithLoggerProvider(lp))
+
+	logger.Debug("dropped")
+	logger.With("service", "billing").WithGroup("req").Warn("slow request",
+		"status", 503, "err", errors.New("upstream timeout"))
+
+	if lp.scope != scopeName {
+		t.Errorf("scope = %q, want %q", lp.scope, scopeName)
+	}
+	if len(lp.records) != 1 {
+		t.Fatalf("got %d records, want 1 (debug filtered by level)", len(lp.records))
+	}
+	r := lp.records[0]
+	if r.Body().AsString() != "slow request" {
+		t.Errorf("body = %q, want %q", r.Body().AsString(), "slow request")
+	}
+	if r.Severity() != otellog.SeverityWarn {
+		t.Errorf("severity = %v, want WARN", r.Severity())
+	}
+	attrs := attrMap(r)
+	if attrs["service"].AsString() != "billing" {
+		t.Errorf("service attr = %v, want billing", attrs["service"])
+	}
+	req := attrs["req"]
+	if req.Kind() != otellog.KindMap {
+		t.Fatalf("req attr kind = %v, want map", req.Kind())
+	}
+	inner := map[string]otellog.Value{}
+	for _, kv := range req.AsMap() {
+		inner[kv.Key] = kv.Value
+	}
+	if inner["status"].AsInt64() != 503 || inner["err"].AsString() != "upstream timeout" {
+		t.Errorf("req group = %v, want status=503 err=upstream timeout", req)
+	}
+}
+
+func TestSeverityMapping(t *testing.T) {
+	cases := map[slog.Level]otellog.Severity{
+		slog.LevelDebug: otellog.SeverityDebug,
+		slog.LevelInfo:  otellog.SeverityInfo,
+		slog.LevelWarn:  otellog.SeverityWarn,
+		slog.LevelError: otellog.SeverityError,
+	}
+	for lvl, want := range cases {
+		if got := severity(lvl); got != want {
+			t.Errorf("severity(%v) = %v, want %v", lvl, got, want)
+		}
+	}
+}
+
+func TestOTelHandler_EmptyGroupOmitted(t *testing.T) {
+	lp := &recordingProvider{}
+	logger := slog.New(newOTelHandler(lp, slog.LevelInfo))
+	logger.WithGroup("empty").Info("msg")
+
+	if n := lp.records[0].AttributesLen(); n != 0 {
+		t.Errorf("got %d attrs, want 0 for an empty group", n)
+	}
+}
+
+func TestWithOTelLogs_ShipsAfterProviderIsSet(t *testing.T) {
+	logger := New("info", WithOTelLogs())
+	logger.Info("before init")
+
+	lp := &recordingProvider{}
+	global.SetLoggerProvider(lp)
+	logger.Info("after init")
+
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+	if len(lp.records) != 1 || lp.records[0].Body().AsString() != "after init" {
+		t.Fatalf("records = %v, want only the one logged after the provider was set", lp.records)
+	}
+}
@@ -0,0 +1,105 @@
+package logz
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces the value of every redacted attribute.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedKeys is a starting set of attribute keys that commonly carry
+// credentials or personal data. Pass it to WithRedaction, optionally with
+// service-specific additions:
+//
+//	logz.New("info", logz.WithRedaction(append(logz.DefaultRedactedKeys, "card_number")...))
+var DefaultRedactedKeys = []string{
+	"password", "passwd", "secret", "token", "access_token", "refresh_token",
+	"authorization", "cookie", "api_key", "apikey", "ssn",
+}
+
+// WithRedaction masks the value of any attribute whose key matches one of
+// keys (case-insensitive) before it is emitted, at any group depth and for
+// attributes added via Logger.With as well as per-record ones.
+func WithRedaction(keys ...string) Option {
+	return func(o *options) {
+		for _, k := range keys {
+			o.redactKeys = append(o.redactKeys, strings.ToLower(k))
+		}
+	}
+}
+
+// WithRedactionPatterns masks every substring of a string attribute value
+// that matches one of patterns, e.g. bearer tokens or card numbers embedded
+// in free-form text.
+func WithRedactionPatterns(patterns ...*regexp.Regexp) Option {
+	return func(o *options) { o.redactPatterns = append(o.redactPatterns, patterns...) }
+}
+
+// redactHandler masks sensitive attributes before passing records on.
+type redactHandler struct {
+	inner    slog.Handler
+	keys     map[string]bool
+	patterns []*regexp.Regexp
+}
+
+func newRedactHandler(inner slog.Handler, keys []string, patterns []*regexp.Regexp) *redactHandler {
+	m := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		m[k] = true
+	}
+	return &redactHandler{inner: inner, keys: m, patterns: patterns}
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.redact(a))
+		return true
+	})
+	return h.inner.Handle(ctx, nr)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redact(a)
+	}
+	return &redactHandler{inner: h.inner.WithAttrs(redacted), keys: h.keys, patterns: h.patterns}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{inner: h.inner.WithGroup(name), keys: h.keys, patterns: h.patterns}
+}
+
+// redact returns a with sensitive values masked, recursing into groups.
+func (h *redactHandler) redact(a slog.Attr) slog.Attr {
+	if h.keys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, RedactedValue)
+	}
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			redacted[i] = h.redact(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindString:
+		if len(h.patterns) > 0 {
+			s := a.Value.String()
+			for _, re := range h.patterns {
+				s = re.ReplaceAllString(s, RedactedValue)
+			}
+			return slog.String(a.Key, s)
+		}
+	}
+	return a
+}
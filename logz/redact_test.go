@@ -0,0 +1,67 @@
+package logz
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func newRedactLogger(buf *bytes.Buffer, keys []string, patterns ...*regexp.Regexp) *slog.Logger {
+	inner := slog.NewJSONHandler(buf, nil)
+	return slog.New(newRedactHandler(&traceHandler{inner: inner, base: inner}, keys, patterns))
+}
+
+func TestRedaction_MasksKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newRedactLogger(&buf, []string{"password", "authorization"})
+
+	logger.With("Authorization", "Bearer abc").
+		WithGroup("user").
+		Info("login", "name", "alice", "password", "hunter2",
+			slog.Group("meta", "password", "nested"))
+
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "Bearer abc") || strings.Contains(out, "nested") {
+		t.Fatalf("sensitive value leaked: %s", out)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["Authorization"] != RedactedValue {
+		t.Errorf("Authorization = %v, want %s", m["Authorization"], RedactedValue)
+	}
+	user := m["user"].(map[string]any)
+	if user["name"] != "alice" || user["password"] != RedactedValue {
+		t.Errorf("user group = %v, want name kept and password redacted", user)
+	}
+}
+
+type secretValuer string
+
+func (s secretValuer) LogValue() slog.Value { return slog.StringValue("tok_" + string(s)) }
+
+func TestRedaction_Patterns(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newRedactLogger(&buf, nil, regexp.MustCompile(`tok_[a-z0-9]+`))
+
+	logger.Info("calling upstream", "detail", "used tok_abc123 for auth", "valuer", secretValuer("xyz"))
+
+	out := buf.String()
+	if strings.Contains(out, "tok_abc123") || strings.Contains(out, "tok_xyz") {
+		t.Fatalf("token leaked: %s", out)
+	}
+	if !strings.Contains(out, "used [REDACTED] for auth") {
+		t.Errorf("expected masked substring, got %s", out)
+	}
+}
+
+func TestNew_WithRedactionDefaults(t *testing.T) {
+	logger := New("info", WithRedaction(DefaultRedactedKeys...))
+	if _, ok := logger.Handler().(*redactHandler); !ok {
+		t.Fatalf("handler = %T, want *redactHandler", logger.Handler())
+	}
+}
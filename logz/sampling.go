@@ -0,0 +1,127 @@
+package logz
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SamplingConfig controls duplicate suppression for WithSampling.
+type SamplingConfig struct {
+	// First is how many records with the same level and message are emitted
+	// per Interval before further duplicates are dropped. Defaults to 10.
+	First int
+	// Interval is the window after which the per-message budget resets.
+	// Defaults to one second.
+	Interval time.Duration
+}
+
+// maxSampledKeys bounds the sampler's memory when messages are not constant
+// strings; the table is reset when it grows past this size.
+const maxSampledKeys = 10_000
+
+// WithSampling rate-limits identical (level, message) pairs so a failing
+// dependency cannot flood the log pipeline. Within each cfg.Interval the first
+// cfg.First records of a pair are emitted and the rest are dropped; the next
+// record emitted for that pair carries a "suppressed_count" attribute with
+// the number dropped in between.
+func WithSampling(cfg SamplingConfig) Option {
+	return func(o *options) { o.sampling = &cfg }
+}
+
+type sampleKey struct {
+	level slog.Level
+	msg   string
+}
+
+type sampleState struct {
+	windowStart time.Time
+	count       int
+	suppressed  int64
+}
+
+// sampler holds the counters shared by a sampling handler and all handlers
+// derived from it via WithAttrs and WithGroup.
+type sampler struct {
+	first    int
+	interval time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	state map[sampleKey]*sampleState
+}
+
+func newSampler(cfg SamplingConfig) *sampler {
+	if cfg.First <= 0 {
+		cfg.First = 10
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	return &sampler{
+		first:    cfg.First,
+		interval: cfg.Interval,
+		now:      time.Now,
+		state:    make(map[sampleKey]*sampleState),
+	}
+}
+
+// allow reports whether a record should be emitted and, if so, how many
+// duplicates were dropped since the last emitted one.
+func (s *sampler) allow(level slog.Level, msg string) (ok bool, suppressed int64) {
+	now := s.now()
+	key := sampleKey{level: level, msg: msg}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.state[key]
+	if st == nil {
+		if len(s.state) >= maxSampledKeys {
+			s.state = make(map[sampleKey]*sampleState)
+		}
+		st = &sampleState{windowStart: now}
+		s.state[key] = st
+	}
+	if now.Sub(st.windowStart) >= s.interval {
+		st.windowStart = now
+		st.count = 0
+	}
+	st.count++
+	if st.count > s.first {
+		st.suppressed++
+		return false, 0
+	}
+	suppressed, st.suppressed = st.suppressed, 0
+	return true, suppressed
+}
+
+// samplingHandler drops duplicate records according to its sampler.
+type samplingHandler struct {
+	inner   slog.Handler
+	sampler *sampler
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	ok, suppressed := h.sampler.allow(r.Level, r.Message)
+	if !ok {
+		return nil
+	}
+	if suppressed > 0 {
+		r = r.Clone()
+		r.AddAttrs(slog.Int64("suppressed_count", suppressed))
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{inner: h.inner.WithAttrs(attrs), sampler: h.sampler}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{inner: h.inner.WithGroup(name), sampler: h.sampler}
+}
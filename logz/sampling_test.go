@@ -0,0 +1,56 @@
+package logz
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSampling_SuppressesDuplicates(t *testing.T) {
+	var buf bytes.Buffer
+	inner := slog.NewJSONHandler(&buf, nil)
+	s := newSampler(SamplingConfig{First: 2, Interval: time.Minute})
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+	logger := slog.New(&samplingHandler{inner: inner, sampler: s})
+
+	for range 5 {
+		logger.Error("db unavailable")
+	}
+	logger.Warn("db unavailable") // different level — separate budget
+	logger.Error("other message")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), buf.String())
+	}
+
+	// Advance past the interval: the next record reports what was dropped.
+	now = now.Add(time.Minute)
+	buf.Reset()
+	logger.With("attempt", 6).Error("db unavailable")
+
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if m["suppressed_count"] != float64(3) {
+		t.Errorf("suppressed_count = %v, want 3", m["suppressed_count"])
+	}
+
+	buf.Reset()
+	logger.Error("db unavailable")
+	if strings.Contains(buf.String(), "suppressed_count") {
+		t.Errorf("suppressed_count should reset after being reported: %s", buf.String())
+	}
+}
+
+func TestSampling_Defaults(t *testing.T) {
+	s := newSampler(SamplingConfig{})
+	if s.first != 10 || s.interval != time.Second {
+		t.Errorf("defaults = first %d interval %v, want 10 and 1s", s.first, s.interval)
+	}
+}
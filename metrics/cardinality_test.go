@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPerMetricCardinalityLimit(t *testing.T) {
+	_ = setupTestMeter(t)
+	rec := New("limsvc", nil,
+		WithDefaultCardinalityLimit(5),
+		WithCardinalityLimit("user_events_total", 2),
+	)
+	ctx := context.Background()
+
+	users := rec.Counter("user_events_total")
+	for i := range 10 {
+		users.Add(ctx, 1, "user", fmt.Sprint(i))
+	}
+	if got := len(rec.seenCombos["user_events_total"]); got != 2 {
+		t.Errorf("user_events_total combos = %d, want 2", got)
+	}
+
+	// The exploding metric must not consume the other metrics' headroom.
+	kinds := rec.Counter("kinds_total")
+	for i := range 5 {
+		kinds.Add(ctx, 1, "kind", fmt.Sprint(i))
+	}
+	if got := len(rec.seenCombos["kinds_total"]); got != 5 {
+		t.Errorf("kinds_total combos = %d, want 5", got)
+	}
+	if rec.checkCardinality("kinds_total", "kind=overflow") {
+		t.Error("sixth kinds_total combo admitted past the default limit")
+	}
+}
+
+func TestLabelTTLEvictsStaleCombos(t *testing.T) {
+	_ = setupTestMeter(t)
+	now := time.Unix(1000, 0)
+	rec := New("ttlsvc", nil, WithCardinalityLimit("jobs_total", 2), WithLabelTTL(time.Minute))
+	rec.now = func() time.Time { return now }
+
+	if !rec.checkCardinality("jobs_total", "a") || !rec.checkCardinality("jobs_total", "b") {
+		t.Fatal("combos under the limit rejected")
+	}
+	if rec.checkCardinality("jobs_total", "c") {
+		t.Fatal("combo over the limit admitted before any TTL elapsed")
+	}
+
+	// "a" stays fresh; "b" goes stale.
+	now = now.Add(45 * time.Second)
+	rec.checkCardinality("jobs_total", "a")
+	now = now.Add(30 * time.Second)
+
+	if !rec.checkCardinality("jobs_total", "c") {
+		t.Fatal("new combo rejected after a stale one expired")
+	}
+	if _, ok := rec.seenCombos["jobs_total"]["b"]; ok {
+		t.Error("stale combo b was not evicted")
+	}
+	if _, ok := rec.seenCombos["jobs_total"]["a"]; !ok {
+		t.Error("fresh combo a was evicted")
+	}
+}
+
+func TestNoTTLNeverEvicts(t *testing.T) {
+	_ = setupTestMeter(t)
+	now := time.Unix(1000, 0)
+	rec := New("nottl", nil, WithCardinalityLimit("x", 1))
+	rec.now = func() time.Time { return now }
+
+	rec.checkCardinality("x", "a")
+	now = now.Add(24 * time.Hour)
+	if rec.checkCardinality("x", "b") {
+		t.Error("combo admitted past the limit without a TTL")
+	}
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+)
+
+// UnmatchedRoute is the route label for requests that no ServeMux pattern
+// matched, or that were served by a router which does not set
+// http.Request.Pattern.
+const UnmatchedRoute = "unmatched"
+
+// knownMethods bounds the method label; anything else is recorded as OTHER.
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true,
+	http.MethodPut: true, http.MethodPatch: true, http.MethodDelete: true,
+	http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// HTTPMiddleware returns middleware that records every request into rec,
+// replacing hand-written RecordRequest calls:
+//
+//   - {prefix}_http_requests_total{method, route, status}
+//   - {prefix}_http_request_duration_seconds{method, route}
+//   - {prefix}_http_request_size_bytes{method, route}
+//   - {prefix}_http_response_size_bytes{method, route}
+//
+// The route label is the http.ServeMux pattern that matched, such as
+// "GET /users/{id}", so paths with IDs do not explode cardinality. Wrap the
+// ServeMux directly: the pattern is read from the request after the mux has
+// served it, and middleware in between that copies the request (for example
+// with WithContext) hides it.
+func HTTPMiddleware(rec *Recorder) func(http.Handler) http.Handler {
+	chassis.AssertVersionChecked()
+	requests := rec.counter("http_requests_total", "Total number of HTTP requests.")
+	duration := rec.histogram("http_request_duration_seconds", "HTTP request duration in seconds.", DurationBuckets)
+	reqSize := rec.histogram("http_request_size_bytes", "HTTP request body size in bytes.", ContentBuckets)
+	respSize := rec.histogram("http_response_size_bytes", "HTTP response body size in bytes.", ContentBuckets)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			body := &countingBody{ReadCloser: r.Body}
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = body
+			}
+			rw := &metricsWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rw, r)
+
+			method := r.Method
+			if !knownMethods[method] {
+				method = "OTHER"
+			}
+			route := r.Pattern
+			if route == "" {
+				route = UnmatchedRoute
+			}
+			ctx := r.Context()
+			requests.Add(ctx, 1, "method", method, "route", route, "status", strconv.Itoa(rw.status))
+			duration.Observe(ctx, time.Since(start).Seconds(), "method", method, "route", route)
+			reqSize.Observe(ctx, float64(body.n), "method", method, "route", route)
+			respSize.Observe(ctx, float64(rw.n), "method", method, "route", route)
+		})
+	}
+}
+
+// countingBody counts the request body bytes read by the handler.
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// metricsWriter captures the response status and body size.
+type metricsWriter struct {
+	http.ResponseWriter
+	status        int
+	n             int64
+	headerWritten bool
+}
+
+func (w *metricsWriter) WriteHeader(code int) {
+	// Informational 1xx responses precede the final status.
+	if !w.headerWritten && code >= 200 {
+		w.status = code
+		w.headerWritten = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *metricsWriter) Write(b []byte) (int, error) {
+	w.headerWritten = true
+	n, err := w.ResponseWriter.Write(b)
+	w.n += int64(n)
+	return n, err
+}
+
+// Unwrap returns the underlying http.ResponseWriter so that
+// http.NewResponseController can reach http.Flusher and http.Hijacker.
+func (w *metricsWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
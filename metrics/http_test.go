@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestHTTPMiddlewareRecordsRoutePattern(t *testing.T) {
+	collect := setupTestMeter(t)
+	rec := New("httpsvc", nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	})
+	handler := HTTPMiddleware(rec)(mux)
+
+	for _, id := range []string{"1", "2", "3"} {
+		req := httptest.NewRequest("POST", "/users/"+id, strings.NewReader("hello"))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/nope", nil))
+
+	rm := collect()
+	m := findMetric(rm, "httpsvc_http_requests_total")
+	if m == nil {
+		t.Fatal("expected httpsvc_http_requests_total")
+	}
+	counts := map[string]float64{}
+	for _, dp := range m.Data.(metricdata.Sum[float64]).DataPoints {
+		route, _ := dp.Attributes.Value(attribute.Key("route"))
+		status, _ := dp.Attributes.Value(attribute.Key("status"))
+		counts[route.AsString()+" "+status.AsString()] += dp.Value
+	}
+	if counts["POST /users/{id} 201"] != 3 {
+		t.Errorf("route counts = %v, want 3 for POST /users/{id} 201", counts)
+	}
+	if counts[UnmatchedRoute+" 404"] != 1 {
+		t.Errorf("route counts = %v, want 1 unmatched 404", counts)
+	}
+
+	sizes := findMetric(rm, "httpsvc_http_request_size_bytes")
+	if sizes == nil {
+		t.Fatal("expected httpsvc_http_request_size_bytes")
+	}
+	for _, dp := range sizes.Data.(metricdata.Histogram[float64]).DataPoints {
+		if route, _ := dp.Attributes.Value(attribute.Key("route")); route.AsString() == "POST /users/{id}" && dp.Sum != 15 {
+			t.Errorf("request size sum = %v, want 15", dp.Sum)
+		}
+	}
+	resp := findMetric(rm, "httpsvc_http_response_size_bytes")
+	if resp == nil {
+		t.Fatal("expected httpsvc_http_response_size_bytes")
+	}
+	for _, dp := range resp.Data.(metricdata.Histogram[float64]).DataPoints {
+		if route, _ := dp.Attributes.Value(attribute.Key("route")); route.AsString() == "POST /users/{id}" && dp.Sum != 21 {
+			t.Errorf("response size sum = %v, want 21", dp.Sum)
+		}
+	}
+	if findMetric(rm, "httpsvc_http_request_duration_seconds") == nil {
+		t.Error("expected httpsvc_http_request_duration_seconds")
+	}
+}
+
+func TestHTTPMiddlewareBoundsMethodLabel(t *testing.T) {
+	collect := setupTestMeter(t)
+	rec := New("methsvc", nil)
+	handler := HTTPMiddleware(rec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("FOOBAR", "/", nil))
+
+	m := findMetric(collect(), "methsvc_http_requests_total")
+	if m == nil {
+		t.Fatal("expected methsvc_http_requests_total")
+	}
+	dp := m.Data.(metricdata.Sum[float64]).DataPoints[0]
+	if method, _ := dp.Attributes.Value(attribute.Key("method")); method.AsString() != "OTHER" {
+		t.Errorf("method = %q, want OTHER", method.AsString())
+	}
+	if status, _ := dp.Attributes.Value(attribute.Key("status")); status.AsString() != "200" {
+		t.Errorf("status = %q, want 200", status.AsString())
+	}
+}
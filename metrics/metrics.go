@@ -1,12 +1,16 @@
 // Package metrics provides OpenTelemetry metrics with cardinality protection.
-// Metrics flow out via OTLP push — there is no scrape endpoint.
+// Metrics flow out via OTLP push; otel.Config.Prometheus adds a scrape
+// endpoint serving the same instruments.
 package metrics
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	chassis "github.com/ai8future/chassis-go/v11"
 	otelapi "go.opentelemetry.io/otel"
@@ -20,12 +24,54 @@ var (
 	ContentBuckets  = []float64{100, 500, 1000, 5000, 10000, 50000, 100000, 500000, 1000000}
 )
 
-// MaxLabelCombinations is the cardinality cap per metric.
+// MaxLabelCombinations is the default cardinality cap per metric. Override
+// it with WithDefaultCardinalityLimit or WithCardinalityLimit.
 const MaxLabelCombinations = 1000
 
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithCardinalityLimit caps the label combinations of one metric, named as
+// passed to Counter or Histogram (without the prefix), or one of the
+// built-in names such as "requests_total". Give a high-cardinality metric
+// its own limit so it cannot starve the others of headroom.
+func WithCardinalityLimit(metric string, limit int) Option {
+	return func(r *Recorder) {
+		r.limits[metric] = limit
+	}
+}
+
+// WithDefaultCardinalityLimit sets the cap for metrics without their own
+// limit. Defaults to MaxLabelCombinations.
+func WithDefaultCardinalityLimit(limit int) Option {
+	return func(r *Recorder) {
+		r.defaultLimit = limit
+	}
+}
+
+// WithLabelTTL forgets label combinations that have not been recorded for
+// ttl, so that a metric at its limit admits new combinations once old ones
+// go stale instead of dropping them for the life of the process. Zero (the
+// default) never forgets. It bounds what the Recorder admits; series already
+// exported under cumulative temporality stay in the SDK until restart.
+func WithLabelTTL(ttl time.Duration) Option {
+	return func(r *Recorder) {
+		r.labelTTL = ttl
+	}
+}
+
+// WithMeterProvider records into mp instead of the global MeterProvider set
+// by otel.Init, for example a provider dedicated to one job.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(r *Recorder) {
+		r.provider = mp
+	}
+}
+
 // Recorder holds pre-registered metrics for a service.
 type Recorder struct {
 	prefix          string
+	provider        metric.MeterProvider
 	meter           metric.Meter
 	requestsTotal   metric.Float64Counter
 	requestDuration metric.Float64Histogram
@@ -33,18 +79,41 @@ type Recorder struct {
 
 	// cardinality tracking
 	mu             sync.RWMutex
-	seenCombos     map[string]map[string]struct{} // metric name → set of label combos
+	seenCombos     map[string]map[string]*atomic.Int64 // metric name → label combo → last seen (unix nanos, with a TTL)
+	nextSweep      map[string]int64                    // metric name → earliest next TTL sweep (unix nanos)
 	overflowWarned map[string]bool
 	logger         *slog.Logger
+	defaultLimit   int
+	limits         map[string]int
+	labelTTL       time.Duration
+	now            func() time.Time
+
+	runtimeOnce sync.Once
 }
 
 // New creates a Recorder with the given metric prefix and optional logger.
 // The prefix is used as the OTel meter name and prepended to metric names.
-func New(prefix string, logger *slog.Logger) *Recorder {
+func New(prefix string, logger *slog.Logger, opts ...Option) *Recorder {
 	chassis.AssertVersionChecked()
-	meter := otelapi.GetMeterProvider().Meter(prefix)
+	r := &Recorder{
+		prefix:         prefix,
+		provider:       otelapi.GetMeterProvider(),
+		seenCombos:     make(map[string]map[string]*atomic.Int64),
+		nextSweep:      make(map[string]int64),
+		overflowWarned: make(map[string]bool),
+		logger:         logger,
+		defaultLimit:   MaxLabelCombinations,
+		limits:         make(map[string]int),
+		now:            time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	meter := r.provider.Meter(prefix)
+	r.meter = meter
 
-	requestsTotal, err := meter.Float64Counter(
+	var err error
+	r.requestsTotal, err = meter.Float64Counter(
 		prefix+"_requests_total",
 		metric.WithDescription("Total number of requests."),
 	)
@@ -52,7 +121,7 @@ func New(prefix string, logger *slog.Logger) *Recorder {
 		logger.Warn("metrics: failed to create requests_total counter", "error", err)
 	}
 
-	requestDuration, err := meter.Float64Histogram(
+	r.requestDuration, err = meter.Float64Histogram(
 		prefix+"_request_duration_seconds",
 		metric.WithDescription("Request duration in seconds."),
 		metric.WithExplicitBucketBoundaries(DurationBuckets...),
@@ -61,7 +130,7 @@ func New(prefix string, logger *slog.Logger) *Recorder {
 		logger.Warn("metrics: failed to create request_duration histogram", "error", err)
 	}
 
-	contentSize, err := meter.Float64Histogram(
+	r.contentSize, err = meter.Float64Histogram(
 		prefix+"_content_size_bytes",
 		metric.WithDescription("Content size in bytes."),
 		metric.WithExplicitBucketBoundaries(ContentBuckets...),
@@ -69,17 +138,24 @@ func New(prefix string, logger *slog.Logger) *Recorder {
 	if err != nil && logger != nil {
 		logger.Warn("metrics: failed to create content_size histogram", "error", err)
 	}
+	return r
+}
 
-	return &Recorder{
-		prefix:          prefix,
-		meter:           meter,
-		requestsTotal:   requestsTotal,
-		requestDuration: requestDuration,
-		contentSize:     contentSize,
-		seenCombos:      make(map[string]map[string]struct{}),
-		overflowWarned:  make(map[string]bool),
-		logger:          logger,
+// Flush exports everything recorded so far, for jobs and CLIs that exit
+// before the next periodic export: call it (or the otel.Init ShutdownFunc)
+// before returning from main, and pair it with otel.Config.DeltaTemporality
+// so each run pushes only its own counts. Flush is a no-op if the
+// MeterProvider cannot be flushed, such as the no-op provider used before
+// otel.Init.
+func (r *Recorder) Flush(ctx context.Context) error {
+	f, ok := r.provider.(interface{ ForceFlush(context.Context) error })
+	if !ok {
+		return nil
 	}
+	if err := f.ForceFlush(ctx); err != nil {
+		return fmt.Errorf("metrics: flush: %w", err)
+	}
+	return nil
 }
 
 // RecordRequest increments request metrics with cardinality protection.
@@ -113,10 +189,18 @@ func (r *Recorder) RecordRequest(ctx context.Context, method, status string, dur
 
 // checkCardinality returns true if the combo is allowed (under limit).
 func (r *Recorder) checkCardinality(metricName, combo string) bool {
+	var now int64
+	if r.labelTTL > 0 {
+		now = r.now().UnixNano()
+	}
+
 	// Fast path: check under read lock if the combo is already known.
 	r.mu.RLock()
 	if combos, exists := r.seenCombos[metricName]; exists {
-		if _, seen := combos[combo]; seen {
+		if lastSeen, seen := combos[combo]; seen {
+			if r.labelTTL > 0 {
+				lastSeen.Store(now)
+			}
 			r.mu.RUnlock()
 			return true
 		}
@@ -127,23 +211,55 @@ func (r *Recorder) checkCardinality(metricName, combo string) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if r.seenCombos[metricName] == nil {
-		r.seenCombos[metricName] = make(map[string]struct{})
+		r.seenCombos[metricName] = make(map[string]*atomic.Int64)
 	}
 	// Re-check: another goroutine may have added this combo while we waited.
-	if _, seen := r.seenCombos[metricName][combo]; seen {
+	if lastSeen, seen := r.seenCombos[metricName][combo]; seen {
+		lastSeen.Store(now)
 		return true
 	}
-	if len(r.seenCombos[metricName]) >= MaxLabelCombinations {
-		r.warnOnceOverflowLocked(metricName)
+	limit := r.limitFor(metricName)
+	if len(r.seenCombos[metricName]) >= limit && r.labelTTL > 0 {
+		r.evictStaleLocked(metricName, now)
+	}
+	if len(r.seenCombos[metricName]) >= limit {
+		r.warnOnceOverflowLocked(metricName, limit)
 		return false
 	}
-	r.seenCombos[metricName][combo] = struct{}{}
+	lastSeen := new(atomic.Int64)
+	lastSeen.Store(now)
+	r.seenCombos[metricName][combo] = lastSeen
 	return true
 }
 
+// limitFor returns the cardinality cap for metricName.
+func (r *Recorder) limitFor(metricName string) int {
+	if limit, ok := r.limits[metricName]; ok {
+		return limit
+	}
+	return r.defaultLimit
+}
+
+// evictStaleLocked forgets metricName's combos not seen within the label
+// TTL. Sweeps run at most once per quarter TTL per metric, so a metric full
+// of live combos does not pay for a scan on every rejected one. Must be
+// called with r.mu held.
+func (r *Recorder) evictStaleLocked(metricName string, now int64) {
+	if now < r.nextSweep[metricName] {
+		return
+	}
+	r.nextSweep[metricName] = now + int64(r.labelTTL/4)
+	cutoff := now - int64(r.labelTTL)
+	for combo, lastSeen := range r.seenCombos[metricName] {
+		if lastSeen.Load() < cutoff {
+			delete(r.seenCombos[metricName], combo)
+		}
+	}
+}
+
 // warnOnceOverflowLocked logs a cardinality overflow warning once per metric.
 // Must be called with r.mu held.
-func (r *Recorder) warnOnceOverflowLocked(metricName string) {
+func (r *Recorder) warnOnceOverflowLocked(metricName string, limit int) {
 	if r.overflowWarned[metricName] {
 		return
 	}
@@ -151,7 +267,7 @@ func (r *Recorder) warnOnceOverflowLocked(metricName string) {
 	if r.logger != nil {
 		r.logger.Warn("metrics cardinality limit reached, dropping new label combinations",
 			"metric", metricName,
-			"limit", MaxLabelCombinations,
+			"limit", limit,
 		)
 	}
 }
@@ -188,10 +304,19 @@ func (h *HistogramVec) Observe(ctx context.Context, val float64, labelPairs ...s
 
 // Counter creates and registers a new counter with the given name.
 func (r *Recorder) Counter(name string) *CounterVec {
+	return r.counter(name, "Custom counter: "+name)
+}
+
+// Histogram creates and registers a new histogram with the given name and buckets.
+func (r *Recorder) Histogram(name string, buckets []float64) *HistogramVec {
+	return r.histogram(name, "Custom histogram: "+name, buckets)
+}
+
+func (r *Recorder) counter(name, description string) *CounterVec {
 	fullName := r.prefix + "_" + name
 	cv, err := r.meter.Float64Counter(
 		fullName,
-		metric.WithDescription("Custom counter: "+name),
+		metric.WithDescription(description),
 	)
 	if err != nil && r.logger != nil {
 		r.logger.Warn("metrics: failed to create counter", "name", fullName, "error", err)
@@ -199,12 +324,11 @@ func (r *Recorder) Counter(name string) *CounterVec {
 	return &CounterVec{inner: cv, name: name, recorder: r}
 }
 
-// Histogram creates and registers a new histogram with the given name and buckets.
-func (r *Recorder) Histogram(name string, buckets []float64) *HistogramVec {
+func (r *Recorder) histogram(name, description string, buckets []float64) *HistogramVec {
 	fullName := r.prefix + "_" + name
 	hv, err := r.meter.Float64Histogram(
 		fullName,
-		metric.WithDescription("Custom histogram: "+name),
+		metric.WithDescription(description),
 		metric.WithExplicitBucketBoundaries(buckets...),
 	)
 	if err != nil && r.logger != nil {
@@ -0,0 +1,220 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// CollectDBStats registers observable instruments that report db's
+// connection pool statistics, read from db.Stats at every collection, with
+// a db label set to name:
+//
+//   - {prefix}_db_open_connections, _db_in_use_connections,
+//     _db_idle_connections and _db_max_open_connections
+//   - {prefix}_db_wait_count_total and _db_wait_duration_seconds_total
+//   - {prefix}_db_closed_max_idle_total, _db_closed_max_idle_time_total and
+//     _db_closed_max_lifetime_total
+//
+// Call the returned function to stop reporting, for example when db is
+// closed.
+func CollectDBStats(rec *Recorder, db *sql.DB, name string) func() {
+	m := rec.meter
+	p := rec.prefix
+	open, err1 := m.Int64ObservableGauge(p+"_db_open_connections",
+		metric.WithDescription("Established connections, in use and idle."))
+	inUse, err2 := m.Int64ObservableGauge(p+"_db_in_use_connections",
+		metric.WithDescription("Connections currently in use."))
+	idle, err3 := m.Int64ObservableGauge(p+"_db_idle_connections",
+		metric.WithDescription("Idle connections."))
+	maxOpen, err4 := m.Int64ObservableGauge(p+"_db_max_open_connections",
+		metric.WithDescription("Maximum open connections; 0 is unlimited."))
+	waitCount, err5 := m.Int64ObservableCounter(p+"_db_wait_count_total",
+		metric.WithDescription("Connections waited for."))
+	waitDuration, err6 := m.Float64ObservableCounter(p+"_db_wait_duration_seconds_total",
+		metric.WithDescription("Total time blocked waiting for a connection."), metric.WithUnit("s"))
+	closedIdle, err7 := m.Int64ObservableCounter(p+"_db_closed_max_idle_total",
+		metric.WithDescription("Connections closed due to SetMaxIdleConns."))
+	closedIdleTime, err8 := m.Int64ObservableCounter(p+"_db_closed_max_idle_time_total",
+		metric.WithDescription("Connections closed due to SetConnMaxIdleTime."))
+	closedLifetime, err9 := m.Int64ObservableCounter(p+"_db_closed_max_lifetime_total",
+		metric.WithDescription("Connections closed due to SetConnMaxLifetime."))
+	if !rec.instrumentsOK("db stats", err1, err2, err3, err4, err5, err6, err7, err8, err9) {
+		return func() {}
+	}
+
+	attrs := metric.WithAttributes(attribute.String("db", name))
+	reg, err := m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s := db.Stats()
+		o.ObserveInt64(open, int64(s.OpenConnections), attrs)
+		o.ObserveInt64(inUse, int64(s.InUse), attrs)
+		o.ObserveInt64(idle, int64(s.Idle), attrs)
+		o.ObserveInt64(maxOpen, int64(s.MaxOpenConnections), attrs)
+		o.ObserveInt64(waitCount, s.WaitCount, attrs)
+		o.ObserveFloat64(waitDuration, s.WaitDuration.Seconds(), attrs)
+		o.ObserveInt64(closedIdle, s.MaxIdleClosed, attrs)
+		o.ObserveInt64(closedIdleTime, s.MaxIdleTimeClosed, attrs)
+		o.ObserveInt64(closedLifetime, s.MaxLifetimeClosed, attrs)
+		return nil
+	}, open, inUse, idle, maxOpen, waitCount, waitDuration, closedIdle, closedIdleTime, closedLifetime)
+	return rec.unregisterFunc(reg, err, "db stats")
+}
+
+// CollectTransportStats instruments t's connection pool, which net/http does
+// not expose, and reports it with a pool label set to name:
+//
+//   - {prefix}_http_client_open_connections, connections dialled and not
+//     yet closed
+//   - {prefix}_http_client_in_use_connections, connections serving a
+//     request whose response body is still open
+//   - {prefix}_http_client_dials_total and
+//     _http_client_reused_connections_total
+//
+// It wraps t.DialContext, so call it before t is first used and do not set
+// t.DialTLSContext. Send requests through the returned RoundTripper for the
+// in-use and reuse counts. Call the returned function to stop reporting.
+func CollectTransportStats(rec *Recorder, t *http.Transport, name string) (http.RoundTripper, func()) {
+	st := &transportStats{next: t}
+	dial := t.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		st.dials.Add(1)
+		st.open.Add(1)
+		return &countedConn{Conn: conn, open: &st.open}, nil
+	}
+
+	m := rec.meter
+	p := rec.prefix
+	open, err1 := m.Int64ObservableGauge(p+"_http_client_open_connections",
+		metric.WithDescription("Open HTTP client connections."))
+	inUse, err2 := m.Int64ObservableGauge(p+"_http_client_in_use_connections",
+		metric.WithDescription("HTTP client connections serving a request."))
+	dials, err3 := m.Int64ObservableCounter(p+"_http_client_dials_total",
+		metric.WithDescription("HTTP client connections dialled."))
+	reused, err4 := m.Int64ObservableCounter(p+"_http_client_reused_connections_total",
+		metric.WithDescription("Requests served on a reused connection."))
+	if !rec.instrumentsOK("transport stats", err1, err2, err3, err4) {
+		return st, func() {}
+	}
+
+	attrs := metric.WithAttributes(attribute.String("pool", name))
+	reg, err := m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(open, st.open.Load(), attrs)
+		o.ObserveInt64(inUse, st.inUse.Load(), attrs)
+		o.ObserveInt64(dials, st.dials.Load(), attrs)
+		o.ObserveInt64(reused, st.reused.Load(), attrs)
+		return nil
+	}, open, inUse, dials, reused)
+	return st, rec.unregisterFunc(reg, err, "transport stats")
+}
+
+// transportStats counts connection pool activity for one transport.
+type transportStats struct {
+	next   http.RoundTripper
+	open   atomic.Int64
+	inUse  atomic.Int64
+	dials  atomic.Int64
+	reused atomic.Int64
+}
+
+// RoundTrip counts the connection as in use from when it is obtained until
+// the response body is closed or fully read.
+func (s *transportStats) RoundTrip(req *http.Request) (*http.Response, error) {
+	var got atomic.Bool
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if got.CompareAndSwap(false, true) {
+				s.inUse.Add(1)
+			}
+			if info.Reused {
+				s.reused.Add(1)
+			}
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	resp, err := s.next.RoundTrip(req)
+	release := func() {
+		if got.CompareAndSwap(true, false) {
+			s.inUse.Add(-1)
+		}
+	}
+	if err != nil || resp.Body == nil || resp.Body == http.NoBody {
+		release()
+		return resp, err
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releaseBody calls release once the body is closed or read to the end.
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.release()
+	}
+	return n, err
+}
+
+func (b *releaseBody) Close() error {
+	b.release()
+	return b.ReadCloser.Close()
+}
+
+// countedConn decrements open when the connection is closed.
+type countedConn struct {
+	net.Conn
+	open *atomic.Int64
+	once sync.Once
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(func() { c.open.Add(-1) })
+	return c.Conn.Close()
+}
+
+// instrumentsOK logs the first instrument creation error, if any.
+func (r *Recorder) instrumentsOK(what string, errs ...error) bool {
+	for _, err := range errs {
+		if err != nil {
+			if r.logger != nil {
+				r.logger.Warn("metrics: failed to create "+what+" instrument", "error", err)
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// unregisterFunc returns a function that unregisters reg, logging a failed
+// registration.
+func (r *Recorder) unregisterFunc(reg metric.Registration, err error, what string) func() {
+	if err != nil {
+		if r.logger != nil {
+			r.logger.Warn("metrics: failed to register "+what+" callback", "error", err)
+		}
+		return func() {}
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() { _ = reg.Unregister() })
+	}
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// fakeDriver opens connections that support nothing but Close, which is
+// enough for database/sql to pool them.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func init() {
+	sql.Register("metrics-fake", fakeDriver{})
+}
+
+// gaugeValue returns the value of an int64 gauge data point.
+func gaugeValue(t *testing.T, m *metricdata.Metrics) int64 {
+	t.Helper()
+	points := m.Data.(metricdata.Gauge[int64]).DataPoints
+	if len(points) != 1 {
+		t.Fatalf("%s: %d data points, want 1", m.Name, len(points))
+	}
+	return points[0].Value
+}
+
+func TestCollectDBStats(t *testing.T) {
+	collect := setupTestMeter(t)
+	rec := New("dbsvc", nil)
+
+	db, err := sql.Open("metrics-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(4)
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stop := CollectDBStats(rec, db, "primary")
+	rm := collect()
+	for name, want := range map[string]int64{
+		"dbsvc_db_open_connections":     1,
+		"dbsvc_db_in_use_connections":   1,
+		"dbsvc_db_idle_connections":     0,
+		"dbsvc_db_max_open_connections": 4,
+	} {
+		m := findMetric(rm, name)
+		if m == nil {
+			t.Errorf("expected %s", name)
+			continue
+		}
+		if got := gaugeValue(t, m); got != want {
+			t.Errorf("%s = %d, want %d", name, got, want)
+		}
+	}
+	if findMetric(rm, "dbsvc_db_wait_duration_seconds_total") == nil {
+		t.Error("expected dbsvc_db_wait_duration_seconds_total")
+	}
+
+	stop()
+	stop() // idempotent
+	if m := findMetric(collect(), "dbsvc_db_open_connections"); m != nil && len(m.Data.(metricdata.Gauge[int64]).DataPoints) != 0 {
+		t.Error("stats still reported after stop")
+	}
+}
+
+func TestCollectTransportStats(t *testing.T) {
+	collect := setupTestMeter(t)
+	rec := New("tpsvc", nil)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	transport := &http.Transport{}
+	rt, stop := CollectTransportStats(rec, transport, "upstream")
+	defer stop()
+	client := &http.Client{Transport: rt}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := collect()
+	if got := gaugeValue(t, findMetric(rm, "tpsvc_http_client_in_use_connections")); got != 1 {
+		t.Errorf("in use with open body = %d, want 1", got)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	resp, err = client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	rm = collect()
+	if got := gaugeValue(t, findMetric(rm, "tpsvc_http_client_in_use_connections")); got != 0 {
+		t.Errorf("in use after bodies closed = %d, want 0", got)
+	}
+	if got := gaugeValue(t, findMetric(rm, "tpsvc_http_client_open_connections")); got != 1 {
+		t.Errorf("open connections = %d, want 1", got)
+	}
+	sum := func(name string) int64 {
+		m := findMetric(rm, name)
+		if m == nil {
+			t.Fatalf("expected %s", name)
+		}
+		return m.Data.(metricdata.Sum[int64]).DataPoints[0].Value
+	}
+	if got := sum("tpsvc_http_client_dials_total"); got != 1 {
+		t.Errorf("dials = %d, want 1", got)
+	}
+	if got := sum("tpsvc_http_client_reused_connections_total"); got != 1 {
+		t.Errorf("reused = %d, want 1", got)
+	}
+
+	transport.CloseIdleConnections()
+	if got := gaugeValue(t, findMetric(collect(), "tpsvc_http_client_open_connections")); got != 0 {
+		t.Errorf("open connections after CloseIdleConnections = %d, want 0", got)
+	}
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ai8future/chassis-go/v11/call"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// breakerStates are the states reported by CollectBreakerStats, in the
+// order their series are observed.
+var breakerStates = []call.State{call.StateClosed, call.StateOpen, call.StateHalfOpen}
+
+// CollectBreakerStats reports cb's state with a breaker label set to
+// cb.Name():
+//
+//   - {prefix}_circuit_breaker_state{breaker, state}, 1 for the current
+//     state ("closed", "open" or "half-open") and 0 for the others, so
+//     open breakers can be found with
+//     svc_circuit_breaker_state{state="open"} == 1
+//   - {prefix}_circuit_breaker_transitions_total{breaker, from, to}
+//   - {prefix}_circuit_breaker_rejections_total{breaker}, requests the
+//     breaker rejected
+//   - {prefix}_circuit_breaker_opens_total{breaker}, times it opened,
+//     including by ForceOpen
+//
+// The last two read cb.Stats, so they count from the breaker's creation.
+// Call the returned function to stop reporting.
+func CollectBreakerStats(rec *Recorder, cb *call.CircuitBreaker) func() {
+	transitions := rec.counter("circuit_breaker_transitions_total", "Circuit breaker state transitions.")
+	stopTransitions := cb.OnStateChange(func(from, to call.State) {
+		transitions.Add(context.Background(), 1,
+			"breaker", cb.Name(), "from", from.String(), "to", to.String())
+	})
+
+	state, err1 := rec.meter.Int64ObservableGauge(rec.prefix+"_circuit_breaker_state",
+		metric.WithDescription("Circuit breaker state; 1 for the current state, 0 otherwise."))
+	rejections, err2 := rec.meter.Int64ObservableCounter(rec.prefix+"_circuit_breaker_rejections_total",
+		metric.WithDescription("Requests rejected by an open circuit breaker."))
+	opens, err3 := rec.meter.Int64ObservableCounter(rec.prefix+"_circuit_breaker_opens_total",
+		metric.WithDescription("Times a circuit breaker opened."))
+	if !rec.instrumentsOK("breaker stats", errors.Join(err1, err2, err3)) {
+		return stopTransitions
+	}
+	name := attribute.String("breaker", cb.Name())
+	reg, err := rec.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stats := cb.Stats()
+		for _, s := range breakerStates {
+			var v int64
+			if s == stats.State {
+				v = 1
+			}
+			o.ObserveInt64(state, v, metric.WithAttributes(name, attribute.String("state", s.String())))
+		}
+		o.ObserveInt64(rejections, int64(stats.Rejected), metric.WithAttributes(name))
+		o.ObserveInt64(opens, int64(stats.Opened), metric.WithAttributes(name))
+		return nil
+	}, state, rejections, opens)
+	unregister := rec.unregisterFunc(reg, err, "breaker stats")
+	return func() {
+		stopTransitions()
+		unregister()
+	}
+}
+
+// CountRateLimitRejections returns a guard.RateLimitConfig.OnReject hook
+// that increments {prefix}_rate_limit_rejections_total{limiter} with the
+// limiter label set to name:
+//
+//	guard.RateLimit(guard.RateLimitConfig{
+//	    Rate: 100, Window: time.Minute, KeyFunc: guard.RemoteAddr(), MaxKeys: 10000,
+//	    OnReject: metrics.CountRateLimitRejections(rec, "api"),
+//	})
+func CountRateLimitRejections(rec *Recorder, name string) func(*http.Request) {
+	rejections := rec.counter("rate_limit_rejections_total", "Requests rejected by a rate limiter.")
+	return func(r *http.Request) {
+		rejections.Add(r.Context(), 1, "limiter", name)
+	}
+}
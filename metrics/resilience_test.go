@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ai8future/chassis-go/v11/call"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestCollectBreakerStats(t *testing.T) {
+	collect := setupTestMeter(t)
+	rec := New("cbsvc", nil)
+
+	name := fmt.Sprintf("metrics-test-%d", time.Now().UnixNano())
+	defer call.RemoveBreaker(name)
+	cb := call.GetBreaker(name, 1, time.Hour)
+	stop := CollectBreakerStats(rec, cb)
+	defer stop()
+
+	cb.Record(false) // closed -> open
+
+	rm := collect()
+	m := findMetric(rm, "cbsvc_circuit_breaker_state")
+	if m == nil {
+		t.Fatal("expected cbsvc_circuit_breaker_state")
+	}
+	states := map[string]int64{}
+	for _, dp := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+		if b, _ := dp.Attributes.Value("breaker"); b.AsString() != name {
+			t.Fatalf("breaker label = %q, want %q", b.AsString(), name)
+		}
+		s, _ := dp.Attributes.Value("state")
+		states[s.AsString()] = dp.Value
+	}
+	if states["open"] != 1 || states["closed"] != 0 || states["half-open"] != 0 {
+		t.Fatalf("state gauge = %v, want only open set", states)
+	}
+
+	m = findMetric(rm, "cbsvc_circuit_breaker_transitions_total")
+	if m == nil {
+		t.Fatal("expected cbsvc_circuit_breaker_transitions_total")
+	}
+	points := m.Data.(metricdata.Sum[float64]).DataPoints
+	if len(points) != 1 || points[0].Value != 1 {
+		t.Fatalf("transitions = %+v, want one closed->open", points)
+	}
+	if to, _ := points[0].Attributes.Value("to"); to.AsString() != "open" {
+		t.Fatalf("to = %q, want open", to.AsString())
+	}
+
+	stop()
+	if findMetric(collect(), "cbsvc_circuit_breaker_state") != nil {
+		t.Fatal("state gauge still reported after stop")
+	}
+}
+
+func TestCollectBreakerStats_RejectionsAndOpens(t *testing.T) {
+	collect := setupTestMeter(t)
+	rec := New("cbrej", nil)
+
+	name := fmt.Sprintf("metrics-test-%d", time.Now().UnixNano())
+	defer call.RemoveBreaker(name)
+	cb := call.GetBreaker(name, 1, time.Hour)
+	stop := CollectBreakerStats(rec, cb)
+	defer stop()
+
+	cb.ForceOpen()
+	for range 3 {
+		cb.Allow()
+	}
+
+	rm := collect()
+	for metricName, want := range map[string]int64{
+		"cbrej_circuit_breaker_rejections_total": 3,
+		"cbrej_circuit_breaker_opens_total":      1,
+	} {
+		m := findMetric(rm, metricName)
+		if m == nil {
+			t.Fatalf("expected %s", metricName)
+		}
+		points := m.Data.(metricdata.Sum[int64]).DataPoints
+		if len(points) != 1 || points[0].Value != want {
+			t.Fatalf("%s = %+v, want %d", metricName, points, want)
+		}
+		if b, _ := points[0].Attributes.Value("breaker"); b.AsString() != name {
+			t.Fatalf("%s breaker label = %q, want %q", metricName, b.AsString(), name)
+		}
+	}
+}
+
+func TestCountRateLimitRejections(t *testing.T) {
+	collect := setupTestMeter(t)
+	rec := New("rlsvc", nil)
+
+	onReject := CountRateLimitRejections(rec, "api")
+	for i := 0; i < 3; i++ {
+		onReject(httptest.NewRequest("GET", "/", nil))
+	}
+
+	m := findMetric(collect(), "rlsvc_rate_limit_rejections_total")
+	if m == nil {
+		t.Fatal("expected rlsvc_rate_limit_rejections_total")
+	}
+	points := m.Data.(metricdata.Sum[float64]).DataPoints
+	if len(points) != 1 || points[0].Value != 3 {
+		t.Fatalf("rejections = %+v, want 3", points)
+	}
+	if l, _ := points[0].Attributes.Value("limiter"); l.AsString() != "api" {
+		t.Fatalf("limiter = %q, want api", l.AsString())
+	}
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"runtime/debug"
+	rtmetrics "runtime/metrics"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// runtimeSamples are the runtime/metrics readings behind the runtime gauges.
+var runtimeSamples = []string{
+	"/sched/goroutines:goroutines",
+	"/memory/classes/heap/objects:bytes",
+	"/gc/heap/objects:objects",
+	"/memory/classes/total:bytes",
+	"/gc/cycles/total:gc-cycles",
+}
+
+// EnableRuntimeMetrics registers observable instruments that report Go
+// runtime and process statistics at every collection:
+//
+//   - {prefix}_runtime_goroutines
+//   - {prefix}_runtime_heap_alloc_bytes and {prefix}_runtime_heap_objects
+//   - {prefix}_runtime_memory_total_bytes, all memory mapped by the runtime
+//   - {prefix}_runtime_gc_cycles_total and {prefix}_runtime_gc_pause_seconds_total
+//   - {prefix}_process_open_fds (Linux only)
+//   - {prefix}_process_cpu_seconds_total, user plus system time (Unix only)
+//
+// Readings that are unavailable on the platform are omitted. Calling it
+// more than once has no further effect.
+//
+// otel.Config.RuntimeMetrics exports the same readings, and more, under the
+// OpenTelemetry go.* names; prefer it for new services and enable only one
+// of the two, since both report goroutines, heap and GC.
+func (r *Recorder) EnableRuntimeMetrics() {
+	r.runtimeOnce.Do(r.registerRuntimeMetrics)
+}
+
+func (r *Recorder) registerRuntimeMetrics() {
+	goroutines, err1 := r.meter.Int64ObservableGauge(r.prefix+"_runtime_goroutines",
+		metric.WithDescription("Number of live goroutines."))
+	heapAlloc, err2 := r.meter.Int64ObservableGauge(r.prefix+"_runtime_heap_alloc_bytes",
+		metric.WithDescription("Bytes of allocated heap objects."), metric.WithUnit("By"))
+	heapObjects, err3 := r.meter.Int64ObservableGauge(r.prefix+"_runtime_heap_objects",
+		metric.WithDescription("Number of allocated heap objects."))
+	memTotal, err4 := r.meter.Int64ObservableGauge(r.prefix+"_runtime_memory_total_bytes",
+		metric.WithDescription("Bytes of memory mapped by the Go runtime."), metric.WithUnit("By"))
+	gcCycles, err5 := r.meter.Int64ObservableCounter(r.prefix+"_runtime_gc_cycles_total",
+		metric.WithDescription("Completed GC cycles."))
+	gcPause, err6 := r.meter.Float64ObservableCounter(r.prefix+"_runtime_gc_pause_seconds_total",
+		metric.WithDescription("Total stop-the-world GC pause time in seconds."), metric.WithUnit("s"))
+	openFDs, err7 := r.meter.Int64ObservableGauge(r.prefix+"_process_open_fds",
+		metric.WithDescription("Number of open file descriptors."))
+	cpu, err8 := r.meter.Float64ObservableCounter(r.prefix+"_process_cpu_seconds_total",
+		metric.WithDescription("User and system CPU time in seconds."), metric.WithUnit("s"))
+	if !r.instrumentsOK("runtime", err1, err2, err3, err4, err5, err6, err7, err8) {
+		return
+	}
+
+	samples := make([]rtmetrics.Sample, len(runtimeSamples))
+	for i, name := range runtimeSamples {
+		samples[i].Name = name
+	}
+	var gcStats debug.GCStats
+
+	_, err := r.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		rtmetrics.Read(samples)
+		for i, inst := range []metric.Int64Observable{goroutines, heapAlloc, heapObjects, memTotal, gcCycles} {
+			if samples[i].Value.Kind() == rtmetrics.KindUint64 {
+				o.ObserveInt64(inst, int64(samples[i].Value.Uint64()))
+			}
+		}
+		debug.ReadGCStats(&gcStats)
+		o.ObserveFloat64(gcPause, gcStats.PauseTotal.Seconds())
+		if n, ok := countOpenFDs(); ok {
+			o.ObserveInt64(openFDs, int64(n))
+		}
+		if secs, ok := cpuSeconds(); ok {
+			o.ObserveFloat64(cpu, secs)
+		}
+		return nil
+	}, goroutines, heapAlloc, heapObjects, memTotal, gcCycles, gcPause, openFDs, cpu)
+	if err != nil && r.logger != nil {
+		r.logger.Warn("metrics: failed to register runtime metrics callback", "error", err)
+	}
+}
+
+// countOpenFDs counts the entries in /proc/self/fd. It reports false where
+// procfs is unavailable.
+func countOpenFDs() (int, bool) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0, false
+	}
+	// ReadDir holds one descriptor open on the directory itself.
+	return len(entries) - 1, true
+}
@@ -0,0 +1,6 @@
+//go:build !unix
+
+package metrics
+
+// cpuSeconds is unavailable on this platform.
+func cpuSeconds() (float64, bool) { return 0, false }
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"runtime"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestEnableRuntimeMetrics(t *testing.T) {
+	collect := setupTestMeter(t)
+	rec := New("rtsvc", nil)
+	rec.EnableRuntimeMetrics()
+	rec.EnableRuntimeMetrics() // second call is a no-op
+	runtime.GC()
+
+	rm := collect()
+	for _, name := range []string{
+		"rtsvc_runtime_goroutines",
+		"rtsvc_runtime_heap_alloc_bytes",
+		"rtsvc_runtime_heap_objects",
+		"rtsvc_runtime_memory_total_bytes",
+		"rtsvc_runtime_gc_cycles_total",
+		"rtsvc_runtime_gc_pause_seconds_total",
+	} {
+		if findMetric(rm, name) == nil {
+			t.Errorf("expected %s in collected metrics", name)
+		}
+	}
+
+	g := findMetric(rm, "rtsvc_runtime_goroutines")
+	if g == nil {
+		t.Fatal("goroutine gauge missing")
+	}
+	points := g.Data.(metricdata.Gauge[int64]).DataPoints
+	if len(points) != 1 || points[0].Value < 1 {
+		t.Errorf("goroutine data points = %+v, want one positive value", points)
+	}
+
+	if runtime.GOOS == "linux" {
+		if findMetric(rm, "rtsvc_process_open_fds") == nil {
+			t.Error("expected rtsvc_process_open_fds on linux")
+		}
+		if findMetric(rm, "rtsvc_process_cpu_seconds_total") == nil {
+			t.Error("expected rtsvc_process_cpu_seconds_total on linux")
+		}
+	}
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package metrics
+
+import "syscall"
+
+// cpuSeconds returns the process's user plus system CPU time.
+func cpuSeconds() (float64, bool) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, false
+	}
+	return tvSeconds(ru.Utime) + tvSeconds(ru.Stime), true
+}
+
+func tvSeconds(tv syscall.Timeval) float64 {
+	return float64(tv.Sec) + float64(tv.Usec)/1e6
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Objective defines a service level objective: Target is the fraction of
+// events that must be good, and Latency, if set, is the threshold a
+// successful event must meet to count as good. {Target: 0.999, Latency:
+// 300 * time.Millisecond} reads "99.9% of requests succeed within 300ms".
+type Objective struct {
+	Target  float64
+	Latency time.Duration
+}
+
+// SLO records good and bad events against an Objective. Create one with
+// Recorder.SLO.
+type SLO struct {
+	name   string
+	obj    Objective
+	events *CounterVec
+}
+
+// SLO returns an SLO named name that records into
+// {prefix}_slo_events_total{slo, outcome} with outcome "good" or "bad",
+// and publishes the target as {prefix}_slo_objective_ratio{slo}. These are
+// the two series standard burn-rate alerts need, for example over 1h:
+//
+//	sum(rate(svc_slo_events_total{slo="checkout",outcome="bad"}[1h]))
+//	  / sum(rate(svc_slo_events_total{slo="checkout"}[1h]))
+//	  / (1 - svc_slo_objective_ratio{slo="checkout"})
+//
+// A result of 1 burns the error budget exactly over the SLO window; page on
+// 14.4 over 1h and 5m together for a 30-day window. SLO panics if Target is
+// not between 0 and 1.
+func (r *Recorder) SLO(name string, obj Objective) *SLO {
+	if obj.Target <= 0 || obj.Target >= 1 {
+		panic(fmt.Sprintf("metrics: SLO %q target must be between 0 and 1, got %v", name, obj.Target))
+	}
+	s := &SLO{
+		name:   name,
+		obj:    obj,
+		events: r.counter("slo_events_total", "SLO events by outcome."),
+	}
+
+	objective, err := r.meter.Float64ObservableGauge(r.prefix+"_slo_objective_ratio",
+		metric.WithDescription("SLO target as a fraction of good events."))
+	if !r.instrumentsOK("slo", err) {
+		return s
+	}
+	attrs := metric.WithAttributes(attribute.String("slo", name))
+	_, err = r.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveFloat64(objective, obj.Target, attrs)
+		return nil
+	}, objective)
+	if err != nil && r.logger != nil {
+		r.logger.Warn("metrics: failed to register slo callback", "slo", name, "error", err)
+	}
+	return s
+}
+
+// Record classifies one event: it is good if err is nil and, when the
+// Objective sets Latency, duration does not exceed it.
+func (s *SLO) Record(ctx context.Context, duration time.Duration, err error) {
+	s.Observe(ctx, err == nil && (s.obj.Latency == 0 || duration <= s.obj.Latency))
+}
+
+// Observe records an event the caller has already classified.
+func (s *SLO) Observe(ctx context.Context, good bool) {
+	outcome := "bad"
+	if good {
+		outcome = "good"
+	}
+	s.events.Add(ctx, 1, "slo", s.name, "outcome", outcome)
+}
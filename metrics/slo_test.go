@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestSLORecordsGoodAndBadEvents(t *testing.T) {
+	collect := setupTestMeter(t)
+	rec := New("slosvc", nil)
+	slo := rec.SLO("checkout", Objective{Target: 0.999, Latency: 300 * time.Millisecond})
+
+	ctx := context.Background()
+	slo.Record(ctx, 100*time.Millisecond, nil)           // good
+	slo.Record(ctx, 300*time.Millisecond, nil)           // good: at threshold
+	slo.Record(ctx, 301*time.Millisecond, nil)           // bad: too slow
+	slo.Record(ctx, 10*time.Millisecond, errors.New("")) // bad: failed
+	slo.Observe(ctx, true)
+
+	rm := collect()
+	m := findMetric(rm, "slosvc_slo_events_total")
+	if m == nil {
+		t.Fatal("expected slosvc_slo_events_total")
+	}
+	counts := map[string]float64{}
+	for _, dp := range m.Data.(metricdata.Sum[float64]).DataPoints {
+		if name, _ := dp.Attributes.Value(attribute.Key("slo")); name.AsString() != "checkout" {
+			t.Errorf("slo label = %q", name.AsString())
+		}
+		outcome, _ := dp.Attributes.Value(attribute.Key("outcome"))
+		counts[outcome.AsString()] = dp.Value
+	}
+	if counts["good"] != 3 || counts["bad"] != 2 {
+		t.Errorf("counts = %v, want good=3 bad=2", counts)
+	}
+
+	obj := findMetric(rm, "slosvc_slo_objective_ratio")
+	if obj == nil {
+		t.Fatal("expected slosvc_slo_objective_ratio")
+	}
+	if v := obj.Data.(metricdata.Gauge[float64]).DataPoints[0].Value; v != 0.999 {
+		t.Errorf("objective = %v, want 0.999", v)
+	}
+}
+
+func TestSLOAvailabilityOnly(t *testing.T) {
+	collect := setupTestMeter(t)
+	slo := New("availsvc", nil).SLO("api", Objective{Target: 0.99})
+	slo.Record(context.Background(), time.Hour, nil)
+
+	// Without a latency threshold, slow successes are good.
+	m := findMetric(collect(), "availsvc_slo_events_total")
+	if m == nil {
+		t.Fatal("expected availsvc_slo_events_total")
+	}
+	dp := m.Data.(metricdata.Sum[float64]).DataPoints[0]
+	if outcome, _ := dp.Attributes.Value(attribute.Key("outcome")); outcome.AsString() != "good" {
+		t.Errorf("outcome = %q, want good", outcome.AsString())
+	}
+}
+
+func TestSLOInvalidTargetPanics(t *testing.T) {
+	_ = setupTestMeter(t)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for target 1")
+		}
+	}()
+	New("badslo", nil).SLO("x", Objective{Target: 1})
+}
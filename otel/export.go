@@ -0,0 +1,305 @@
+package otel
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	apimetric "go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ExportConfig tunes how telemetry is batched and pushed over OTLP. Zero
+// fields keep the SDK defaults.
+type ExportConfig struct {
+	// MaxQueueSize bounds the spans, and separately the log records,
+	// waiting to be exported (default 2048, or OTEL_BSP_MAX_QUEUE_SIZE and
+	// OTEL_BLRP_MAX_QUEUE_SIZE). Once it is full, new ones are dropped and
+	// counted in otel.exporter.dropped{reason="queue_full"}; raise it to
+	// ride out longer collector outages.
+	MaxQueueSize int
+	// MaxBatchSize is the most spans or log records sent in one export
+	// (default 512).
+	MaxBatchSize int
+	// BatchTimeout is the longest a span or log record waits before its
+	// batch is exported (defaults 5s for spans, 1s for logs).
+	BatchTimeout time.Duration
+	// Timeout bounds each export, retries included (default 10s).
+	Timeout time.Duration
+	// MetricInterval is how often metrics are pushed (default 60s).
+	MetricInterval time.Duration
+	// Retry configures how failed exports are retried. Nil keeps the OTLP
+	// default: exponential backoff from 5s up to 30s, for at most 1m.
+	Retry *RetryConfig
+}
+
+// RetryConfig configures the retrying of failed OTLP exports. Zero
+// durations keep the defaults.
+type RetryConfig struct {
+	// Disabled drops a batch as soon as its export fails.
+	Disabled bool
+	// InitialInterval is the wait after the first failure (default 5s).
+	InitialInterval time.Duration
+	// MaxInterval caps the backoff between retries (default 30s).
+	MaxInterval time.Duration
+	// MaxElapsedTime is how long a batch is retried before it is dropped
+	// (default 1m).
+	MaxElapsedTime time.Duration
+}
+
+// retrySettings mirrors the layout of the OTLP exporters' RetryConfig
+// types, so it converts to each of them.
+type retrySettings struct {
+	Enabled         bool
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	MaxElapsedTime  time.Duration
+}
+
+// settings returns r with defaults filled in.
+func (r RetryConfig) settings() retrySettings {
+	s := retrySettings{
+		Enabled:         !r.Disabled,
+		InitialInterval: 5 * time.Second,
+		MaxInterval:     30 * time.Second,
+		MaxElapsedTime:  time.Minute,
+	}
+	if r.InitialInterval > 0 {
+		s.InitialInterval = r.InitialInterval
+	}
+	if r.MaxInterval > 0 {
+		s.MaxInterval = r.MaxInterval
+	}
+	if r.MaxElapsedTime > 0 {
+		s.MaxElapsedTime = r.MaxElapsedTime
+	}
+	return s
+}
+
+// validate panics if any field is negative.
+func (e ExportConfig) validate() {
+	if e.MaxQueueSize < 0 || e.MaxBatchSize < 0 || e.BatchTimeout < 0 || e.Timeout < 0 || e.MetricInterval < 0 {
+		panic("otel: Config.Export fields must not be negative")
+	}
+	if r := e.Retry; r != nil && (r.InitialInterval < 0 || r.MaxInterval < 0 || r.MaxElapsedTime < 0) {
+		panic("otel: Config.Export.Retry durations must not be negative")
+	}
+}
+
+func (e ExportConfig) spanBatchOptions() []sdktrace.BatchSpanProcessorOption {
+	var opts []sdktrace.BatchSpanProcessorOption
+	if e.MaxQueueSize > 0 {
+		opts = append(opts, sdktrace.WithMaxQueueSize(e.MaxQueueSize))
+	}
+	if e.MaxBatchSize > 0 {
+		opts = append(opts, sdktrace.WithMaxExportBatchSize(e.MaxBatchSize))
+	}
+	if e.BatchTimeout > 0 {
+		opts = append(opts, sdktrace.WithBatchTimeout(e.BatchTimeout))
+	}
+	if e.Timeout > 0 {
+		opts = append(opts, sdktrace.WithExportTimeout(e.Timeout))
+	}
+	return opts
+}
+
+func (e ExportConfig) logBatchOptions() []sdklog.BatchProcessorOption {
+	var opts []sdklog.BatchProcessorOption
+	if e.MaxQueueSize > 0 {
+		opts = append(opts, sdklog.WithMaxQueueSize(e.MaxQueueSize))
+	}
+	if e.MaxBatchSize > 0 {
+		opts = append(opts, sdklog.WithExportMaxBatchSize(e.MaxBatchSize))
+	}
+	if e.BatchTimeout > 0 {
+		opts = append(opts, sdklog.WithExportInterval(e.BatchTimeout))
+	}
+	if e.Timeout > 0 {
+		opts = append(opts, sdklog.WithExportTimeout(e.Timeout))
+	}
+	return opts
+}
+
+func (e ExportConfig) readerOptions() []metric.PeriodicReaderOption {
+	var opts []metric.PeriodicReaderOption
+	if e.MetricInterval > 0 {
+		opts = append(opts, metric.WithInterval(e.MetricInterval))
+	}
+	if e.Timeout > 0 {
+		opts = append(opts, metric.WithTimeout(e.Timeout))
+	}
+	return opts
+}
+
+// dropLogInterval is the least time between two warnings about dropped
+// telemetry for one signal.
+const dropLogInterval = time.Minute
+
+// Reasons reported in the reason attribute of otel.exporter.dropped.
+const (
+	dropExportFailed = "export_failed" // the export failed after its retries
+	dropQueueFull    = "queue_full"    // the export queue was full
+)
+
+// dropReporter counts and logs telemetry of one signal lost to failed
+// exports or a full export queue, which the SDK otherwise reports nowhere
+// by default.
+type dropReporter struct {
+	signal   attribute.KeyValue
+	counter  atomic.Pointer[apimetric.Int64Counter] // set once a MeterProvider exists
+	lastLog  atomic.Int64                           // UnixNano of the last warning
+	unlogged atomic.Int64                           // drops since the last warning
+}
+
+func newDropReporter(signal string) *dropReporter {
+	return &dropReporter{signal: attribute.String("otel.signal", signal)}
+}
+
+// setMeterProvider starts counting drops in otel.exporter.dropped on mp.
+func (r *dropReporter) setMeterProvider(mp apimetric.MeterProvider) {
+	c, err := mp.Meter(scopeName).Int64Counter("otel.exporter.dropped",
+		apimetric.WithUnit("{item}"),
+		apimetric.WithDescription("Spans, metric streams and log records dropped because an OTLP export failed or the export queue was full"))
+	if err == nil {
+		r.counter.Store(&c)
+	}
+}
+
+// report records n items dropped for reason, with the export error if there
+// was one, and logs a warning at most once per dropLogInterval.
+func (r *dropReporter) report(n int, reason string, err error) {
+	if c := r.counter.Load(); c != nil {
+		(*c).Add(context.Background(), int64(n), apimetric.WithAttributes(r.signal, attribute.String("reason", reason)))
+	}
+	total := r.unlogged.Add(int64(n))
+	now, last := time.Now().UnixNano(), r.lastLog.Load()
+	if now-last < int64(dropLogInterval) || !r.lastLog.CompareAndSwap(last, now) {
+		return
+	}
+	r.unlogged.Add(-total)
+	attrs := []any{"signal", r.signal.Value.AsString(), "dropped", total, "reason", reason}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	slog.Warn("otel: telemetry dropped", attrs...)
+}
+
+// exportQueue bounds the items of one signal waiting to be exported, so
+// that overflow is counted: the batch processor drops silently when its own
+// queue is full. Items are admitted in front of the processor and released
+// as batches reach the exporter; since the processor's queue never holds
+// more than the admitted items, it never overflows itself.
+type exportQueue struct {
+	size    int64
+	pending atomic.Int64
+	drops   *dropReporter
+}
+
+// newExportQueue returns an exportQueue of size items, or of the size in
+// the environment variable env, or of 2048, the SDK default.
+func newExportQueue(size int, env string, drops *dropReporter) *exportQueue {
+	if size <= 0 {
+		size = 2048
+		if n, err := strconv.Atoi(os.Getenv(env)); err == nil && n > 0 {
+			size = n
+		}
+	}
+	return &exportQueue{size: int64(size), drops: drops}
+}
+
+// admit reserves room for one item, or counts it as dropped and reports
+// false when the queue is full.
+func (q *exportQueue) admit() bool {
+	if q.pending.Add(1) > q.size {
+		q.pending.Add(-1)
+		q.drops.report(1, dropQueueFull, nil)
+		return false
+	}
+	return true
+}
+
+// release frees the room of n items handed to the exporter.
+func (q *exportQueue) release(n int) {
+	q.pending.Add(-int64(n))
+}
+
+// queuedSpanProcessor admits sampled spans to the batch processor through
+// queue; the batch processor ignores unsampled ones.
+type queuedSpanProcessor struct {
+	sdktrace.SpanProcessor
+	queue *exportQueue
+}
+
+func (p queuedSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() || p.queue.admit() {
+		p.SpanProcessor.OnEnd(s)
+	}
+}
+
+// queuedLogProcessor admits log records to the batch processor through
+// queue.
+type queuedLogProcessor struct {
+	sdklog.Processor
+	queue *exportQueue
+}
+
+func (p queuedLogProcessor) OnEmit(ctx context.Context, r *sdklog.Record) error {
+	if !p.queue.admit() {
+		return nil
+	}
+	return p.Processor.OnEmit(ctx, r)
+}
+
+type reportingSpanExporter struct {
+	sdktrace.SpanExporter
+	drops *dropReporter
+	queue *exportQueue
+}
+
+func (e reportingSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.queue.release(len(spans))
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if err != nil {
+		e.drops.report(len(spans), dropExportFailed, err)
+	}
+	return err
+}
+
+type reportingMetricExporter struct {
+	metric.Exporter
+	drops *dropReporter
+}
+
+func (e reportingMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	if err != nil {
+		n := 0
+		for _, sm := range rm.ScopeMetrics {
+			n += len(sm.Metrics)
+		}
+		e.drops.report(n, dropExportFailed, err)
+	}
+	return err
+}
+
+type reportingLogExporter struct {
+	sdklog.Exporter
+	drops *dropReporter
+	queue *exportQueue
+}
+
+func (e reportingLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.queue.release(len(records))
+	err := e.Exporter.Export(ctx, records)
+	if err != nil {
+		e.drops.report(len(records), dropExportFailed, err)
+	}
+	return err
+}
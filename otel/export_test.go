@@ -0,0 +1,91 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDropReporterCountsEveryDropAndLogsOnce(t *testing.T) {
+	reader := metric.NewManualReader()
+	r := newDropReporter("logs")
+	r.setMeterProvider(metric.NewMeterProvider(metric.WithReader(reader)))
+
+	errExport := errors.New("collector unavailable")
+	r.report(3, dropExportFailed, errExport)
+	first := r.lastLog.Load()
+	if first == 0 || r.unlogged.Load() != 0 {
+		t.Fatalf("first drop not logged: lastLog=%d unlogged=%d", first, r.unlogged.Load())
+	}
+	r.report(2, dropExportFailed, errExport)
+	if r.lastLog.Load() != first || r.unlogged.Load() != 2 {
+		t.Fatalf("second drop within %v logged again: unlogged=%d", dropLogInterval, r.unlogged.Load())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	if v, _ := sum.DataPoints[0].Attributes.Value("otel.signal"); v.AsString() != "logs" || sum.DataPoints[0].Value != 5 {
+		t.Fatalf("otel.exporter.dropped = %d %v, want 5 for logs", sum.DataPoints[0].Value, sum.DataPoints[0].Attributes)
+	}
+}
+
+type countingSpanProcessor struct {
+	sdktrace.SpanProcessor
+	ended int
+}
+
+func (p *countingSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) { p.ended++ }
+
+func TestExportQueueCountsOverflow(t *testing.T) {
+	reader := metric.NewManualReader()
+	drops := newDropReporter("traces")
+	drops.setMeterProvider(metric.NewMeterProvider(metric.WithReader(reader)))
+	queue := newExportQueue(2, "OTEL_BSP_MAX_QUEUE_SIZE", drops)
+	next := &countingSpanProcessor{}
+	p := queuedSpanProcessor{next, queue}
+
+	sampled := tracetest.SpanStub{SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	})}.Snapshot()
+	unsampled := tracetest.SpanStub{}.Snapshot()
+
+	for range 3 {
+		p.OnEnd(sampled)
+	}
+	p.OnEnd(unsampled) // not queued by the batch processor, so never dropped
+	queue.release(2)   // a batch reached the exporter
+	p.OnEnd(sampled)
+
+	if next.ended != 4 {
+		t.Fatalf("processor saw %d spans, want 4", next.ended)
+	}
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	dp := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints[0]
+	if v, _ := dp.Attributes.Value("reason"); v.AsString() != dropQueueFull || dp.Value != 1 {
+		t.Fatalf("otel.exporter.dropped = %d %v, want 1 queue_full", dp.Value, dp.Attributes)
+	}
+}
+
+func TestExportQueueSizeFromEnvironment(t *testing.T) {
+	t.Setenv("OTEL_BLRP_MAX_QUEUE_SIZE", "5")
+	if q := newExportQueue(0, "OTEL_BLRP_MAX_QUEUE_SIZE", nil); q.size != 5 {
+		t.Errorf("size = %d, want 5 from the environment", q.size)
+	}
+	if q := newExportQueue(7, "OTEL_BLRP_MAX_QUEUE_SIZE", nil); q.size != 7 {
+		t.Errorf("size = %d, want the configured 7", q.size)
+	}
+}
@@ -0,0 +1,192 @@
+package otel
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// OTLP transport protocols for Config.Protocol, named as in the
+// OTEL_EXPORTER_OTLP_PROTOCOL environment variable.
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http/protobuf"
+)
+
+// Default collector endpoints for each protocol.
+const (
+	defaultGRPCEndpoint = "localhost:4317"
+	defaultHTTPEndpoint = "localhost:4318"
+)
+
+// signalEndpoint returns the endpoint for one signal: its own if set, else
+// the shared Config.Endpoint.
+func signalEndpoint(own, shared string) string {
+	if own != "" {
+		return own
+	}
+	return shared
+}
+
+// signalHeaders merges a signal's own headers over the shared ones.
+func signalHeaders(own, shared map[string]string) map[string]string {
+	if len(own) == 0 {
+		return shared
+	}
+	merged := maps.Clone(shared)
+	if merged == nil {
+		merged = make(map[string]string, len(own))
+	}
+	maps.Copy(merged, own)
+	return merged
+}
+
+// isURL reports whether endpoint is a full URL rather than host:port.
+func isURL(endpoint string) bool {
+	return strings.Contains(endpoint, "://")
+}
+
+// newTraceExporter builds the OTLP span exporter for cfg, whose Protocol and
+// Endpoint have already been defaulted.
+func newTraceExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	endpoint := signalEndpoint(cfg.TracesEndpoint, cfg.Endpoint)
+	headers := signalHeaders(cfg.TracesHeaders, cfg.Headers)
+	if cfg.Protocol == ProtocolHTTP {
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+		if isURL(endpoint) {
+			opts = []otlptracehttp.Option{otlptracehttp.WithEndpointURL(endpoint)}
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(headers))
+		}
+		if cfg.Export.Timeout > 0 {
+			opts = append(opts, otlptracehttp.WithTimeout(cfg.Export.Timeout))
+		}
+		if r := cfg.Export.Retry; r != nil {
+			opts = append(opts, otlptracehttp.WithRetry(otlptracehttp.RetryConfig(r.settings())))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if isURL(endpoint) {
+		opts = []otlptracegrpc.Option{otlptracegrpc.WithEndpointURL(endpoint)}
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if len(headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(headers))
+	}
+	if cfg.Export.Timeout > 0 {
+		opts = append(opts, otlptracegrpc.WithTimeout(cfg.Export.Timeout))
+	}
+	if r := cfg.Export.Retry; r != nil {
+		opts = append(opts, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig(r.settings())))
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// newMetricExporter builds the OTLP metric exporter for cfg, whose Protocol
+// and Endpoint have already been defaulted.
+func newMetricExporter(ctx context.Context, cfg Config) (metric.Exporter, error) {
+	endpoint := signalEndpoint(cfg.MetricsEndpoint, cfg.Endpoint)
+	headers := signalHeaders(cfg.MetricsHeaders, cfg.Headers)
+	if cfg.Protocol == ProtocolHTTP {
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(endpoint)}
+		if isURL(endpoint) {
+			opts = []otlpmetrichttp.Option{otlpmetrichttp.WithEndpointURL(endpoint)}
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if len(headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(headers))
+		}
+		if cfg.DeltaTemporality {
+			opts = append(opts, otlpmetrichttp.WithTemporalitySelector(deltaTemporality))
+		}
+		if cfg.Export.Timeout > 0 {
+			opts = append(opts, otlpmetrichttp.WithTimeout(cfg.Export.Timeout))
+		}
+		if r := cfg.Export.Retry; r != nil {
+			opts = append(opts, otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig(r.settings())))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(endpoint)}
+	if isURL(endpoint) {
+		opts = []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpointURL(endpoint)}
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if len(headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(headers))
+	}
+	if cfg.DeltaTemporality {
+		opts = append(opts, otlpmetricgrpc.WithTemporalitySelector(deltaTemporality))
+	}
+	if cfg.Export.Timeout > 0 {
+		opts = append(opts, otlpmetricgrpc.WithTimeout(cfg.Export.Timeout))
+	}
+	if r := cfg.Export.Retry; r != nil {
+		opts = append(opts, otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig(r.settings())))
+	}
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// newLogExporter builds the OTLP log exporter for cfg, whose Protocol and
+// Endpoint have already been defaulted.
+func newLogExporter(ctx context.Context, cfg Config) (sdklog.Exporter, error) {
+	endpoint := signalEndpoint(cfg.LogsEndpoint, cfg.Endpoint)
+	headers := signalHeaders(cfg.LogsHeaders, cfg.Headers)
+	if cfg.Protocol == ProtocolHTTP {
+		opts := []otlploghttp.Option{otlploghttp.WithEndpoint(endpoint)}
+		if isURL(endpoint) {
+			opts = []otlploghttp.Option{otlploghttp.WithEndpointURL(endpoint)}
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if len(headers) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(headers))
+		}
+		if cfg.Export.Timeout > 0 {
+			opts = append(opts, otlploghttp.WithTimeout(cfg.Export.Timeout))
+		}
+		if r := cfg.Export.Retry; r != nil {
+			opts = append(opts, otlploghttp.WithRetry(otlploghttp.RetryConfig(r.settings())))
+		}
+		return otlploghttp.New(ctx, opts...)
+	}
+	opts := []otlploggrpc.Option{otlploggrpc.WithEndpoint(endpoint)}
+	if isURL(endpoint) {
+		opts = []otlploggrpc.Option{otlploggrpc.WithEndpointURL(endpoint)}
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+	if len(headers) > 0 {
+		opts = append(opts, otlploggrpc.WithHeaders(headers))
+	}
+	if cfg.Export.Timeout > 0 {
+		opts = append(opts, otlploggrpc.WithTimeout(cfg.Export.Timeout))
+	}
+	if r := cfg.Export.Retry; r != nil {
+		opts = append(opts, otlploggrpc.WithRetry(otlploggrpc.RetryConfig(r.settings())))
+	}
+	return otlploggrpc.New(ctx, opts...)
+}
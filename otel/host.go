@@ -0,0 +1,13 @@
+package otel
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/host"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// startHostMetrics starts the OpenTelemetry host instrumentation on mp:
+// this process's CPU time and the host's CPU time, memory and network I/O,
+// read at each collection.
+func startHostMetrics(mp metric.MeterProvider) error {
+	return host.Start(host.WithMeterProvider(mp))
+}
@@ -1,4 +1,4 @@
-// Package otel bootstraps OpenTelemetry trace and metric pipelines for
+// Package otel bootstraps OpenTelemetry trace, metric and log pipelines for
 // chassis-go services. It is the sole SDK consumer — all other chassis
 // modules depend only on OTel API packages.
 package otel
@@ -7,17 +7,20 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
+	"strconv"
 	"time"
 
 	chassis "github.com/ai8future/chassis-go/v11"
+	otelruntime "go.opentelemetry.io/contrib/instrumentation/runtime"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -25,9 +28,117 @@ import (
 type Config struct {
 	ServiceName    string
 	ServiceVersion string
-	Endpoint       string           // OTLP gRPC endpoint, defaults to localhost:4317
-	Sampler        sdktrace.Sampler // defaults to AlwaysSample
+	Endpoint       string           // OTLP endpoint, defaults to localhost:4317 (gRPC) or localhost:4318 (HTTP)
+	Sampler        sdktrace.Sampler // defaults to AlwaysSample; see Sampling for rules
 	Insecure       bool             // when true, disables TLS for OTLP connections
+
+	// Protocol is the OTLP transport: ProtocolGRPC (the default) or
+	// ProtocolHTTP, which many SaaS backends require.
+	Protocol string
+
+	// Headers are sent with every OTLP export, typically an API key such
+	// as {"x-honeycomb-team": key} or {"Authorization": "Basic ..."}.
+	Headers map[string]string
+
+	// TracesEndpoint and MetricsEndpoint override Endpoint for one signal,
+	// for backends that ingest traces and metrics at different addresses.
+	// Like Endpoint, each is host:port, or a full URL such as
+	// "https://otlp.example.com/v1/traces" when the path is not the OTLP
+	// default; the URL's scheme then decides TLS unless Insecure is set.
+	TracesEndpoint  string
+	MetricsEndpoint string
+
+	// TracesHeaders and MetricsHeaders are sent with one signal's exports,
+	// in addition to Headers; on a name clash they win.
+	TracesHeaders  map[string]string
+	MetricsHeaders map[string]string
+
+	// Logs enables the log pipeline: Init sets the global LoggerProvider to
+	// one that exports over OTLP the records from loggers built with
+	// logz.WithOTelLogs. Without it no log exporter is built and such
+	// loggers write to stderr only.
+	Logs bool
+
+	// LogsEndpoint and LogsHeaders do the same as TracesEndpoint and
+	// TracesHeaders for the log pipeline.
+	LogsEndpoint string
+	LogsHeaders  map[string]string
+
+	// Prometheus additionally exposes every metric for scraping through
+	// PrometheusHandler, alongside the OTLP push, for teams migrating
+	// between the two.
+	Prometheus bool
+
+	// PrometheusAddr, such as ":9464", implies Prometheus and also serves
+	// PrometheusHandler at /metrics on its own listener, for services with
+	// no admin mux to mount it on. The ShutdownFunc closes the listener.
+	PrometheusAddr string
+
+	// Views customise how metrics are exported, by both OTLP and
+	// Prometheus: bucket boundaries, names and attributes per instrument.
+	// Build them with HistogramBuckets, RenameMetric, DropMetricAttributes
+	// and DropMetric, or metric.NewView. Views matching the same instrument
+	// combine into one stream: later views win on name and aggregation, and
+	// every attribute filter applies.
+	Views []metric.View
+
+	// RuntimeMetrics exports Go runtime metrics: goroutines, heap and GC,
+	// scheduler latency and GOMAXPROCS, as go.* instruments, through the
+	// OpenTelemetry runtime instrumentation. Prefer it to
+	// metrics.Recorder.EnableRuntimeMetrics, which reports the same readings
+	// under the recorder's prefix for dashboards built on those names;
+	// enable only one of the two.
+	RuntimeMetrics bool
+
+	// HostMetrics exports this process's CPU time and the host's CPU time,
+	// memory usage and network I/O, as process.cpu.time and system.*
+	// instruments, through the OpenTelemetry host instrumentation. Inside a
+	// container, the system.* values describe the node, not the
+	// container's limits.
+	HostMetrics bool
+
+	// Export tunes batching, queueing, timeouts and retries of the OTLP
+	// exports. Whatever the settings, telemetry lost to a failed export or
+	// a full export queue is counted in otel.exporter.dropped{otel.signal,
+	// reason} and logged as a warning at most once a minute per signal.
+	Export ExportConfig
+
+	// SpanProcessors receive every span alongside the exporting processor,
+	// in order and before it, for span-to-log mirroring or enrichment in
+	// OnStart. The TracerProvider shuts them down with itself.
+	SpanProcessors []sdktrace.SpanProcessor
+
+	// WrapSpanExport wraps the processor that batches and exports spans,
+	// so the wrapper decides which spans reach the exporter and in what
+	// form: tail sampling, or scrubbing PII from finished spans. The
+	// wrapper must pass OnEnd, Shutdown and ForceFlush on to the processor
+	// it was given.
+	WrapSpanExport func(sdktrace.SpanProcessor) sdktrace.SpanProcessor
+
+	// ShutdownHooks run first when the ShutdownFunc is called, in order,
+	// while the providers still export, so they can stop producers and
+	// record final telemetry. Each gets the same 5s bound as a provider,
+	// and their errors are returned with the providers'.
+	ShutdownHooks []func(context.Context) error
+
+	// DetectResources adds attributes describing where the service runs to
+	// every span and metric: host.name, container.id, the Kubernetes pod,
+	// namespace and node (KubernetesDetector), the cloud provider, platform
+	// and region (CloudDetector), and OTEL_RESOURCE_ATTRIBUTES. Detection
+	// reads only local files and environment variables.
+	DetectResources bool
+
+	// ResourceDetectors run after the built-in ones, for attributes they
+	// do not cover, such as the contrib EC2 or GCE detectors.
+	ResourceDetectors []resource.Detector
+
+	// DeltaTemporality exports counters and histograms over OTLP as deltas
+	// since the previous export instead of running totals. Use it for jobs
+	// and CLIs: each run pushes only what it recorded, so runs aggregate
+	// in the backend without resets being mistaken for restarts. Call the
+	// ShutdownFunc (or metrics.Recorder.Flush) before exiting so the final
+	// interval is pushed. The Prometheus endpoint is always cumulative.
+	DeltaTemporality bool
 }
 
 // ShutdownFunc drains and closes all OTel providers.
@@ -43,49 +154,65 @@ func RatioSample(fraction float64) sdktrace.Sampler {
 	return sdktrace.TraceIDRatioBased(fraction)
 }
 
-// Init initializes OpenTelemetry trace and metric pipelines.
+// Init initializes OpenTelemetry trace and metric pipelines, and the log
+// pipeline when Config.Logs is set.
 // Returns a ShutdownFunc that must be called on process exit.
+// Panics if Protocol is neither ProtocolGRPC nor ProtocolHTTP.
 func Init(cfg Config) ShutdownFunc {
 	chassis.AssertVersionChecked()
 
-	if cfg.Endpoint == "" {
-		cfg.Endpoint = "localhost:4317"
+	switch cfg.Protocol {
+	case "", ProtocolGRPC:
+		cfg.Protocol = ProtocolGRPC
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = defaultGRPCEndpoint
+		}
+	case ProtocolHTTP:
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = defaultHTTPEndpoint
+		}
+	default:
+		panic("otel: Config.Protocol must be \"grpc\" or \"http/protobuf\", got " + strconv.Quote(cfg.Protocol))
 	}
 	if cfg.Sampler == nil {
 		cfg.Sampler = sdktrace.AlwaysSample()
 	}
+	cfg.Export.validate()
 
 	ctx := context.Background()
 
-	res, resErr := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName(cfg.ServiceName),
-			semconv.ServiceVersion(cfg.ServiceVersion),
-		),
-	)
+	res, resErr := resource.New(ctx, resourceOptions(cfg)...)
 	if resErr != nil {
-		slog.Warn("otel: resource creation failed, using default", "error", resErr)
-		res = resource.Default()
+		// res still holds everything that was detected; a failing
+		// detector leaves out only its own attributes.
+		slog.Warn("otel: resource detection incomplete", "error", resErr)
 	}
 
 	// --- Trace pipeline ---
-	traceOpts := []otlptracegrpc.Option{
-		otlptracegrpc.WithEndpoint(cfg.Endpoint),
-	}
-	if cfg.Insecure {
-		traceOpts = append(traceOpts, otlptracegrpc.WithInsecure())
-	}
-	traceExporter, err := otlptracegrpc.New(ctx, traceOpts...)
+	traceExporter, err := newTraceExporter(ctx, cfg)
 	if err != nil {
 		slog.Error("otel: trace exporter creation failed, all telemetry disabled", "error", err)
-		return func(ctx context.Context) error { return nil }
+		return shutdownAll(cfg.ShutdownHooks)
 	}
 
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(traceExporter),
+	traceDrops := newDropReporter("traces")
+	spanQueue := newExportQueue(cfg.Export.MaxQueueSize, "OTEL_BSP_MAX_QUEUE_SIZE", traceDrops)
+	var spanProcessor sdktrace.SpanProcessor = queuedSpanProcessor{sdktrace.NewBatchSpanProcessor(
+		reportingSpanExporter{traceExporter, traceDrops, spanQueue}, cfg.Export.spanBatchOptions()...), spanQueue}
+	if cfg.WrapSpanExport != nil {
+		spanProcessor = cfg.WrapSpanExport(spanProcessor)
+	}
+	if keepsErrorSpans(cfg.Sampler) {
+		spanProcessor = errorSpanProcessor{spanProcessor}
+	}
+	tpOpts := []sdktrace.TracerProviderOption{
 		sdktrace.WithResource(res),
 		sdktrace.WithSampler(cfg.Sampler),
-	)
+	}
+	for _, sp := range cfg.SpanProcessors {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(sp))
+	}
+	tp := sdktrace.NewTracerProvider(append(tpOpts, sdktrace.WithSpanProcessor(spanProcessor))...)
 
 	otel.SetTracerProvider(tp)
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
@@ -94,40 +221,98 @@ func Init(cfg Config) ShutdownFunc {
 	))
 
 	// --- Metric pipeline ---
-	metricOpts := []otlpmetricgrpc.Option{
-		otlpmetricgrpc.WithEndpoint(cfg.Endpoint),
+	var readers []metric.Option
+	metricDrops := newDropReporter("metrics")
+	metricExporter, err := newMetricExporter(ctx, cfg)
+	if err != nil {
+		slog.Warn("otel: metric exporter creation failed, OTLP metrics disabled", "error", err)
+	} else {
+		reader := metric.NewPeriodicReader(reportingMetricExporter{metricExporter, metricDrops}, cfg.Export.readerOptions()...)
+		readers = append(readers, metric.WithReader(reader))
 	}
-	if cfg.Insecure {
-		metricOpts = append(metricOpts, otlpmetricgrpc.WithInsecure())
+	shutdowns := append(slices.Clone(cfg.ShutdownHooks), tp.Shutdown)
+	if cfg.Prometheus || cfg.PrometheusAddr != "" {
+		if reader, err := newPrometheusReader(); err != nil {
+			slog.Warn("otel: prometheus exporter creation failed, scrape endpoint disabled", "error", err)
+		} else {
+			readers = append(readers, metric.WithReader(reader))
+			if cfg.PrometheusAddr != "" {
+				if stop, err := servePrometheus(cfg.PrometheusAddr); err != nil {
+					slog.Warn("otel: prometheus listener failed, scrape endpoint disabled", "addr", cfg.PrometheusAddr, "error", err)
+				} else {
+					shutdowns = append(shutdowns, stop)
+				}
+			}
+		}
 	}
-	metricExporter, err := otlpmetricgrpc.New(ctx, metricOpts...)
-	if err != nil {
-		slog.Warn("otel: metric exporter creation failed, metrics disabled", "error", err)
-		return func(ctx context.Context) error {
-			shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-			defer cancel()
-			return tp.Shutdown(shutdownCtx)
+	if len(readers) > 0 {
+		opts := append(readers, metric.WithResource(res))
+		if len(cfg.Views) > 0 {
+			opts = append(opts, metric.WithView(mergeViews(cfg.Views)))
+		}
+		mp := metric.NewMeterProvider(opts...)
+		otel.SetMeterProvider(mp)
+		shutdowns = append(shutdowns, mp.Shutdown)
+		traceDrops.setMeterProvider(mp)
+		metricDrops.setMeterProvider(mp)
+		if cfg.RuntimeMetrics {
+			if err := otelruntime.Start(otelruntime.WithMeterProvider(mp)); err != nil {
+				slog.Warn("otel: runtime metrics failed to start", "error", err)
+			}
+		}
+		if cfg.HostMetrics {
+			if err := startHostMetrics(mp); err != nil {
+				slog.Warn("otel: host metrics failed to start", "error", err)
+			}
 		}
 	}
 
-	mp := metric.NewMeterProvider(
-		metric.WithReader(metric.NewPeriodicReader(metricExporter)),
-		metric.WithResource(res),
-	)
+	// --- Log pipeline ---
+	if !cfg.Logs {
+		return shutdownAll(shutdowns)
+	}
+	logExporter, err := newLogExporter(ctx, cfg)
+	if err != nil {
+		slog.Warn("otel: log exporter creation failed, OTLP logs disabled", "error", err)
+	} else {
+		logDrops := newDropReporter("logs")
+		logDrops.setMeterProvider(otel.GetMeterProvider())
+		logQueue := newExportQueue(cfg.Export.MaxQueueSize, "OTEL_BLRP_MAX_QUEUE_SIZE", logDrops)
+		processor := sdklog.NewBatchProcessor(reportingLogExporter{logExporter, logDrops, logQueue}, cfg.Export.logBatchOptions()...)
+		lp := sdklog.NewLoggerProvider(
+			sdklog.WithProcessor(queuedLogProcessor{processor, logQueue}),
+			sdklog.WithResource(res),
+		)
+		global.SetLoggerProvider(lp)
+		shutdowns = append(shutdowns, lp.Shutdown)
+	}
 
-	otel.SetMeterProvider(mp)
+	return shutdownAll(shutdowns)
+}
 
+// shutdownAll returns a ShutdownFunc that calls each shutdown in order,
+// bounding each by 5s, and joins their errors.
+func shutdownAll(shutdowns []func(context.Context) error) ShutdownFunc {
 	return func(ctx context.Context) error {
-		tCtx, tCancel := context.WithTimeout(ctx, 5*time.Second)
-		defer tCancel()
-		tErr := tp.Shutdown(tCtx)
-
-		mCtx, mCancel := context.WithTimeout(ctx, 5*time.Second)
-		defer mCancel()
-		mErr := mp.Shutdown(mCtx)
+		var errs []error
+		for _, shutdown := range shutdowns {
+			sCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			errs = append(errs, shutdown(sCtx))
+			cancel()
+		}
+		return errors.Join(errs...)
+	}
+}
 
-		return errors.Join(tErr, mErr)
+// deltaTemporality selects delta temporality for counters and histograms.
+// Up-down counters stay cumulative: their deltas cannot be summed back into
+// a meaningful current value.
+func deltaTemporality(kind metric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case metric.InstrumentKindUpDownCounter, metric.InstrumentKindObservableUpDownCounter:
+		return metricdata.CumulativeTemporality
 	}
+	return metricdata.DeltaTemporality
 }
 
 // DetachContext returns a new context.Background() populated with the OTel
@@ -3,12 +3,23 @@ package otel_test
 
 import (
 	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	chassis "github.com/ai8future/chassis-go/v11"
 	"github.com/ai8future/chassis-go/v11/otel"
+	otelapi "go.opentelemetry.io/otel"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -165,3 +176,212 @@ func TestInitWithCustomSampler(t *testing.T) {
 		t.Fatalf("shutdown returned unexpected error: %v", err)
 	}
 }
+
+func TestInit_DeltaTemporality(t *testing.T) {
+	chassis.ResetVersionCheck()
+	chassis.RequireMajor(11)
+
+	shutdown := otel.Init(otel.Config{
+		ServiceName:      "test-delta",
+		ServiceVersion:   "1.0.0",
+		Insecure:         true,
+		DeltaTemporality: true,
+	})
+	if shutdown == nil {
+		t.Fatal("Init returned nil shutdown function")
+	}
+	if err := shutdownWithShortTimeout(t, shutdown); err != nil && !isCollectorUnavailable(err) {
+		t.Fatalf("shutdown returned unexpected error: %v", err)
+	}
+}
+
+func TestInit_HTTPProtocolExportsEverySignal(t *testing.T) {
+	chassis.ResetVersionCheck()
+	chassis.RequireMajor(11)
+
+	var mu sync.Mutex
+	got := map[string]http.Header{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got[r.URL.Path] = r.Header.Clone()
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer srv.Close()
+
+	shutdown := otel.Init(otel.Config{
+		ServiceName:     "test-http",
+		ServiceVersion:  "1.0.0",
+		Protocol:        otel.ProtocolHTTP,
+		Endpoint:        strings.TrimPrefix(srv.URL, "http://"),
+		MetricsEndpoint: srv.URL + "/custom/metrics",
+		Insecure:        true,
+		Headers:         map[string]string{"X-Api-Key": "shared", "X-Dataset": "all"},
+		TracesHeaders:   map[string]string{"X-Dataset": "traces"},
+		Logs:            true,
+		LogsEndpoint:    srv.URL + "/custom/logs",
+	})
+	_, span := otelapi.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	counter, _ := otelapi.Meter("test").Int64Counter("test.http.count")
+	counter.Add(context.Background(), 1)
+	var rec otellog.Record
+	rec.SetBody(otellog.StringValue("hello"))
+	global.GetLoggerProvider().Logger("test").Emit(context.Background(), rec)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	traces, metrics, logs := got["/v1/traces"], got["/custom/metrics"], got["/custom/logs"]
+	if traces == nil || metrics == nil || logs == nil {
+		t.Fatalf("exports received at %v, want /v1/traces, /custom/metrics and /custom/logs", slices.Collect(maps.Keys(got)))
+	}
+	if traces.Get("X-Api-Key") != "shared" || traces.Get("X-Dataset") != "traces" {
+		t.Fatalf("trace headers = %v", traces)
+	}
+	if metrics.Get("X-Api-Key") != "shared" || metrics.Get("X-Dataset") != "all" {
+		t.Fatalf("metric headers = %v", metrics)
+	}
+	if logs.Get("X-Api-Key") != "shared" {
+		t.Fatalf("log headers = %v", logs)
+	}
+}
+
+func TestInit_UnknownProtocolPanics(t *testing.T) {
+	chassis.ResetVersionCheck()
+	chassis.RequireMajor(11)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for unknown protocol")
+		}
+	}()
+	otel.Init(otel.Config{ServiceName: "test", Protocol: "http/json"})
+}
+
+func TestInit_FailedExportsAreCounted(t *testing.T) {
+	chassis.ResetVersionCheck()
+	chassis.RequireMajor(11)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	shutdown := otel.Init(otel.Config{
+		ServiceName:    "test-drops",
+		ServiceVersion: "1.0.0",
+		Protocol:       otel.ProtocolHTTP,
+		Endpoint:       srv.URL,
+		Prometheus:     true,
+		Export: otel.ExportConfig{
+			BatchTimeout: 10 * time.Millisecond,
+			Timeout:      time.Second,
+			Retry:        &otel.RetryConfig{Disabled: true},
+		},
+	})
+	defer shutdownWithShortTimeout(t, shutdown)
+
+	for range 3 {
+		_, span := otelapi.Tracer("test").Start(context.Background(), "op")
+		span.End()
+	}
+
+	want := `otel_signal="traces",reason="export_failed"} 3`
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec := httptest.NewRecorder()
+		otel.PrometheusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		if strings.Contains(rec.Body.String(), want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("scrape output missing %s:\n%s", want, rec.Body)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestInit_NegativeExportSettingPanics(t *testing.T) {
+	chassis.ResetVersionCheck()
+	chassis.RequireMajor(11)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for negative MaxQueueSize")
+		}
+	}()
+	otel.Init(otel.Config{ServiceName: "test", Export: otel.ExportConfig{MaxQueueSize: -1}})
+}
+
+// nameFilter passes to next only spans whose name is not drop.
+type nameFilter struct {
+	sdktrace.SpanProcessor
+	drop string
+}
+
+func (f nameFilter) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.Name() != f.drop {
+		f.SpanProcessor.OnEnd(s)
+	}
+}
+
+func TestInit_SpanProcessorsAndShutdownHooks(t *testing.T) {
+	chassis.ResetVersionCheck()
+	chassis.RequireMajor(11)
+
+	var mu sync.Mutex
+	exported := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/traces" {
+			mu.Lock()
+			exported++
+			mu.Unlock()
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer srv.Close()
+
+	mirror := tracetest.NewSpanRecorder()
+	var hookSawProvider bool
+	errHook := errors.New("hook failed")
+	shutdown := otel.Init(otel.Config{
+		ServiceName:    "test-processors",
+		ServiceVersion: "1.0.0",
+		Protocol:       otel.ProtocolHTTP,
+		Endpoint:       srv.URL,
+		SpanProcessors: []sdktrace.SpanProcessor{mirror},
+		WrapSpanExport: func(next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+			return nameFilter{next, "secret"}
+		},
+		ShutdownHooks: []func(context.Context) error{func(ctx context.Context) error {
+			_, span := otelapi.Tracer("test").Start(ctx, "final")
+			span.End()
+			hookSawProvider = span.SpanContext().IsValid()
+			return errHook
+		}},
+	})
+	_, span := otelapi.Tracer("test").Start(context.Background(), "secret")
+	span.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); !errors.Is(err, errHook) {
+		t.Fatalf("shutdown error = %v, want it to include the hook's", err)
+	}
+	if !hookSawProvider {
+		t.Error("shutdown hook ran after the tracer provider was shut down")
+	}
+	if got := len(mirror.Ended()); got != 2 {
+		t.Errorf("extra processor saw %d spans, want 2", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if exported != 1 {
+		t.Errorf("trace exports = %d, want 1 (final only; secret filtered by the wrapper)", exported)
+	}
+}
@@ -0,0 +1,66 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+// promHandler serves the registry of the most recent Prometheus reader.
+var promHandler atomic.Pointer[http.Handler]
+
+// newPrometheusReader returns a metric reader that exports into a fresh
+// registry and publishes that registry to PrometheusHandler.
+func newPrometheusReader() (metric.Reader, error) {
+	reg := prometheus.NewRegistry()
+	reader, err := otelprom.New(otelprom.WithRegisterer(reg))
+	if err != nil {
+		return nil, err
+	}
+	h := promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+	promHandler.Store(&h)
+	return reader, nil
+}
+
+// PrometheusHandler returns an http.Handler serving all metrics in the
+// Prometheus text format, for mounting at /metrics. It may be mounted before
+// Init; until Init runs with Config.Prometheus set it responds 503 Service
+// Unavailable.
+func PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := promHandler.Load()
+		if h == nil {
+			http.Error(w, "prometheus exporter not enabled", http.StatusServiceUnavailable)
+			return
+		}
+		(*h).ServeHTTP(w, r)
+	})
+}
+
+// servePrometheus serves PrometheusHandler at /metrics on addr and returns a
+// function that shuts the server down. Listening happens before it returns,
+// so a port already in use is reported to Init.
+func servePrometheus(addr string) (func(context.Context) error, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", PrometheusHandler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("otel: prometheus server stopped", "addr", addr, "error", err)
+		}
+	}()
+	return srv.Shutdown, nil
+}
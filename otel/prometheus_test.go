@@ -0,0 +1,164 @@
+package otel_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	chassis "github.com/ai8future/chassis-go/v11"
+	"github.com/ai8future/chassis-go/v11/otel"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	apimetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestPrometheusHandlerServesMetrics(t *testing.T) {
+	chassis.ResetVersionCheck()
+	chassis.RequireMajor(11)
+
+	shutdown := otel.Init(otel.Config{
+		ServiceName:    "prom-svc",
+		ServiceVersion: "1.0.0",
+		Insecure:       true,
+		Prometheus:     true,
+	})
+	defer shutdownWithShortTimeout(t, shutdown)
+
+	counter, err := otelapi.GetMeterProvider().Meter("promtest").Int64Counter("promtest_jobs_total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter.Add(context.Background(), 3)
+
+	rec := httptest.NewRecorder()
+	otel.PrometheusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	if !strings.Contains(string(body), "promtest_jobs_total") {
+		t.Errorf("scrape output missing promtest_jobs_total:\n%s", body)
+	}
+}
+
+func TestPrometheusAddrServesMetrics(t *testing.T) {
+	chassis.ResetVersionCheck()
+	chassis.RequireMajor(11)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	shutdown := otel.Init(otel.Config{
+		ServiceName:    "prom-addr-svc",
+		ServiceVersion: "1.0.0",
+		Insecure:       true,
+		PrometheusAddr: addr,
+	})
+	counter, err := otelapi.GetMeterProvider().Meter("promtest").Int64Counter("promaddr_jobs_total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter.Add(context.Background(), 1)
+
+	resp, err := http.Get("http://" + addr + "/metrics")
+	if err != nil {
+		t.Fatalf("scrape: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "promaddr_jobs_total") {
+		t.Fatalf("status %d, body missing promaddr_jobs_total:\n%s", resp.StatusCode, body)
+	}
+
+	_ = shutdownWithShortTimeout(t, shutdown)
+	if _, err := http.Get("http://" + addr + "/metrics"); err == nil {
+		t.Fatal("metrics listener still open after shutdown")
+	}
+}
+
+func TestInit_ViewsShapeExportedMetrics(t *testing.T) {
+	chassis.ResetVersionCheck()
+	chassis.RequireMajor(11)
+
+	shutdown := otel.Init(otel.Config{
+		ServiceName:    "views-svc",
+		ServiceVersion: "1.0.0",
+		Insecure:       true,
+		Prometheus:     true,
+		Views: []metric.View{
+			otel.HistogramBuckets("viewtest.latency", 7, 70),
+			otel.RenameMetric("viewtest.old", "viewtest.renamed"),
+			otel.DropMetricAttributes("viewtest.*", "user"),
+			otel.DropMetric("viewtest.noise"),
+		},
+	})
+	defer shutdownWithShortTimeout(t, shutdown)
+
+	ctx := context.Background()
+	meter := otelapi.GetMeterProvider().Meter("viewtest")
+	hist, _ := meter.Float64Histogram("viewtest.latency")
+	hist.Record(ctx, 5, apimetric.WithAttributes(attribute.String("user", "u1"), attribute.String("route", "/a")))
+	old, _ := meter.Int64Counter("viewtest.old")
+	old.Add(ctx, 1)
+	noise, _ := meter.Int64Counter("viewtest.noise")
+	noise.Add(ctx, 1)
+
+	rec := httptest.NewRecorder()
+	otel.PrometheusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{`le="7"`, `le="70"`, "viewtest_renamed_total", `route="/a"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("scrape output missing %s", want)
+		}
+	}
+	for _, unwanted := range []string{`le="5"`, "viewtest_old", "viewtest_noise", `user="u1"`} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("scrape output contains %s", unwanted)
+		}
+	}
+	if t.Failed() {
+		t.Logf("scrape output:\n%s", body)
+	}
+}
+
+func TestHistogramBucketsPanicsOnUnsortedBounds(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for unsorted bounds")
+		}
+	}()
+	otel.HistogramBuckets("x", 10, 5)
+}
+
+func TestInit_RuntimeAndHostMetrics(t *testing.T) {
+	chassis.ResetVersionCheck()
+	chassis.RequireMajor(11)
+
+	shutdown := otel.Init(otel.Config{
+		ServiceName:    "runtime-svc",
+		ServiceVersion: "1.0.0",
+		Insecure:       true,
+		Prometheus:     true,
+		RuntimeMetrics: true,
+		HostMetrics:    true,
+	})
+	defer shutdownWithShortTimeout(t, shutdown)
+
+	rec := httptest.NewRecorder()
+	otel.PrometheusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{"go_goroutine", "process_cpu_time", "system_memory_usage"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("scrape output missing %s", want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package otel
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// serviceAccountNamespaceFile holds the pod's namespace inside Kubernetes.
+// A variable so tests can point it elsewhere.
+var serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// resourceOptions returns the resource options for cfg. The service name and
+// version come last so no detector can override them.
+func resourceOptions(cfg Config) []resource.Option {
+	var opts []resource.Option
+	if cfg.DetectResources {
+		opts = append(opts,
+			resource.WithHost(),
+			resource.WithContainer(),
+			resource.WithDetectors(KubernetesDetector(), CloudDetector()),
+			resource.WithFromEnv(),
+		)
+	}
+	if len(cfg.ResourceDetectors) > 0 {
+		opts = append(opts, resource.WithDetectors(cfg.ResourceDetectors...))
+	}
+	return append(opts, resource.WithAttributes(
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+	))
+}
+
+// KubernetesDetector returns a resource detector for pods. It reports
+// nothing outside Kubernetes. Inside, it sets k8s.pod.name from POD_NAME or
+// the host name, k8s.namespace.name from POD_NAMESPACE or the service account
+// mount, and k8s.node.name and k8s.pod.uid from NODE_NAME and POD_UID. Expose
+// those variables through the downward API:
+//
+//	env:
+//	- name: NODE_NAME
+//	  valueFrom: {fieldRef: {fieldPath: spec.nodeName}}
+func KubernetesDetector() resource.Detector {
+	return detectorFunc(func() []attribute.KeyValue {
+		if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+			return nil
+		}
+		pod := os.Getenv("POD_NAME")
+		if pod == "" {
+			pod, _ = os.Hostname()
+		}
+		ns := os.Getenv("POD_NAMESPACE")
+		if ns == "" {
+			if b, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+				ns = strings.TrimSpace(string(b))
+			}
+		}
+		var attrs []attribute.KeyValue
+		attrs = appendIfSet(attrs, semconv.K8SPodName, pod)
+		attrs = appendIfSet(attrs, semconv.K8SNamespaceName, ns)
+		attrs = appendIfSet(attrs, semconv.K8SNodeName, os.Getenv("NODE_NAME"))
+		return appendIfSet(attrs, semconv.K8SPodUID, os.Getenv("POD_UID"))
+	})
+}
+
+// CloudDetector returns a resource detector that sets cloud.provider,
+// cloud.platform, cloud.region and cloud.account.id from the environment
+// variables AWS Lambda and ECS, Google Cloud Run, Cloud Functions and App
+// Engine, and Azure App Service and Functions set for their workloads. It
+// never calls a metadata server, so it costs nothing at startup and reports
+// nothing on plain VMs; add a contrib detector to ResourceDetectors for those.
+func CloudDetector() resource.Detector {
+	return detectorFunc(func() []attribute.KeyValue {
+		env := os.Getenv
+		switch {
+		case env("AWS_LAMBDA_FUNCTION_NAME") != "":
+			return awsAttrs(semconv.CloudPlatformAWSLambda, semconv.FaaSName(env("AWS_LAMBDA_FUNCTION_NAME")))
+		case env("ECS_CONTAINER_METADATA_URI_V4") != "" || env("ECS_CONTAINER_METADATA_URI") != "":
+			return awsAttrs(semconv.CloudPlatformAWSECS)
+		case env("K_SERVICE") != "":
+			return gcpAttrs(semconv.CloudPlatformGCPCloudRun, semconv.FaaSName(env("K_SERVICE")))
+		case env("FUNCTION_TARGET") != "":
+			return gcpAttrs(semconv.CloudPlatformGCPCloudFunctions)
+		case env("GAE_SERVICE") != "":
+			return gcpAttrs(semconv.CloudPlatformGCPAppEngine)
+		case env("FUNCTIONS_WORKER_RUNTIME") != "" && env("WEBSITE_SITE_NAME") != "":
+			return azureAttrs(semconv.CloudPlatformAzureFunctions, semconv.FaaSName(env("WEBSITE_SITE_NAME")))
+		case env("WEBSITE_SITE_NAME") != "":
+			return azureAttrs(semconv.CloudPlatformAzureAppService)
+		}
+		return nil
+	})
+}
+
+func awsAttrs(attrs ...attribute.KeyValue) []attribute.KeyValue {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	attrs = append(attrs, semconv.CloudProviderAWS)
+	return appendIfSet(attrs, semconv.CloudRegion, region)
+}
+
+func gcpAttrs(attrs ...attribute.KeyValue) []attribute.KeyValue {
+	project := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	if project == "" {
+		project = os.Getenv("GCP_PROJECT")
+	}
+	attrs = append(attrs, semconv.CloudProviderGCP)
+	return appendIfSet(attrs, semconv.CloudAccountID, project)
+}
+
+func azureAttrs(attrs ...attribute.KeyValue) []attribute.KeyValue {
+	attrs = append(attrs, semconv.CloudProviderAzure)
+	return appendIfSet(attrs, semconv.CloudRegion, os.Getenv("REGION_NAME"))
+}
+
+// appendIfSet appends attr(value) to attrs unless value is empty.
+func appendIfSet(attrs []attribute.KeyValue, attr func(string) attribute.KeyValue, value string) []attribute.KeyValue {
+	if value == "" {
+		return attrs
+	}
+	return append(attrs, attr(value))
+}
+
+// detectorFunc adapts a function returning attributes to resource.Detector.
+// The resource carries no schema URL, so it merges with any other detector's.
+type detectorFunc func() []attribute.KeyValue
+
+func (f detectorFunc) Detect(context.Context) (*resource.Resource, error) {
+	return resource.NewSchemaless(f()...), nil
+}
@@ -0,0 +1,105 @@
+package otel
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func detectedAttrs(t *testing.T, d resource.Detector) map[attribute.Key]string {
+	t.Helper()
+	res, err := d.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	got := map[attribute.Key]string{}
+	for _, kv := range res.Attributes() {
+		got[kv.Key] = kv.Value.Emit()
+	}
+	return got
+}
+
+func TestKubernetesDetector(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	if got := detectedAttrs(t, KubernetesDetector()); len(got) != 0 {
+		t.Fatalf("outside Kubernetes: %v, want nothing", got)
+	}
+
+	nsFile := filepath.Join(t.TempDir(), "namespace")
+	if err := os.WriteFile(nsFile, []byte("payments\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	orig := serviceAccountNamespaceFile
+	serviceAccountNamespaceFile = nsFile
+	t.Cleanup(func() { serviceAccountNamespaceFile = orig })
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("POD_NAME", "api-7d9f-x2")
+	t.Setenv("POD_NAMESPACE", "")
+	t.Setenv("NODE_NAME", "node-3")
+	t.Setenv("POD_UID", "")
+	got := detectedAttrs(t, KubernetesDetector())
+	want := map[attribute.Key]string{
+		"k8s.pod.name":       "api-7d9f-x2",
+		"k8s.namespace.name": "payments",
+		"k8s.node.name":      "node-3",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("attrs = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestCloudDetector(t *testing.T) {
+	for _, name := range []string{
+		"AWS_LAMBDA_FUNCTION_NAME", "ECS_CONTAINER_METADATA_URI_V4", "ECS_CONTAINER_METADATA_URI",
+		"K_SERVICE", "FUNCTION_TARGET", "GAE_SERVICE", "FUNCTIONS_WORKER_RUNTIME", "WEBSITE_SITE_NAME",
+	} {
+		t.Setenv(name, "")
+	}
+	if got := detectedAttrs(t, CloudDetector()); len(got) != 0 {
+		t.Fatalf("no cloud: %v, want nothing", got)
+	}
+
+	t.Setenv("K_SERVICE", "checkout")
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "shop-prod")
+	got := detectedAttrs(t, CloudDetector())
+	if got["cloud.provider"] != "gcp" || got["cloud.platform"] != "gcp_cloud_run" ||
+		got["cloud.account.id"] != "shop-prod" || got["faas.name"] != "checkout" {
+		t.Fatalf("Cloud Run attrs = %v", got)
+	}
+
+	t.Setenv("K_SERVICE", "")
+	t.Setenv("ECS_CONTAINER_METADATA_URI_V4", "http://169.254.170.2/v4/abc")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	got = detectedAttrs(t, CloudDetector())
+	if got["cloud.provider"] != "aws" || got["cloud.platform"] != "aws_ecs" || got["cloud.region"] != "eu-west-1" {
+		t.Fatalf("ECS attrs = %v", got)
+	}
+}
+
+func TestResourceOptions_ServiceNameWins(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "service.name=from-env,deployment.environment=prod")
+	res, err := resource.New(context.Background(), resourceOptions(Config{
+		ServiceName:     "billing",
+		DetectResources: true,
+	})...)
+	if err != nil {
+		t.Logf("resource.New: %v", err)
+	}
+	got := map[attribute.Key]string{}
+	for _, kv := range res.Attributes() {
+		got[kv.Key] = kv.Value.Emit()
+	}
+	if got["service.name"] != "billing" || got["deployment.environment"] != "prod" || got["host.name"] == "" {
+		t.Fatalf("resource = %v", got)
+	}
+}
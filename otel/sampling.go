@@ -0,0 +1,198 @@
+package otel
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SamplerBuilder builds a trace sampler from a base ratio and rules, for
+// Config.Sampler. Start one with Sampling:
+//
+//	sampler := otel.Sampling(0.05).
+//		Route("/health", 0).
+//		Route("/billing.v1.Billing/*", 0.5).
+//		ForceBaggage("debug").
+//		KeepErrors().
+//		Build()
+//
+// The sampler is parent-based: a span with a parent follows its parent's
+// decision, so whole traces are kept or dropped together, and the ratio and
+// rules decide only for root spans.
+type SamplerBuilder struct {
+	ratio       float64
+	rules       []samplingRule
+	baggageKeys []string
+	keepErrors  bool
+}
+
+// samplingRule samples root spans whose route matches pattern at ratio.
+type samplingRule struct {
+	pattern string
+	ratio   float64
+}
+
+// Sampling starts a SamplerBuilder that samples the given fraction of root
+// spans no rule matches. Panics unless 0 <= ratio <= 1.
+func Sampling(ratio float64) *SamplerBuilder {
+	checkRatio(ratio)
+	return &SamplerBuilder{ratio: ratio}
+}
+
+// Route samples root spans for pattern at ratio instead of the base ratio.
+// pattern is matched against the span's url.path attribute, set by
+// httpkit.Tracing, or its rpc.method attribute, set by the grpckit tracing
+// interceptors, falling back to the span name. A trailing '*' matches any
+// suffix; otherwise the match is exact. The first matching rule wins.
+// Panics unless 0 <= ratio <= 1.
+func (b *SamplerBuilder) Route(pattern string, ratio float64) *SamplerBuilder {
+	checkRatio(ratio)
+	b.rules = append(b.rules, samplingRule{pattern: pattern, ratio: ratio})
+	return b
+}
+
+// ForceBaggage samples every span whose context carries the baggage member
+// key set to "1" or "true", overriding the ratio, the rules and an unsampled
+// parent. Callers turn it on per request with the W3C baggage header, e.g.
+// "baggage: debug=1", which httpkit.Tracing and the grpckit interceptors
+// extract with the propagator Init installs.
+func (b *SamplerBuilder) ForceBaggage(key string) *SamplerBuilder {
+	b.baggageKeys = append(b.baggageKeys, key)
+	return b
+}
+
+// KeepErrors also exports spans the sampler did not pick when they end with
+// an error status, so failures are visible at any ratio. Unpicked spans are
+// then recorded in memory until they end, which costs CPU and allocations
+// for every span; only spans with an error are exported, so their traces
+// are incomplete. Requires Init, which adds the processor that exports them.
+func (b *SamplerBuilder) KeepErrors() *SamplerBuilder {
+	b.keepErrors = true
+	return b
+}
+
+// Build returns the sampler.
+func (b *SamplerBuilder) Build() sdktrace.Sampler {
+	root := &ruleSampler{base: sdktrace.TraceIDRatioBased(b.ratio)}
+	for _, r := range b.rules {
+		root.rules = append(root.rules, compiledRule{
+			pattern: strings.TrimSuffix(r.pattern, "*"),
+			prefix:  strings.HasSuffix(r.pattern, "*"),
+			sampler: sdktrace.TraceIDRatioBased(r.ratio),
+		})
+	}
+	return &builtSampler{
+		parentBased: sdktrace.ParentBased(root),
+		baggageKeys: b.baggageKeys,
+		keepErrors:  b.keepErrors,
+		description: fmt.Sprintf("ChassisSampler{ratio=%g,rules=%d,baggage=%v,keepErrors=%t}",
+			b.ratio, len(b.rules), b.baggageKeys, b.keepErrors),
+	}
+}
+
+func checkRatio(ratio float64) {
+	if ratio < 0 || ratio > 1 {
+		panic(fmt.Sprintf("otel: sampling ratio must be between 0 and 1, got %g", ratio))
+	}
+}
+
+// builtSampler applies the baggage override and KeepErrors around the
+// parent-based rule sampler.
+type builtSampler struct {
+	parentBased sdktrace.Sampler
+	baggageKeys []string
+	keepErrors  bool
+	description string
+}
+
+func (s *builtSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if s.forced(p) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	res := s.parentBased.ShouldSample(p)
+	if s.keepErrors && res.Decision == sdktrace.Drop {
+		res.Decision = sdktrace.RecordOnly
+	}
+	return res
+}
+
+func (s *builtSampler) forced(p sdktrace.SamplingParameters) bool {
+	if len(s.baggageKeys) == 0 {
+		return false
+	}
+	bag := baggage.FromContext(p.ParentContext)
+	for _, key := range s.baggageKeys {
+		if v := bag.Member(key).Value(); v == "1" || v == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *builtSampler) Description() string { return s.description }
+
+// keepsErrorSpans reports whether Init should export unsampled error spans.
+func keepsErrorSpans(s sdktrace.Sampler) bool {
+	b, ok := s.(*builtSampler)
+	return ok && b.keepErrors
+}
+
+// ruleSampler picks the ratio sampler for a root span by its route.
+type ruleSampler struct {
+	base  sdktrace.Sampler
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	pattern string
+	prefix  bool
+	sampler sdktrace.Sampler
+}
+
+func (s *ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	route := p.Name
+	for _, kv := range p.Attributes {
+		if kv.Key == "url.path" || kv.Key == "rpc.method" {
+			route = kv.Value.AsString()
+			break
+		}
+	}
+	for _, r := range s.rules {
+		if route == r.pattern || (r.prefix && strings.HasPrefix(route, r.pattern)) {
+			return r.sampler.ShouldSample(p)
+		}
+	}
+	return s.base.ShouldSample(p)
+}
+
+func (s *ruleSampler) Description() string { return "ChassisRuleSampler" }
+
+// errorSpanProcessor passes spans to next, marking unsampled ones that ended
+// with an error status as sampled so that next exports them.
+type errorSpanProcessor struct {
+	sdktrace.SpanProcessor
+}
+
+func (p errorSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() && s.Status().Code == codes.Error {
+		s = sampledSpan{s}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// sampledSpan reports its span context as sampled.
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
@@ -0,0 +1,107 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func decide(s sdktrace.Sampler, ctx context.Context, name string, attrs ...attribute.KeyValue) sdktrace.SamplingDecision {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	return s.ShouldSample(sdktrace.SamplingParameters{
+		ParentContext: ctx, TraceID: traceID, Name: name, Attributes: attrs,
+	}).Decision
+}
+
+func TestSamplingRules(t *testing.T) {
+	s := Sampling(0).
+		Route("/health", 0).
+		Route("/billing.v1.Billing/*", 1).
+		Route("/api/*", 1).
+		Build()
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		attrs []attribute.KeyValue
+		want  sdktrace.SamplingDecision
+	}{
+		{"GET", []attribute.KeyValue{attribute.String("url.path", "/api/users/42")}, sdktrace.RecordAndSample},
+		{"GET", []attribute.KeyValue{attribute.String("url.path", "/health")}, sdktrace.Drop},
+		{"GET", []attribute.KeyValue{attribute.String("url.path", "/other")}, sdktrace.Drop},
+		{"x", []attribute.KeyValue{attribute.String("rpc.method", "/billing.v1.Billing/Charge")}, sdktrace.RecordAndSample},
+		{"/api/by-name", nil, sdktrace.RecordAndSample},
+	}
+	for _, tt := range tests {
+		if got := decide(s, ctx, tt.name, tt.attrs...); got != tt.want {
+			t.Errorf("%s %v: decision %v, want %v", tt.name, tt.attrs, got, tt.want)
+		}
+	}
+}
+
+func TestSamplingFollowsParent(t *testing.T) {
+	s := Sampling(0).Build()
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled, Remote: true,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), parent)
+	if got := decide(s, ctx, "child"); got != sdktrace.RecordAndSample {
+		t.Fatalf("child of sampled parent: %v, want RecordAndSample", got)
+	}
+}
+
+func TestSamplingForceBaggage(t *testing.T) {
+	s := Sampling(0).ForceBaggage("debug").Build()
+	for value, want := range map[string]sdktrace.SamplingDecision{
+		"1":    sdktrace.RecordAndSample,
+		"true": sdktrace.RecordAndSample,
+		"0":    sdktrace.Drop,
+	} {
+		m, _ := baggage.NewMember("debug", value)
+		bag, _ := baggage.New(m)
+		if got := decide(s, baggage.ContextWithBaggage(context.Background(), bag), "op"); got != want {
+			t.Errorf("debug=%s: %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestSamplingKeepErrorsExportsOnlyFailedSpans(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	sampler := Sampling(0).KeepErrors().Build()
+	if !keepsErrorSpans(sampler) {
+		t.Fatal("keepsErrorSpans = false for a KeepErrors sampler")
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithSpanProcessor(errorSpanProcessor{sdktrace.NewSimpleSpanProcessor(exp)}),
+	)
+	tracer := tp.Tracer("test")
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	ok.End()
+	_, failed := tracer.Start(context.Background(), "failed")
+	failed.SetStatus(codes.Error, "boom")
+	failed.End()
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "failed" {
+		t.Fatalf("exported %v, want only the failed span", spans.Snapshots())
+	}
+}
+
+func TestSamplingPanicsOnBadRatio(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for ratio > 1")
+		}
+	}()
+	Sampling(0.5).Route("/x", 2)
+}
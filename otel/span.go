@@ -0,0 +1,50 @@
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// scopeName is the instrumentation scope of spans started with Start and of
+// the package's own metrics.
+const scopeName = "github.com/ai8future/chassis-go/v11/otel"
+
+// Start starts a span named name, as a child of the span in ctx if there is
+// one, and returns a context carrying it and a function that ends it. Pass
+// the operation's error to end: a non-nil error is recorded on the span and
+// sets its status to Error. Use a named result so the deferred call sees the
+// final error:
+//
+//	func Charge(ctx context.Context, id string) (err error) {
+//		ctx, end := otel.Start(ctx, "billing.Charge", attribute.String("invoice.id", id))
+//		defer func() { end(err) }()
+//		...
+//	}
+//
+// Spans use the global TracerProvider, so they are no-ops until Init runs.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, func(error)) {
+	ctx, span := otel.GetTracerProvider().Tracer(scopeName).Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// AddEvent adds an event to the span in ctx. It does nothing when ctx holds
+// no recording span.
+func AddEvent(ctx context.Context, name string, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// SetAttributes sets attributes on the span in ctx. It does nothing when ctx
+// holds no recording span.
+func SetAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
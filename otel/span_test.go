@@ -0,0 +1,74 @@
+package otel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ai8future/chassis-go/v11/otel"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	prev := otelapi.GetTracerProvider()
+	otelapi.SetTracerProvider(tp)
+	t.Cleanup(func() { otelapi.SetTracerProvider(prev) })
+	return rec
+}
+
+func TestStart_RecordsErrorOnEnd(t *testing.T) {
+	rec := recordSpans(t)
+
+	parentCtx, endParent := otel.Start(context.Background(), "parent")
+	ctx, end := otel.Start(parentCtx, "charge", attribute.String("invoice.id", "inv_1"))
+	otel.SetAttributes(ctx, attribute.Int("amount", 42))
+	otel.AddEvent(ctx, "card.declined", attribute.String("reason", "insufficient_funds"))
+	end(errors.New("declined"))
+	endParent(nil)
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	child, parent := spans[0], spans[1]
+	if child.Name() != "charge" || child.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("child %q has parent %v, want %v", child.Name(), child.Parent().SpanID(), parent.SpanContext().SpanID())
+	}
+	if child.Status().Code != codes.Error || child.Status().Description != "declined" {
+		t.Fatalf("child status = %+v, want Error declined", child.Status())
+	}
+	if parent.Status().Code != codes.Unset {
+		t.Fatalf("parent status = %+v, want Unset", parent.Status())
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range child.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["invoice.id"].AsString() != "inv_1" || attrs["amount"].AsInt64() != 42 {
+		t.Fatalf("attributes = %v", child.Attributes())
+	}
+	var names []string
+	for _, e := range child.Events() {
+		names = append(names, e.Name)
+	}
+	if len(names) != 2 || names[0] != "card.declined" || names[1] != "exception" {
+		t.Fatalf("events = %v, want [card.declined exception]", names)
+	}
+}
+
+func TestSpanHelpers_NoSpanInContext(t *testing.T) {
+	ctx := context.Background()
+	otel.AddEvent(ctx, "ignored")
+	otel.SetAttributes(ctx, attribute.Bool("ignored", true))
+	if trace.SpanFromContext(ctx).IsRecording() {
+		t.Fatal("background context unexpectedly holds a recording span")
+	}
+}
@@ -0,0 +1,105 @@
+package otel
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+// The helpers below build the common metric.View cases for Config.Views.
+// instrument is an instrument name such as "http.server.duration", or a
+// pattern where '*' matches any run of characters and '?' one character.
+// Views whose instrument does not exist have no effect. For anything else,
+// build a view with metric.NewView directly.
+
+// HistogramBuckets sets explicit bucket boundaries, in the instrument's
+// unit, for the matching histograms. Panics unless bounds is strictly
+// increasing.
+func HistogramBuckets(instrument string, bounds ...float64) metric.View {
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			panic("otel: HistogramBuckets bounds must be strictly increasing")
+		}
+	}
+	return metric.NewView(
+		metric.Instrument{Name: instrument},
+		metric.Stream{Aggregation: metric.AggregationExplicitBucketHistogram{Boundaries: bounds}},
+	)
+}
+
+// RenameMetric exports instrument under name. Panics if instrument is a
+// pattern, which would give several instruments the same name.
+func RenameMetric(instrument, name string) metric.View {
+	if strings.ContainsAny(instrument, "*?") {
+		panic("otel: RenameMetric instrument must be an exact name, not a pattern")
+	}
+	return metric.NewView(metric.Instrument{Name: instrument}, metric.Stream{Name: name})
+}
+
+// DropMetricAttributes removes the attribute keys from the matching
+// instruments, merging the series that differed only in them. Use it to cut
+// cardinality from instrumentation you do not control.
+func DropMetricAttributes(instrument string, keys ...string) metric.View {
+	denied := make([]attribute.Key, len(keys))
+	for i, k := range keys {
+		denied[i] = attribute.Key(k)
+	}
+	return metric.NewView(
+		metric.Instrument{Name: instrument},
+		metric.Stream{AttributeFilter: attribute.NewDenyKeysFilter(denied...)},
+	)
+}
+
+// DropMetric stops the matching instruments from being exported at all.
+func DropMetric(instrument string) metric.View {
+	return metric.NewView(metric.Instrument{Name: instrument}, metric.Stream{Aggregation: metric.AggregationDrop{}})
+}
+
+// mergeViews combines views into one, so that every view matching an
+// instrument shapes the same stream instead of each adding a stream of its
+// own, as separate SDK views would. Later views override the name,
+// description, unit and aggregation set by earlier ones, where they set
+// them; attribute filters all apply.
+func mergeViews(views []metric.View) metric.View {
+	return func(inst metric.Instrument) (metric.Stream, bool) {
+		merged := metric.Stream{Name: inst.Name, Description: inst.Description, Unit: inst.Unit}
+		matched := false
+		for _, view := range views {
+			s, ok := view(inst)
+			if !ok {
+				continue
+			}
+			matched = true
+			// A view that leaves these alone reports the instrument's own.
+			if s.Name != inst.Name {
+				merged.Name = s.Name
+			}
+			if s.Description != inst.Description {
+				merged.Description = s.Description
+			}
+			if s.Unit != inst.Unit {
+				merged.Unit = s.Unit
+			}
+			if s.Aggregation != nil {
+				merged.Aggregation = s.Aggregation
+			}
+			if s.ExemplarReservoirProviderSelector != nil {
+				merged.ExemplarReservoirProviderSelector = s.ExemplarReservoirProviderSelector
+			}
+			merged.AttributeFilter = andFilter(merged.AttributeFilter, s.AttributeFilter)
+		}
+		return merged, matched
+	}
+}
+
+// andFilter keeps the attributes that both a and b keep; nil keeps all.
+func andFilter(a, b attribute.Filter) attribute.Filter {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return func(kv attribute.KeyValue) bool { return a(kv) && b(kv) }
+}
@@ -0,0 +1,49 @@
+// Package requestid carries request IDs in a context, independent of the
+// transport that received the request. httpkit and grpckit store the IDs
+// they accept or generate here, so handlers, logs and outgoing calls read
+// them the same way over HTTP and gRPC.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// contextKey is the unexported context key used to store request IDs.
+type contextKey struct{}
+
+// idCounter is a fallback counter used when crypto/rand fails.
+var idCounter uint64
+
+// From retrieves the request ID from the context.
+// Returns an empty string if no request ID is present.
+func From(ctx context.Context) string {
+	v, ok := ctx.Value(contextKey{}).(string)
+	if !ok {
+		return ""
+	}
+	return v
+}
+
+// NewContext returns a copy of ctx carrying id as the request ID From
+// reports.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// New returns a new UUID-v4-like random request ID using crypto/rand.
+// Falls back to a timestamp+counter scheme if crypto/rand is unavailable.
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x-%d", time.Now().UnixNano(), atomic.AddUint64(&idCounter, 1))
+	}
+	// Set version (4) and variant (RFC 4122) bits.
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x",
+		b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
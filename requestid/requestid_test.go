@@ -0,0 +1,29 @@
+package requestid
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+var uuidV4 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewContextRoundTrip(t *testing.T) {
+	if id := From(context.Background()); id != "" {
+		t.Fatalf("From(empty ctx) = %q, want empty", id)
+	}
+	ctx := NewContext(context.Background(), "req-1")
+	if id := From(ctx); id != "req-1" {
+		t.Fatalf("From = %q, want req-1", id)
+	}
+}
+
+func TestNewIsUniqueUUID(t *testing.T) {
+	a, b := New(), New()
+	if !uuidV4.MatchString(a) {
+		t.Errorf("New() = %q, want a UUID v4", a)
+	}
+	if a == b {
+		t.Errorf("New() returned %q twice", a)
+	}
+}
@@ -0,0 +1,40 @@
+package secval
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsafePath is returned by ValidatePath, and by Validator for
+// Policy.PathFields values, when a path could escape its base directory.
+var ErrUnsafePath = errors.New("secval: unsafe path")
+
+// ValidatePath checks that s is safe to use as a relative file path or
+// storage key: it must not be absolute (a leading "/" or "\", which also
+// covers UNC paths, or a drive letter such as "C:"), contain a ".." segment
+// (with "/" or "\" separators), null bytes, or other control characters.
+// It does not clean the path; use SafeFilename to sanitise instead of
+// rejecting.
+func ValidatePath(s string) error {
+	for _, r := range s {
+		if r == 0 {
+			return fmt.Errorf("%w: %q contains a null byte", ErrUnsafePath, s)
+		}
+		if r < 32 || r == 127 {
+			return fmt.Errorf("%w: %q contains a control character", ErrUnsafePath, s)
+		}
+	}
+	if strings.HasPrefix(s, "/") || strings.HasPrefix(s, `\`) {
+		return fmt.Errorf("%w: %q is an absolute path", ErrUnsafePath, s)
+	}
+	if len(s) >= 2 && s[1] == ':' && ('a' <= s[0] && s[0] <= 'z' || 'A' <= s[0] && s[0] <= 'Z') {
+		return fmt.Errorf("%w: %q has a drive letter", ErrUnsafePath, s)
+	}
+	for seg := range strings.FieldsFuncSeq(s, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if seg == ".." {
+			return fmt.Errorf("%w: %q contains a parent directory reference", ErrUnsafePath, s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package secval
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidatePath(t *testing.T) {
+	safe := []string{"report.pdf", "uploads/2024/a.png", "a..b/c", "..hidden", "dir/.../x", "a/b:c", "ab:c"}
+	for _, p := range safe {
+		if err := ValidatePath(p); err != nil {
+			t.Errorf("ValidatePath(%q) = %v, want nil", p, err)
+		}
+	}
+	unsafe := []string{"../etc/passwd", "a/../../b", `..\windows`, "a/..", "file\x00.txt", "line\nbreak", "bell\x07", "del\x7f",
+		"/etc/passwd", `\\server\share`, `\x`, `C:\x`, "c:/x", "D:x"}
+	for _, p := range unsafe {
+		if err := ValidatePath(p); !errors.Is(err, ErrUnsafePath) {
+			t.Errorf("ValidatePath(%q) = %v, want ErrUnsafePath", p, err)
+		}
+	}
+}
+
+func TestPolicyPathFields(t *testing.T) {
+	v := New(Policy{PathFields: []string{"filename", "storage-key"}})
+	if err := v.ValidateJSON([]byte(`{"filename": "a.txt", "files": [{"storage_key": "u/1/b.png"}]}`)); err != nil {
+		t.Fatalf("safe paths rejected: %v", err)
+	}
+	for _, doc := range []string{
+		`{"filename": "../../etc/passwd"}`,
+		`{"files": [{"storage_key": "u/\u0000/b"}]}`,
+		`{"FileName": "a\r\nb"}`,
+	} {
+		if err := v.ValidateJSON([]byte(doc)); !errors.Is(err, ErrUnsafePath) {
+			t.Errorf("%s: expected ErrUnsafePath, got %v", doc, err)
+		}
+	}
+	// Other fields are not path-checked.
+	if err := v.ValidateJSON([]byte(`{"comment": "../see above"}`)); err != nil {
+		t.Errorf("non-path field rejected: %v", err)
+	}
+}
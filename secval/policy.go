@@ -0,0 +1,129 @@
+package secval
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Policy configures a Validator. The zero Policy matches ValidateJSON.
+type Policy struct {
+	// DeniedKeys are blocked in addition to the built-in dangerous keys
+	// (__proto__, constructor, prototype).
+	DeniedKeys []string
+	// AllowedKeys are removed from the denied set, including built-in keys,
+	// for services whose domain legitimately uses them.
+	AllowedKeys []string
+	// MaxDepth is the maximum nesting depth. Zero means MaxNestingDepth.
+	MaxDepth int
+	// CaseSensitive matches keys exactly instead of case-insensitively.
+	// Hyphens are still normalised to underscores.
+	CaseSensitive bool
+	// PathFields names fields, at any depth, whose string values are used
+	// as filenames or storage keys. Their values are checked with
+	// ValidatePath and violations wrap ErrUnsafePath.
+	PathFields []string
+
+	// Structural limits close DoS vectors the key scan does not cover.
+	// Zero means unlimited. Violations wrap ErrLimitExceeded.
+
+	// MaxKeys caps the number of keys in any one object.
+	MaxKeys int
+	// MaxArrayLen caps the number of elements in any one array.
+	MaxArrayLen int
+	// MaxStringLen caps the length in bytes of any string value or key.
+	MaxStringLen int
+	// MaxSize caps the size in bytes of the whole payload. It is checked
+	// before parsing.
+	MaxSize int
+	// MaxHeaderLen caps the length in bytes of any one header value checked
+	// by ValidateHeader.
+	MaxHeaderLen int
+}
+
+// Validator validates JSON against a Policy. Build one with New; it is safe
+// for concurrent use.
+type Validator struct {
+	denied        map[string]bool
+	maxDepth      int
+	caseSensitive bool
+	maxKeys       int
+	maxArrayLen   int
+	maxStringLen  int
+	maxSize       int
+	maxHeaderLen  int
+	pathFields    map[string]bool
+}
+
+// defaultValidator backs the package-level ValidateJSON.
+var defaultValidator = New(Policy{})
+
+// New returns a Validator for p.
+func New(p Policy) *Validator {
+	v := &Validator{
+		denied:        make(map[string]bool, len(dangerousKeys)+len(p.DeniedKeys)),
+		maxDepth:      p.MaxDepth,
+		caseSensitive: p.CaseSensitive,
+		maxKeys:       p.MaxKeys,
+		maxArrayLen:   p.MaxArrayLen,
+		maxStringLen:  p.MaxStringLen,
+		maxSize:       p.MaxSize,
+		maxHeaderLen:  p.MaxHeaderLen,
+	}
+	if v.maxDepth <= 0 {
+		v.maxDepth = MaxNestingDepth
+	}
+	for k := range dangerousKeys {
+		v.denied[k] = true
+	}
+	for _, k := range p.DeniedKeys {
+		v.denied[v.normalise(k)] = true
+	}
+	for _, k := range p.AllowedKeys {
+		delete(v.denied, v.normalise(k))
+	}
+	if len(p.PathFields) > 0 {
+		v.pathFields = make(map[string]bool, len(p.PathFields))
+		for _, k := range p.PathFields {
+			v.pathFields[v.normalise(k)] = true
+		}
+	}
+	return v
+}
+
+// ValidateJSON checks data against the policy with a single streaming token
+// scan, without building an intermediate tree. Returns nil on success, or a
+// *Violation naming the offending field and rule and wrapping one of the
+// sentinel errors (ErrDangerousKey, ErrNestingDepth, ErrLimitExceeded,
+// ErrUnsafePath, ErrInvalidJSON).
+func (v *Validator) ValidateJSON(data []byte) error {
+	if v.maxSize > 0 && len(data) > v.maxSize {
+		return bodyViolation(nil, RuleMaxSize, fmt.Errorf("%w: payload is %d bytes, maximum %d", ErrLimitExceeded, len(data), v.maxSize))
+	}
+	return v.scan(data)
+}
+
+// checkString enforces MaxStringLen on a string value or key.
+func (v *Validator) checkString(s string) error {
+	if v.maxStringLen > 0 && len(s) > v.maxStringLen {
+		return fmt.Errorf("%w: string of %d bytes, maximum %d", ErrLimitExceeded, len(s), v.maxStringLen)
+	}
+	return nil
+}
+
+// normalise strips non-ASCII and non-printable characters from key, maps
+// hyphens to underscores and, unless the policy is case-sensitive,
+// lower-cases it.
+func (v *Validator) normalise(key string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, key)
+	cleaned = strings.ReplaceAll(cleaned, "-", "_")
+	if v.caseSensitive {
+		return cleaned
+	}
+	return strings.ToLower(cleaned)
+}
@@ -0,0 +1,92 @@
+package secval
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPolicyDeniedKeysExtendDefaults(t *testing.T) {
+	v := New(Policy{DeniedKeys: []string{"$where", "Admin-Override"}})
+	for _, doc := range []string{`{"$where": 1}`, `{"admin_override": 1}`, `{"__proto__": 1}`} {
+		if err := v.ValidateJSON([]byte(doc)); !errors.Is(err, ErrDangerousKey) {
+			t.Errorf("%s: expected ErrDangerousKey, got %v", doc, err)
+		}
+	}
+	if err := v.ValidateJSON([]byte(`{"name": "x"}`)); err != nil {
+		t.Errorf("clean JSON rejected: %v", err)
+	}
+}
+
+func TestPolicyAllowedKeysTrimDefaults(t *testing.T) {
+	v := New(Policy{AllowedKeys: []string{"constructor"}})
+	if err := v.ValidateJSON([]byte(`{"constructor": "Ada Lovelace"}`)); err != nil {
+		t.Errorf("allowed key rejected: %v", err)
+	}
+	if err := v.ValidateJSON([]byte(`{"__proto__": 1}`)); !errors.Is(err, ErrDangerousKey) {
+		t.Errorf("other built-in keys must stay blocked, got %v", err)
+	}
+}
+
+func TestPolicyMaxDepth(t *testing.T) {
+	v := New(Policy{MaxDepth: 3})
+	ok := strings.Repeat(`{"a":`, 3) + `1` + strings.Repeat(`}`, 3)
+	deep := strings.Repeat(`[`, 4) + strings.Repeat(`]`, 4)
+	if err := v.ValidateJSON([]byte(ok)); err != nil {
+		t.Errorf("depth 3 rejected: %v", err)
+	}
+	if err := v.ValidateJSON([]byte(deep)); !errors.Is(err, ErrNestingDepth) {
+		t.Errorf("expected ErrNestingDepth, got %v", err)
+	}
+}
+
+func TestPolicyCaseSensitive(t *testing.T) {
+	v := New(Policy{CaseSensitive: true, DeniedKeys: []string{"Secret"}})
+	if err := v.ValidateJSON([]byte(`{"secret": 1, "PROTOTYPE": 2}`)); err != nil {
+		t.Errorf("case-sensitive policy should allow differently cased keys: %v", err)
+	}
+	if err := v.ValidateJSON([]byte(`{"Secret": 1}`)); !errors.Is(err, ErrDangerousKey) {
+		t.Errorf("exact match should be rejected, got %v", err)
+	}
+}
+
+func TestZeroPolicyMatchesValidateJSON(t *testing.T) {
+	v := New(Policy{})
+	deep := strings.Repeat(`{"a":`, 21) + `1` + strings.Repeat(`}`, 21)
+	if err := v.ValidateJSON([]byte(deep)); !errors.Is(err, ErrNestingDepth) {
+		t.Errorf("expected default MaxNestingDepth, got %v", err)
+	}
+}
+
+func TestPolicyStructuralLimits(t *testing.T) {
+	v := New(Policy{MaxKeys: 2, MaxArrayLen: 3, MaxStringLen: 5, MaxSize: 64})
+	tests := []struct {
+		doc  string
+		fail bool
+	}{
+		{`{"a": [1, 2, 3], "b": "12345"}`, false},
+		{`{"a": 1, "b": 2, "c": 3}`, true},               // too many keys
+		{`{"a": [1, 2, 3, 4]}`, true},                    // array too long
+		{`{"a": "123456"}`, true},                        // string value too long
+		{`{"abcdef": 1}`, true},                          // key too long
+		{`[{"a": 1, "b": 2, "c": 3}]`, true},             // nested object
+		{`"` + strings.Repeat("x", 3) + `"`, false},      // top-level string
+		{`[` + strings.Repeat(`"", `, 30) + `""]`, true}, // exceeds MaxSize
+	}
+	for _, tt := range tests {
+		err := v.ValidateJSON([]byte(tt.doc))
+		if tt.fail && !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("%s: expected ErrLimitExceeded, got %v", tt.doc, err)
+		}
+		if !tt.fail && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.doc, err)
+		}
+	}
+}
+
+func TestPolicyZeroLimitsUnlimited(t *testing.T) {
+	doc := `{"k": [` + strings.Repeat(`1, `, 10000) + `1], "s": "` + strings.Repeat("x", 100000) + `"}`
+	if err := New(Policy{}).ValidateJSON([]byte(doc)); err != nil {
+		t.Fatalf("zero limits should be unlimited, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package secval
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ValidateMessage applies the policy to a protobuf message as if it were its
+// JSON mapping: MaxSize is checked against the encoded size, messages and
+// maps count as objects and repeated fields as arrays for MaxDepth, MaxKeys
+// and MaxArrayLen, and string-keyed map entries and google.protobuf.Struct
+// keys are matched against the denied keys. Field names come from the schema
+// and are not checked. Errors are *Violation values as for ValidateJSON,
+// with paths using the proto field names.
+func (v *Validator) ValidateMessage(m proto.Message) error {
+	if m == nil {
+		return nil
+	}
+	if v.maxSize > 0 {
+		if n := proto.Size(m); n > v.maxSize {
+			return bodyViolation(nil, RuleMaxSize, fmt.Errorf("%w: message is %d bytes, maximum %d", ErrLimitExceeded, n, v.maxSize))
+		}
+	}
+	w := &protoWalker{v: v}
+	return w.message(m.ProtoReflect(), 0)
+}
+
+// protoWalker walks a message, tracking the path to the current field.
+type protoWalker struct {
+	v    *Validator
+	path []pathSeg
+}
+
+func (w *protoWalker) fail(rule string, err error) error {
+	return bodyViolation(w.path, rule, err)
+}
+
+func (w *protoWalker) depth(depth int) error {
+	if depth >= w.v.maxDepth {
+		return w.fail(RuleMaxDepth, fmt.Errorf("%w: depth %d exceeds maximum %d", ErrNestingDepth, depth, w.v.maxDepth))
+	}
+	return nil
+}
+
+// key checks an attacker-controlled key and reports whether its value is a
+// path field.
+func (w *protoWalker) key(k string) (bool, error) {
+	if err := w.v.checkString(k); err != nil {
+		return false, w.fail(RuleMaxStringLen, err)
+	}
+	normalised := w.v.normalise(k)
+	if w.v.denied[normalised] {
+		return false, w.fail(RuleDangerousKey, fmt.Errorf("%w: %q", ErrDangerousKey, k))
+	}
+	return w.v.pathFields[normalised], nil
+}
+
+func (w *protoWalker) message(m protoreflect.Message, depth int) error {
+	switch x := m.Interface().(type) {
+	case *structpb.Struct:
+		return w.structValue(x, depth)
+	case *structpb.ListValue:
+		return w.listValue(x, depth)
+	case *structpb.Value:
+		return w.jsonValue(x, depth, false)
+	}
+	if err := w.depth(depth); err != nil {
+		return err
+	}
+	var err error
+	m.Range(func(fd protoreflect.FieldDescriptor, val protoreflect.Value) bool {
+		w.path = append(w.path, keySeg(string(fd.Name())))
+		err = w.field(fd, val, depth)
+		w.path = w.path[:len(w.path)-1]
+		return err == nil
+	})
+	return err
+}
+
+func (w *protoWalker) field(fd protoreflect.FieldDescriptor, val protoreflect.Value, depth int) error {
+	switch {
+	case fd.IsMap():
+		if err := w.depth(depth + 1); err != nil {
+			return err
+		}
+		mp := val.Map()
+		if w.v.maxKeys > 0 && mp.Len() > w.v.maxKeys {
+			return w.fail(RuleMaxKeys, fmt.Errorf("%w: map has %d keys, maximum %d", ErrLimitExceeded, mp.Len(), w.v.maxKeys))
+		}
+		stringKeys := fd.MapKey().Kind() == protoreflect.StringKind
+		var err error
+		mp.Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			w.path = append(w.path, keySeg(k.String()))
+			isPath := false
+			if stringKeys {
+				isPath, err = w.key(k.String())
+			}
+			if err == nil {
+				err = w.single(fd.MapValue(), mv, depth+1, isPath)
+			}
+			w.path = w.path[:len(w.path)-1]
+			return err == nil
+		})
+		return err
+	case fd.IsList():
+		if err := w.depth(depth + 1); err != nil {
+			return err
+		}
+		list := val.List()
+		if w.v.maxArrayLen > 0 && list.Len() > w.v.maxArrayLen {
+			return w.fail(RuleMaxArrayLen, fmt.Errorf("%w: list has %d elements, maximum %d", ErrLimitExceeded, list.Len(), w.v.maxArrayLen))
+		}
+		for i := range list.Len() {
+			w.path = append(w.path, indexSeg(i))
+			err := w.single(fd, list.Get(i), depth+1, false)
+			w.path = w.path[:len(w.path)-1]
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return w.single(fd, val, depth, w.v.pathFields[w.v.normalise(string(fd.Name()))])
+	}
+}
+
+// single checks one non-repeated value of fd's kind.
+func (w *protoWalker) single(fd protoreflect.FieldDescriptor, val protoreflect.Value, depth int, isPath bool) error {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return w.message(val.Message(), depth+1)
+	case protoreflect.StringKind:
+		return w.str(val.String(), isPath)
+	}
+	return nil
+}
+
+func (w *protoWalker) str(s string, isPath bool) error {
+	if err := w.v.checkString(s); err != nil {
+		return w.fail(RuleMaxStringLen, err)
+	}
+	if isPath {
+		if err := ValidatePath(s); err != nil {
+			return w.fail(RuleUnsafePath, err)
+		}
+	}
+	return nil
+}
+
+func (w *protoWalker) structValue(s *structpb.Struct, depth int) error {
+	if err := w.depth(depth); err != nil {
+		return err
+	}
+	if w.v.maxKeys > 0 && len(s.GetFields()) > w.v.maxKeys {
+		return w.fail(RuleMaxKeys, fmt.Errorf("%w: object has %d keys, maximum %d", ErrLimitExceeded, len(s.GetFields()), w.v.maxKeys))
+	}
+	for k, val := range s.GetFields() {
+		w.path = append(w.path, keySeg(k))
+		isPath, err := w.key(k)
+		if err == nil {
+			err = w.jsonValue(val, depth+1, isPath)
+		}
+		w.path = w.path[:len(w.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *protoWalker) listValue(l *structpb.ListValue, depth int) error {
+	if err := w.depth(depth); err != nil {
+		return err
+	}
+	if w.v.maxArrayLen > 0 && len(l.GetValues()) > w.v.maxArrayLen {
+		return w.fail(RuleMaxArrayLen, fmt.Errorf("%w: array has %d elements, maximum %d", ErrLimitExceeded, len(l.GetValues()), w.v.maxArrayLen))
+	}
+	for i, val := range l.GetValues() {
+		w.path = append(w.path, indexSeg(i))
+		err := w.jsonValue(val, depth+1, false)
+		w.path = w.path[:len(w.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *protoWalker) jsonValue(val *structpb.Value, depth int, isPath bool) error {
+	switch kind := val.GetKind().(type) {
+	case *structpb.Value_StructValue:
+		return w.structValue(kind.StructValue, depth)
+	case *structpb.Value_ListValue:
+		return w.listValue(kind.ListValue, depth)
+	case *structpb.Value_StringValue:
+		return w.str(kind.StringValue, isPath)
+	}
+	return nil
+}
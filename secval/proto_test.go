@@ -0,0 +1,88 @@
+package secval
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func mustStruct(t *testing.T, m map[string]any) *structpb.Struct {
+	t.Helper()
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		t.Fatalf("NewStruct: %v", err)
+	}
+	return s
+}
+
+func TestValidateMessageStructKeys(t *testing.T) {
+	v := New(Policy{})
+	clean := mustStruct(t, map[string]any{"name": "x", "tags": []any{"a", map[string]any{"k": 1}}})
+	if err := v.ValidateMessage(clean); err != nil {
+		t.Fatalf("clean struct rejected: %v", err)
+	}
+
+	bad := mustStruct(t, map[string]any{"user": map[string]any{"list": []any{map[string]any{"__proto__": 1}}}})
+	err := v.ValidateMessage(bad)
+	if !errors.Is(err, ErrDangerousKey) {
+		t.Fatalf("expected ErrDangerousKey, got %v", err)
+	}
+	var ve *Violation
+	if !errors.As(err, &ve) || ve.Field != "user.list[0].__proto__" {
+		t.Errorf("Field = %q, want user.list[0].__proto__", ve.Field)
+	}
+}
+
+func TestValidateMessageStructDepthMatchesJSON(t *testing.T) {
+	v := New(Policy{MaxDepth: 3})
+	ok := mustStruct(t, map[string]any{"a": map[string]any{"b": map[string]any{}}})
+	if err := v.ValidateMessage(ok); err != nil {
+		t.Errorf("depth 3 rejected: %v", err)
+	}
+	deep := mustStruct(t, map[string]any{"a": map[string]any{"b": map[string]any{"c": map[string]any{}}}})
+	if err := v.ValidateMessage(deep); !errors.Is(err, ErrNestingDepth) {
+		t.Errorf("expected ErrNestingDepth, got %v", err)
+	}
+}
+
+func TestValidateMessageMapFields(t *testing.T) {
+	v := New(Policy{MaxKeys: 2, MaxStringLen: 12})
+	info := &errdetails.ErrorInfo{Reason: "R", Metadata: map[string]string{"Constructor": "x"}}
+	if err := v.ValidateMessage(info); !errors.Is(err, ErrDangerousKey) {
+		t.Errorf("expected ErrDangerousKey for map key, got %v", err)
+	}
+	info = &errdetails.ErrorInfo{Metadata: map[string]string{"a": "1", "b": "2", "c": "3"}}
+	if err := v.ValidateMessage(info); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded for map size, got %v", err)
+	}
+	info = &errdetails.ErrorInfo{Reason: "much too long"}
+	if err := v.ValidateMessage(info); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded for string field, got %v", err)
+	}
+}
+
+func TestValidateMessageRepeatedAndSize(t *testing.T) {
+	br := &errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+		{Field: "a"}, {Field: "b"}, {Field: "c"},
+	}}
+	if err := New(Policy{MaxArrayLen: 2}).ValidateMessage(br); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded for list, got %v", err)
+	}
+	big := &errdetails.ErrorInfo{Reason: strings.Repeat("x", 100)}
+	if err := New(Policy{MaxSize: 50}).ValidateMessage(big); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded for size, got %v", err)
+	}
+	if err := New(Policy{}).ValidateMessage(br); err != nil {
+		t.Errorf("clean message rejected: %v", err)
+	}
+}
+
+func TestValidateMessagePathFields(t *testing.T) {
+	v := New(Policy{PathFields: []string{"domain"}})
+	if err := v.ValidateMessage(&errdetails.ErrorInfo{Domain: "../etc"}); !errors.Is(err, ErrUnsafePath) {
+		t.Errorf("expected ErrUnsafePath, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package secval
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrInvalidHeader is returned for header values containing line breaks or
+// control characters.
+var ErrInvalidHeader = errors.New("secval: invalid header value")
+
+// ValidateHeaderValue rejects header values that could split or smuggle a
+// header: carriage returns, line feeds, null bytes and other control
+// characters. Horizontal tabs are allowed, as in RFC 9110 field values.
+func ValidateHeaderValue(s string) error {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\r' || c == '\n':
+			return fmt.Errorf("%w: contains a line break", ErrInvalidHeader)
+		case c == 0:
+			return fmt.Errorf("%w: contains a null byte", ErrInvalidHeader)
+		case (c < 32 && c != '\t') || c == 127:
+			return fmt.Errorf("%w: contains a control character", ErrInvalidHeader)
+		}
+	}
+	return nil
+}
+
+// ValidateHeader checks every value in h with the default policy. See
+// Validator.ValidateHeader.
+func ValidateHeader(h http.Header) error {
+	return defaultValidator.ValidateHeader(h)
+}
+
+// ValidateQuery checks q with the default policy. See Validator.ValidateQuery.
+func ValidateQuery(q url.Values) error {
+	return defaultValidator.ValidateQuery(q)
+}
+
+// ValidateHeader checks every value in h with ValidateHeaderValue and
+// enforces MaxHeaderLen. Violations are *Violation values naming the header
+// and wrapping ErrInvalidHeader or ErrLimitExceeded.
+func (v *Validator) ValidateHeader(h http.Header) error {
+	for name, values := range h {
+		for _, val := range values {
+			if err := ValidateHeaderValue(val); err != nil {
+				return paramViolation(InHeader, name, RuleInvalidHeader, err)
+			}
+			if v.maxHeaderLen > 0 && len(val) > v.maxHeaderLen {
+				return paramViolation(InHeader, name, RuleMaxHeaderLen, fmt.Errorf("%w: value of %d bytes, maximum %d",
+					ErrLimitExceeded, len(val), v.maxHeaderLen))
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateQuery checks query parameters against the policy the same way as
+// JSON object keys: names are matched against the denied keys, MaxKeys caps
+// the number of distinct parameters, MaxArrayLen caps how often one
+// parameter repeats, MaxStringLen caps each name and value, and PathFields
+// values are checked with ValidatePath. Violations are *Violation values
+// wrapping ErrDangerousKey, ErrLimitExceeded or ErrUnsafePath.
+func (v *Validator) ValidateQuery(q url.Values) error {
+	if v.maxKeys > 0 && len(q) > v.maxKeys {
+		return &Violation{In: InQuery, Rule: RuleMaxKeys,
+			Err: fmt.Errorf("%w: query has %d parameters, maximum %d", ErrLimitExceeded, len(q), v.maxKeys)}
+	}
+	for key, values := range q {
+		if err := v.checkString(key); err != nil {
+			return paramViolation(InQuery, key, RuleMaxStringLen, err)
+		}
+		normalised := v.normalise(key)
+		if v.denied[normalised] {
+			return paramViolation(InQuery, key, RuleDangerousKey, fmt.Errorf("%w: %q", ErrDangerousKey, key))
+		}
+		if v.maxArrayLen > 0 && len(values) > v.maxArrayLen {
+			return paramViolation(InQuery, key, RuleMaxArrayLen, fmt.Errorf("%w: repeated %d times, maximum %d",
+				ErrLimitExceeded, len(values), v.maxArrayLen))
+		}
+		for _, val := range values {
+			if err := v.checkString(val); err != nil {
+				return paramViolation(InQuery, key, RuleMaxStringLen, err)
+			}
+			if v.pathFields[normalised] {
+				if err := ValidatePath(val); err != nil {
+					return paramViolation(InQuery, key, RuleUnsafePath, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// paramViolation returns a Violation for the named query parameter or
+// header.
+func paramViolation(in, name, rule string, err error) *Violation {
+	return &Violation{In: in, Field: name, Key: name, Rule: rule, Err: err}
+}
+
+// ValidateRequest checks r's headers and query parameters. It does not read
+// the body; use ValidateJSON for that.
+func (v *Validator) ValidateRequest(r *http.Request) error {
+	if err := v.ValidateHeader(r.Header); err != nil {
+		return err
+	}
+	return v.ValidateQuery(r.URL.Query())
+}
@@ -0,0 +1,97 @@
+package secval
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestValidateHeaderValueRejectsInjection(t *testing.T) {
+	for _, val := range []string{"a\r\nSet-Cookie: x=1", "a\nb", "a\rb", "a\x00b", "a\x1bb", "a\x7fb"} {
+		if err := ValidateHeaderValue(val); !errors.Is(err, ErrInvalidHeader) {
+			t.Errorf("%q: expected ErrInvalidHeader, got %v", val, err)
+		}
+	}
+	for _, val := range []string{"", "text/html; charset=utf-8", "a\tb", "caf\xc3\xa9"} {
+		if err := ValidateHeaderValue(val); err != nil {
+			t.Errorf("%q: unexpected error %v", val, err)
+		}
+	}
+}
+
+func TestValidateHeaderNamesHeaderAndEnforcesLength(t *testing.T) {
+	h := http.Header{"X-Note": {"ok", "bad\r\nvalue"}}
+	err := ValidateHeader(h)
+	if !errors.Is(err, ErrInvalidHeader) || !strings.Contains(err.Error(), `"X-Note"`) {
+		t.Fatalf("expected ErrInvalidHeader naming X-Note, got %v", err)
+	}
+
+	v := New(Policy{MaxHeaderLen: 8})
+	if err := v.ValidateHeader(http.Header{"X-Id": {"12345678"}}); err != nil {
+		t.Errorf("value at limit rejected: %v", err)
+	}
+	if err := v.ValidateHeader(http.Header{"X-Id": {"123456789"}}); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded, got %v", err)
+	}
+}
+
+func TestValidateQueryDangerousKeys(t *testing.T) {
+	for _, raw := range []string{"__proto__=1", "Constructor=x", "a=1&prototype=2"} {
+		q, _ := url.ParseQuery(raw)
+		if err := ValidateQuery(q); !errors.Is(err, ErrDangerousKey) {
+			t.Errorf("%s: expected ErrDangerousKey, got %v", raw, err)
+		}
+	}
+	q, _ := url.ParseQuery("name=x&page=2")
+	if err := ValidateQuery(q); err != nil {
+		t.Errorf("clean query rejected: %v", err)
+	}
+}
+
+func TestValidateQueryLimits(t *testing.T) {
+	v := New(Policy{MaxKeys: 2, MaxArrayLen: 2, MaxStringLen: 5, PathFields: []string{"file"}})
+	tests := []struct {
+		raw  string
+		want error
+	}{
+		{"a=1&b=2", nil},
+		{"a=1&b=2&c=3", ErrLimitExceeded},
+		{"a=1&a=2&a=3", ErrLimitExceeded},
+		{"a=123456", ErrLimitExceeded},
+		{"abcdef=1", ErrLimitExceeded},
+		{"file=../x", ErrUnsafePath},
+		{"file=a/b", nil},
+	}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.raw)
+		err := v.ValidateQuery(q)
+		if tt.want == nil && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.raw, err)
+		} else if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.raw, tt.want, err)
+		}
+	}
+}
+
+func TestValidatorValidateRequest(t *testing.T) {
+	v := New(Policy{})
+	r := httptest.NewRequest("GET", "/?q=ok", nil)
+	r.Header.Set("Accept", "application/json")
+	if err := v.ValidateRequest(r); err != nil {
+		t.Fatalf("clean request rejected: %v", err)
+	}
+
+	r = httptest.NewRequest("GET", "/?__proto__=1", nil)
+	if err := v.ValidateRequest(r); !errors.Is(err, ErrDangerousKey) {
+		t.Errorf("expected ErrDangerousKey, got %v", err)
+	}
+
+	r = httptest.NewRequest("GET", "/", nil)
+	r.Header["X-Evil"] = []string{"a\nb"}
+	if err := v.ValidateRequest(r); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("expected ErrInvalidHeader, got %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package secval
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// scanner walks a JSON document token by token, enforcing a Validator's
+// policy and tracking the path to the current value. It advances one step at
+// a time so Decode can unmarshal from the tokens as they are validated.
+type scanner struct {
+	v     *Validator
+	dec   *json.Decoder
+	path  []pathSeg
+	stack []frame // open objects and arrays, innermost last
+	done  bool    // the top-level value is complete
+
+	emit bool   // re-encode consumed tokens into out
+	out  []byte // JSON text of the tokens consumed so far, for Decode
+}
+
+// frame is an open object or array and the number of members seen in it.
+type frame struct {
+	object bool
+	n      int
+}
+
+func newScanner(v *Validator, data []byte) *scanner {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return &scanner{v: v, dec: dec}
+}
+
+// scan validates data in one pass over its tokens.
+func (v *Validator) scan(data []byte) error {
+	s := newScanner(v, data)
+	for {
+		if err := s.step(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// step consumes the next value, member or closing delimiter. It returns
+// io.EOF once the top-level value is complete and nothing follows it.
+func (s *scanner) step() error {
+	if s.done {
+		if _, err := s.dec.Token(); err != io.EOF {
+			return s.fail(RuleInvalidJSON, fmt.Errorf("%w: unexpected data after top-level value", ErrInvalidJSON))
+		}
+		return io.EOF
+	}
+	if len(s.stack) == 0 {
+		return s.value(false)
+	}
+	f := &s.stack[len(s.stack)-1]
+	if !s.dec.More() {
+		tok, err := s.dec.Token()
+		if err != nil {
+			return s.fail(RuleInvalidJSON, fmt.Errorf("%w: %v", ErrInvalidJSON, err))
+		}
+		s.stack = s.stack[:len(s.stack)-1]
+		s.write(tok)
+		s.valueDone()
+		return nil
+	}
+	if f.n > 0 {
+		s.writeByte(',')
+	}
+	if !f.object {
+		if s.v.maxArrayLen > 0 && f.n >= s.v.maxArrayLen {
+			return s.fail(RuleMaxArrayLen, fmt.Errorf("%w: array has more than %d elements", ErrLimitExceeded, s.v.maxArrayLen))
+		}
+		s.path = append(s.path, indexSeg(f.n))
+		f.n++
+		return s.value(false)
+	}
+
+	tok, err := s.dec.Token()
+	if err != nil {
+		return s.fail(RuleInvalidJSON, fmt.Errorf("%w: %v", ErrInvalidJSON, err))
+	}
+	key := tok.(string)
+	f.n++
+	if s.v.maxKeys > 0 && f.n > s.v.maxKeys {
+		return s.fail(RuleMaxKeys, fmt.Errorf("%w: object has more than %d keys", ErrLimitExceeded, s.v.maxKeys))
+	}
+	s.path = append(s.path, keySeg(key))
+	if err := s.v.checkString(key); err != nil {
+		return s.fail(RuleMaxStringLen, err)
+	}
+	normalised := s.v.normalise(key)
+	if s.v.denied[normalised] {
+		return s.fail(RuleDangerousKey, fmt.Errorf("%w: %q", ErrDangerousKey, key))
+	}
+	s.write(key)
+	s.writeByte(':')
+	return s.value(s.v.pathFields[normalised])
+}
+
+// value consumes one value, opening a frame for an object or array. isPath
+// applies ValidatePath to a string value.
+func (s *scanner) value(isPath bool) error {
+	tok, err := s.dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return s.fail(RuleInvalidJSON, fmt.Errorf("%w: %v", ErrInvalidJSON, err))
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if depth := len(s.stack); depth >= s.v.maxDepth {
+			return s.fail(RuleMaxDepth, fmt.Errorf("%w: depth %d exceeds maximum %d", ErrNestingDepth, depth, s.v.maxDepth))
+		}
+		s.stack = append(s.stack, frame{object: t == '{'})
+		s.write(tok)
+		return nil
+	case string:
+		if err := s.v.checkString(t); err != nil {
+			return s.fail(RuleMaxStringLen, err)
+		}
+		if isPath {
+			if err := ValidatePath(t); err != nil {
+				return s.fail(RuleUnsafePath, err)
+			}
+		}
+	}
+	s.write(tok)
+	s.valueDone()
+	return nil
+}
+
+// valueDone leaves a completed value: it drops the value's path segment, or
+// marks the document done if it was the top-level value.
+func (s *scanner) valueDone() {
+	if len(s.stack) == 0 {
+		s.done = true
+		return
+	}
+	s.path = s.path[:len(s.path)-1]
+}
+
+// write appends the JSON encoding of tok when the scanner is emitting.
+func (s *scanner) write(tok json.Token) {
+	if !s.emit {
+		return
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		s.out = append(s.out, t.String()...)
+	case json.Number:
+		s.out = append(s.out, t...)
+	case string:
+		b, _ := json.Marshal(t)
+		s.out = append(s.out, b...)
+	case bool:
+		s.out = strconv.AppendBool(s.out, t)
+	case nil:
+		s.out = append(s.out, "null"...)
+	}
+}
+
+func (s *scanner) writeByte(c byte) {
+	if s.emit {
+		s.out = append(s.out, c)
+	}
+}
+
+func (s *scanner) fail(rule string, err error) error {
+	return bodyViolation(s.path, rule, err)
+}
+
+// Read implements io.Reader over the validated document: each call advances
+// the scan and returns the JSON text of the tokens it accepted, so a decoder
+// reading from it never sees input past a violation.
+func (s *scanner) Read(p []byte) (int, error) {
+	for len(s.out) == 0 {
+		if err := s.step(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.out)
+	s.out = s.out[n:]
+	return n, nil
+}
+
+// Decode validates body against v and unmarshals it into a T in a single
+// pass: the unmarshal reads the tokens of the validating scan as it accepts
+// them, so body is tokenised once and a violation stops the decode before
+// any of T is filled in. A nil v uses the zero Policy. Failures, including
+// unmarshal type mismatches, are returned as a *Violation wrapping a
+// sentinel error.
+func Decode[T any](body []byte, v *Validator) (T, error) {
+	var out, zero T
+	if v == nil {
+		v = defaultValidator
+	}
+	if v.maxSize > 0 && len(body) > v.maxSize {
+		return zero, bodyViolation(nil, RuleMaxSize, fmt.Errorf("%w: payload is %d bytes, maximum %d", ErrLimitExceeded, len(body), v.maxSize))
+	}
+	s := newScanner(v, body)
+	s.emit = true
+	err := json.NewDecoder(s).Decode(&out)
+	for err == nil {
+		err = s.step() // check what follows the value
+	}
+	if err == io.EOF {
+		return out, nil
+	}
+	var violation *Violation
+	if errors.As(err, &violation) {
+		return zero, err
+	}
+	var path []pathSeg
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		for k := range strings.SplitSeq(typeErr.Field, ".") {
+			path = append(path, keySeg(k))
+		}
+	}
+	return zero, bodyViolation(path, RuleInvalidJSON, fmt.Errorf("%w: %v", ErrInvalidJSON, err))
+}
+
+// DecodeReader reads r and decodes it with Decode. When the policy sets
+// MaxSize, at most MaxSize+1 bytes are read, so an oversized body is
+// rejected without buffering all of it.
+func DecodeReader[T any](r io.Reader, v *Validator) (T, error) {
+	if v == nil {
+		v = defaultValidator
+	}
+	if v.maxSize > 0 {
+		r = io.LimitReader(r, int64(v.maxSize)+1)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		var out T
+		return out, fmt.Errorf("secval: read body: %w", err)
+	}
+	return Decode[T](body, v)
+}
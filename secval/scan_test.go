@@ -0,0 +1,130 @@
+package secval
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateJSONReportsField(t *testing.T) {
+	tests := []struct {
+		doc   string
+		field string
+		want  error
+	}{
+		{`{"user": {"__proto__": {}}}`, "user.__proto__", ErrDangerousKey},
+		{`{"items": [{"ok": 1}, {"constructor": 1}]}`, "items[1].constructor", ErrDangerousKey},
+		{`{"__proto__": 1}`, "__proto__", ErrDangerousKey},
+		{`{"a": 1} {"b": 2}`, "", ErrInvalidJSON},
+		{`{"a": `, "a", ErrInvalidJSON},
+	}
+	for _, tt := range tests {
+		err := ValidateJSON([]byte(tt.doc))
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.doc, tt.want, err)
+			continue
+		}
+		var ve *Violation
+		if !errors.As(err, &ve) {
+			t.Fatalf("%s: expected *Violation, got %T", tt.doc, err)
+		}
+		if ve.Field != tt.field {
+			t.Errorf("%s: Field = %q, want %q", tt.doc, ve.Field, tt.field)
+		}
+	}
+}
+
+func TestValidateJSONCountsDuplicateKeys(t *testing.T) {
+	v := New(Policy{MaxKeys: 2})
+	if err := v.ValidateJSON([]byte(`{"a": 1, "a": 2, "a": 3}`)); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded for repeated keys, got %v", err)
+	}
+}
+
+type decodeTarget struct {
+	Name string   `json:"name"`
+	Age  int      `json:"age"`
+	Tags []string `json:"tags"`
+}
+
+func TestDecode(t *testing.T) {
+	got, err := Decode[decodeTarget]([]byte(`{"name": "Ada", "age": 36, "tags": ["math"]}`), nil)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got.Name != "Ada" || got.Age != 36 || len(got.Tags) != 1 {
+		t.Errorf("Decode = %+v", got)
+	}
+}
+
+func TestDecodeRejectsBeforeUnmarshal(t *testing.T) {
+	got, err := Decode[map[string]any]([]byte(`{"name": "x", "__proto__": {"admin": true}}`), nil)
+	if !errors.Is(err, ErrDangerousKey) {
+		t.Fatalf("expected ErrDangerousKey, got %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected zero value on failure, got %v", got)
+	}
+}
+
+func TestDecodeFromValidatedTokens(t *testing.T) {
+	type target struct {
+		When  time.Time         `json:"when"`
+		Score float64           `json:"score"`
+		Big   int64             `json:"big"`
+		Note  string            `json:"note"`
+		Meta  map[string]any    `json:"meta"`
+		Rows  [][]bool          `json:"rows"`
+		Extra *json.RawMessage  `json:"extra"`
+		Empty map[string]string `json:"empty"`
+	}
+	doc := `{"when": "2024-05-01T10:00:00Z", "score": 1.5e2, "big": 9007199254740993,
+		"note": "a \"quoted\" <tag> \u00e9", "meta": {"n": null, "l": [1, "x"]},
+		"rows": [[true, false], []], "extra": {"k":[1,2]}, "empty": {}}`
+	got, err := Decode[target]([]byte(doc), nil)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	var want target
+	if err := json.Unmarshal([]byte(doc), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode = %+v\nwant %+v", got, want)
+	}
+
+	if n, err := Decode[int]([]byte(`42`), nil); err != nil || n != 42 {
+		t.Errorf("Decode(42) = %d, %v", n, err)
+	}
+	if _, err := Decode[map[string]int]([]byte(`{"a": 1} {"b": 2}`), nil); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("trailing data: expected ErrInvalidJSON, got %v", err)
+	}
+}
+
+func TestDecodeTypeMismatchReportsField(t *testing.T) {
+	_, err := Decode[decodeTarget]([]byte(`{"name": "Ada", "age": "old"}`), nil)
+	var ve *Violation
+	if !errors.As(err, &ve) || !errors.Is(err, ErrInvalidJSON) {
+		t.Fatalf("expected *Violation wrapping ErrInvalidJSON, got %v", err)
+	}
+	if ve.Field != "age" {
+		t.Errorf("Field = %q, want age", ve.Field)
+	}
+}
+
+func TestDecodeReaderEnforcesMaxSize(t *testing.T) {
+	v := New(Policy{MaxSize: 16})
+	if _, err := DecodeReader[decodeTarget](strings.NewReader(`{"name": "Ada"}`), v); err != nil {
+		t.Errorf("small body rejected: %v", err)
+	}
+	body := strings.NewReader(`{"name": "` + strings.Repeat("x", 1<<20) + `"}`)
+	if _, err := DecodeReader[decodeTarget](body, v); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded, got %v", err)
+	}
+	if body.Len() < 1<<19 {
+		t.Errorf("DecodeReader read %d bytes past the limit", (1<<20)-body.Len())
+	}
+}
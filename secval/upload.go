@@ -0,0 +1,149 @@
+package secval
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrUploadRejected is returned by ValidateUpload for files whose name or
+// content type is not allowed.
+var ErrUploadRejected = errors.New("secval: upload rejected")
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// UploadPolicy configures ValidateUpload. The zero UploadPolicy accepts any
+// named file and only sanitises its filename.
+type UploadPolicy struct {
+	// MaxSize caps the file size in bytes. Zero means unlimited. It is
+	// enforced while the Upload is read.
+	MaxSize int64
+	// AllowedMIME lists accepted media types, such as "image/png", or
+	// wildcards such as "image/*". Empty accepts any type.
+	AllowedMIME []string
+	// MagicByteCheck determines the content type by sniffing the first
+	// bytes of the file instead of trusting the declared Content-Type.
+	MagicByteCheck bool
+	// FilenameRules constrains the client-supplied filename.
+	FilenameRules FilenameRules
+}
+
+// FilenameRules constrains upload filenames.
+type FilenameRules struct {
+	// AllowedExtensions lists accepted extensions, such as ".pdf",
+	// matched case-insensitively. Empty accepts any extension.
+	AllowedExtensions []string
+	// MaxLength caps the length in bytes of the original filename. Zero
+	// means unlimited.
+	MaxLength int
+	// URLSafe sanitises with SafeFilenameURL instead of SafeFilename.
+	URLSafe bool
+}
+
+// Upload is a validated file. Read it to consume the content; reads fail
+// with ErrLimitExceeded once more than MaxSize bytes have been returned.
+type Upload struct {
+	// Filename is the sanitised filename, safe to use as a path segment.
+	Filename string
+	// ContentType is the sniffed media type with MagicByteCheck, and the
+	// declared one otherwise, without parameters.
+	ContentType string
+
+	r io.Reader
+}
+
+// Read reads the file content.
+func (u *Upload) Read(p []byte) (int, error) { return u.r.Read(p) }
+
+// ValidateUpload checks a multipart file part against p and returns it as an
+// Upload. Filename and content type are checked up front; with
+// MagicByteCheck the first 512 bytes are read and sniffed with
+// http.DetectContentType. Violations wrap ErrUploadRejected or
+// ErrLimitExceeded.
+func ValidateUpload(part *multipart.Part, p UploadPolicy) (*Upload, error) {
+	name := part.FileName()
+	if name == "" {
+		return nil, fmt.Errorf("%w: part %q is not a file", ErrUploadRejected, part.FormName())
+	}
+	if max := p.FilenameRules.MaxLength; max > 0 && len(name) > max {
+		return nil, fmt.Errorf("%w: filename of %d bytes, maximum %d", ErrLimitExceeded, len(name), max)
+	}
+	if exts := p.FilenameRules.AllowedExtensions; len(exts) > 0 {
+		ext := filepath.Ext(name)
+		if !slices.ContainsFunc(exts, func(e string) bool { return strings.EqualFold(e, ext) }) {
+			return nil, fmt.Errorf("%w: extension %q is not allowed", ErrUploadRejected, ext)
+		}
+	}
+
+	var r io.Reader = part
+	contentType := part.Header.Get("Content-Type")
+	if p.MagicByteCheck {
+		head := make([]byte, sniffLen)
+		n, err := io.ReadFull(part, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("secval: read upload: %w", err)
+		}
+		head = head[:n]
+		contentType = http.DetectContentType(head)
+		r = io.MultiReader(bytes.NewReader(head), part)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+	if len(p.AllowedMIME) > 0 && !mimeAllowed(p.AllowedMIME, mediaType) {
+		return nil, fmt.Errorf("%w: content type %q is not allowed", ErrUploadRejected, mediaType)
+	}
+
+	u := &Upload{Filename: SafeFilename(name), ContentType: mediaType, r: r}
+	if p.FilenameRules.URLSafe {
+		u.Filename = SafeFilenameURL(name)
+	}
+	if p.MaxSize > 0 {
+		u.r = &limitedReader{r: r, remaining: p.MaxSize}
+	}
+	return u, nil
+}
+
+// mimeAllowed reports whether mediaType matches an entry in allowed, either
+// exactly or by a "type/*" wildcard.
+func mimeAllowed(allowed []string, mediaType string) bool {
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if a == mediaType || (strings.HasSuffix(a, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(a, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitedReader fails with ErrLimitExceeded once more than remaining bytes
+// are available, unlike io.LimitReader, which silently truncates.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, fmt.Errorf("%w: upload exceeds maximum size", ErrLimitExceeded)
+	}
+	// Read one byte past the limit to detect an oversized file.
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), fmt.Errorf("%w: upload exceeds maximum size", ErrLimitExceeded)
+	}
+	return n, err
+}
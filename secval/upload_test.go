@@ -0,0 +1,129 @@
+package secval
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// pngHeader is the PNG signature followed by enough bytes to sniff.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// filePart builds a multipart body with one file part and returns it.
+func filePart(t *testing.T, filename, contentType string, content []byte) *multipart.Part {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="file"; filename="`+filename+`"`)
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	w, err := mw.CreatePart(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(content)
+	mw.Close()
+
+	part, err := multipart.NewReader(&buf, mw.Boundary()).NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return part
+}
+
+func TestValidateUploadAcceptsAllowedFile(t *testing.T) {
+	p := UploadPolicy{
+		AllowedMIME:    []string{"image/*"},
+		MagicByteCheck: true,
+		FilenameRules:  FilenameRules{AllowedExtensions: []string{".png"}},
+	}
+	u, err := ValidateUpload(filePart(t, "My Photo.PNG", "application/octet-stream", pngHeader), p)
+	if err != nil {
+		t.Fatalf("ValidateUpload: %v", err)
+	}
+	if u.ContentType != "image/png" {
+		t.Errorf("ContentType = %q, want image/png", u.ContentType)
+	}
+	if u.Filename != "My Photo.PNG" {
+		t.Errorf("Filename = %q", u.Filename)
+	}
+	got, err := io.ReadAll(u)
+	if err != nil || !bytes.Equal(got, pngHeader) {
+		t.Errorf("content = %q, err = %v", got, err)
+	}
+}
+
+func TestValidateUploadMagicBytesOverrideDeclaredType(t *testing.T) {
+	p := UploadPolicy{AllowedMIME: []string{"image/png"}, MagicByteCheck: true}
+	_, err := ValidateUpload(filePart(t, "evil.png", "image/png", []byte("<html><script>alert(1)</script>")), p)
+	if !errors.Is(err, ErrUploadRejected) {
+		t.Fatalf("expected ErrUploadRejected, got %v", err)
+	}
+}
+
+func TestValidateUploadDeclaredTypeWithoutSniffing(t *testing.T) {
+	p := UploadPolicy{AllowedMIME: []string{"application/pdf"}}
+	if _, err := ValidateUpload(filePart(t, "a.pdf", "application/pdf; name=a", []byte("%PDF-")), p); err != nil {
+		t.Errorf("declared pdf rejected: %v", err)
+	}
+	if _, err := ValidateUpload(filePart(t, "a.pdf", "text/plain", []byte("%PDF-")), p); !errors.Is(err, ErrUploadRejected) {
+		t.Errorf("expected ErrUploadRejected, got %v", err)
+	}
+}
+
+func TestValidateUploadFilenameRules(t *testing.T) {
+	p := UploadPolicy{FilenameRules: FilenameRules{AllowedExtensions: []string{".txt"}, MaxLength: 12, URLSafe: true}}
+	if _, err := ValidateUpload(filePart(t, "run.exe", "", nil), p); !errors.Is(err, ErrUploadRejected) {
+		t.Errorf("expected ErrUploadRejected for extension, got %v", err)
+	}
+	if _, err := ValidateUpload(filePart(t, "much-too-long.txt", "", nil), p); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded for filename, got %v", err)
+	}
+	u, err := ValidateUpload(filePart(t, "My Notes.txt", "", nil), p)
+	if err != nil {
+		t.Fatalf("ValidateUpload: %v", err)
+	}
+	if u.Filename != "my-notes.txt" {
+		t.Errorf("Filename = %q, want my-notes.txt", u.Filename)
+	}
+}
+
+func TestValidateUploadRejectsFormField(t *testing.T) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mw.WriteField("name", "value")
+	mw.Close()
+	part, _ := multipart.NewReader(&buf, mw.Boundary()).NextPart()
+	if _, err := ValidateUpload(part, UploadPolicy{}); !errors.Is(err, ErrUploadRejected) {
+		t.Errorf("expected ErrUploadRejected, got %v", err)
+	}
+}
+
+func TestValidateUploadMaxSize(t *testing.T) {
+	p := UploadPolicy{MaxSize: 10, MagicByteCheck: true}
+	u, err := ValidateUpload(filePart(t, "a.txt", "", []byte("0123456789")), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(u); err != nil || len(got) != 10 {
+		t.Errorf("file at limit: %d bytes, err = %v", len(got), err)
+	}
+
+	u, err = ValidateUpload(filePart(t, "a.txt", "", []byte(strings.Repeat("x", 4096))), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(u)
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+	if len(got) > 10 {
+		t.Errorf("read %d bytes past the limit", len(got))
+	}
+}
@@ -0,0 +1,134 @@
+package secval
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ai8future/chassis-go/v11/errors"
+)
+
+// Rules name the policy check a Violation failed.
+const (
+	RuleDangerousKey  = "dangerous_key"
+	RuleMaxDepth      = "max_depth"
+	RuleMaxKeys       = "max_keys"
+	RuleMaxArrayLen   = "max_array_len"
+	RuleMaxStringLen  = "max_string_len"
+	RuleMaxSize       = "max_size"
+	RuleMaxHeaderLen  = "max_header_len"
+	RuleUnsafePath    = "unsafe_path"
+	RuleInvalidJSON   = "invalid_json"
+	RuleInvalidHeader = "invalid_header"
+)
+
+// Locations of a Violation.
+const (
+	InBody   = "body"
+	InQuery  = "query"
+	InHeader = "header"
+)
+
+// Violation reports which part of the input failed which rule, so callers
+// can tell clients exactly what was rejected. Err wraps one of the sentinel
+// errors, so errors.Is works as for a plain error.
+type Violation struct {
+	// In is where the violation occurred: InBody, InQuery or InHeader.
+	In string
+	// Field is the dotted path to the offending body value, such as
+	// "user.tags[2]", or the query parameter or header name. It is empty
+	// for the body root.
+	Field string
+	// Pointer is the RFC 6901 JSON pointer to the offending body value,
+	// such as "/user/tags/2". It is empty for the root and outside the body.
+	Pointer string
+	// Rule is the check that failed, one of the Rule constants.
+	Rule string
+	// Key is the object key, map key, query parameter or header name
+	// closest to the violation, if any.
+	Key string
+	Err error
+}
+
+// ValidationError is the former name of Violation.
+//
+// Deprecated: Use Violation.
+type ValidationError = Violation
+
+func (v *Violation) Error() string {
+	if v.Field == "" {
+		return v.Err.Error()
+	}
+	switch v.In {
+	case InQuery:
+		return fmt.Sprintf("%v (query parameter %q)", v.Err, v.Field)
+	case InHeader:
+		return fmt.Sprintf("%v (header %q)", v.Err, v.Field)
+	}
+	return fmt.Sprintf("%v (field %q)", v.Err, v.Field)
+}
+
+func (v *Violation) Unwrap() error { return v.Err }
+
+// ServiceError converts an error returned by a Validator to a ServiceError
+// for either transport. A *Violation of RuleMaxSize becomes a 413, any other
+// a 400 (InvalidArgument) whose details carry its location, rule, field,
+// key and pointer; the field also becomes a google.rpc BadRequest field
+// violation on gRPC. Other errors become a plain 400.
+func ServiceError(err error) *errors.ServiceError {
+	var viol *Violation
+	if !stderrors.As(err, &viol) {
+		return errors.ValidationError(err.Error())
+	}
+	if viol.Rule == RuleMaxSize {
+		return errors.PayloadTooLargeError(err.Error())
+	}
+	details := map[string]any{"in": viol.In, "rule": viol.Rule}
+	if viol.Field != "" {
+		details["field"] = viol.Field
+	}
+	if viol.Key != "" {
+		details["key"] = viol.Key
+	}
+	if viol.Pointer != "" {
+		details["pointer"] = viol.Pointer
+	}
+	return errors.ValidationError(err.Error()).WithDetails(details)
+}
+
+// pathSeg is one step into a document: an object or map key, or a list
+// index.
+type pathSeg struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func keySeg(k string) pathSeg { return pathSeg{key: k} }
+func indexSeg(i int) pathSeg  { return pathSeg{index: i, isIndex: true} }
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// bodyViolation returns a Violation at path within the body.
+func bodyViolation(path []pathSeg, rule string, err error) *Violation {
+	v := &Violation{In: InBody, Rule: rule, Err: err}
+	var field, pointer strings.Builder
+	for _, seg := range path {
+		if seg.isIndex {
+			i := strconv.Itoa(seg.index)
+			field.WriteString("[" + i + "]")
+			pointer.WriteString("/" + i)
+			continue
+		}
+		if field.Len() > 0 {
+			field.WriteByte('.')
+		}
+		field.WriteString(seg.key)
+		pointer.WriteString("/" + pointerEscaper.Replace(seg.key))
+		v.Key = seg.key
+	}
+	v.Field = field.String()
+	v.Pointer = pointer.String()
+	return v
+}
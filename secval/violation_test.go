@@ -0,0 +1,80 @@
+package secval
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestViolationLocatesBodyValue(t *testing.T) {
+	v := New(Policy{MaxStringLen: 4, DeniedKeys: []string{"bad"}})
+	tests := []struct {
+		doc     string
+		pointer string
+		rule    string
+		key     string
+	}{
+		{`{"user": {"tags": ["ok", {"bad": 1}]}}`, "/user/tags/1/bad", RuleDangerousKey, "bad"},
+		{`{"a/b": {"c~d": "toolong"}}`, "/a~1b/c~0d", RuleMaxStringLen, "c~d"},
+		{`[["abcde"]]`, "/0/0", RuleMaxStringLen, ""},
+		{`{"a": [1,]}`, "/a/1", RuleInvalidJSON, "a"},
+	}
+	for _, tt := range tests {
+		var viol *Violation
+		if err := v.ValidateJSON([]byte(tt.doc)); !errors.As(err, &viol) {
+			t.Fatalf("%s: expected *Violation, got %v", tt.doc, err)
+		}
+		if viol.In != InBody || viol.Pointer != tt.pointer || viol.Rule != tt.rule || viol.Key != tt.key {
+			t.Errorf("%s: got in=%q pointer=%q rule=%q key=%q, want pointer=%q rule=%q key=%q",
+				tt.doc, viol.In, viol.Pointer, viol.Rule, viol.Key, tt.pointer, tt.rule, tt.key)
+		}
+	}
+}
+
+func TestViolationForHeaderAndQuery(t *testing.T) {
+	var viol *Violation
+	err := ValidateHeader(http.Header{"X-Name": {"a\r\nb"}})
+	if !errors.As(err, &viol) || viol.In != InHeader || viol.Key != "X-Name" || viol.Rule != RuleInvalidHeader {
+		t.Errorf("header violation = %+v", viol)
+	}
+	if err.Error() != `secval: invalid header value: contains a line break (header "X-Name")` {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	err = ValidateQuery(url.Values{"__proto__": {"1"}})
+	if !errors.As(err, &viol) || viol.In != InQuery || viol.Key != "__proto__" || viol.Rule != RuleDangerousKey {
+		t.Errorf("query violation = %+v", viol)
+	}
+	if viol.Pointer != "" {
+		t.Errorf("query violation Pointer = %q, want empty", viol.Pointer)
+	}
+}
+
+func TestValidationErrorAlias(t *testing.T) {
+	var ve *ValidationError
+	if err := ValidateJSON([]byte(`{"__proto__": 1}`)); !errors.As(err, &ve) || ve.Rule != RuleDangerousKey {
+		t.Errorf("expected *ValidationError alias to match, got %v", err)
+	}
+}
+
+func TestServiceError(t *testing.T) {
+	svcErr := ServiceError(ValidateJSON([]byte(`{"items": [{"constructor": 1}]}`)))
+	if svcErr.HTTPCode != http.StatusBadRequest {
+		t.Errorf("HTTPCode = %d, want 400", svcErr.HTTPCode)
+	}
+	want := map[string]any{"in": InBody, "rule": RuleDangerousKey, "field": "items[0].constructor", "key": "constructor", "pointer": "/items/0/constructor"}
+	for k, v := range want {
+		if svcErr.Details[k] != v {
+			t.Errorf("Details[%q] = %v, want %v", k, svcErr.Details[k], v)
+		}
+	}
+
+	big := New(Policy{MaxSize: 4})
+	if svcErr := ServiceError(big.ValidateJSON([]byte(`"toolong"`))); svcErr.HTTPCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("max_size HTTPCode = %d, want 413", svcErr.HTTPCode)
+	}
+	if svcErr := ServiceError(errors.New("plain")); svcErr.HTTPCode != http.StatusBadRequest || svcErr.Details != nil {
+		t.Errorf("plain error = %d %v, want a bare 400", svcErr.HTTPCode, svcErr.Details)
+	}
+}